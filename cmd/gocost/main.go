@@ -1,23 +1,35 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/madalinpopa/gocost/internal/app"
+	"github.com/madalinpopa/gocost/internal/cli"
 	"github.com/madalinpopa/gocost/internal/config"
 	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/demo"
+	"github.com/madalinpopa/gocost/internal/domain"
 	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/madalinpopa/gocost/internal/ui"
 	"github.com/spf13/viper"
 )
 
 // version will be set during build time
 var version = "dev"
 
+// demoCommand starts the interface on sample data instead of the data file.
+const demoCommand = "demo"
+
 func main() {
 	versionFlag := flag.Bool("version", false, "Print version information and exit")
+	profileFlag := flag.String("profile", "", "Open the data file of a profile of the config file")
 	flag.Parse()
 
 	// If version flag is set, print version and exit
@@ -34,9 +46,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Subcommands run non-interactively, so they never prompt for a currency.
+	args := flag.Args()
 	var selectedCurrency string
 	if !exists {
-		selectedCurrency = config.PromptForCurrency()
+		if len(args) > 0 {
+			selectedCurrency = config.DefaultCurrency
+		} else {
+			selectedCurrency = config.PromptForCurrency()
+		}
 	}
 
 	if err := config.LoadConfig(selectedCurrency, configFilePath); err != nil {
@@ -46,26 +64,271 @@ func main() {
 		os.Exit(1)
 	}
 
-	dataFilePath := viper.GetString(config.DataFileField)
-	repo, err := data.NewJsonRepository(dataFilePath, selectedCurrency)
+	if len(args) > 0 && args[0] == demoCommand {
+		if err := runDemo(); err != nil {
+			if _, err := fmt.Fprintf(os.Stderr, "Error running demo: %v\n", err); err != nil {
+				os.Exit(2)
+			}
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	profile, err := config.GetProfile(*profileFlag)
+	if err != nil {
+		if _, err := fmt.Fprintf(os.Stderr, "Error: %v\n", err); err != nil {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	if len(args) > 0 && cli.IsRecoveryCommand(args[0]) {
+		config.UseProfile(profile)
+		exitWithCommandError(runRecovery(profile, args))
+	}
+
+	repo, backups, dataFilePath, err := openRepository(profile, len(args) == 0)
 	if err != nil {
 		if _, err := fmt.Fprintf(os.Stderr, "Error loading data from %s: %v", dataFilePath, err); err != nil {
 			os.Exit(2)
 		}
 		os.Exit(1)
 	}
+	config.UseProfile(profile)
 
-	categorySvc := service.NewCategoryService(repo)
-	groupSvc := service.NewGroupService(repo)
-	incomeSvc := service.NewIncomeService(repo)
+	if len(args) > 0 {
+		s := newServices(repo, fileJournal(repo, dataFilePath))
+		c := cli.New(s.category, s.group, s.income, s.rate, s.recurring, s.month, s.journal, dataFilePath, backups, os.Stdout)
+		if jsonRepo, ok := repo.(*data.JsonRepository); ok {
+			c.SetEncrypter(jsonRepo)
+		}
+		err := c.Run(args)
+		closeRepository(repo)
+		exitWithCommandError(err)
+	}
 
-	a := app.New(categorySvc, groupSvc, incomeSvc, dataFilePath)
+	a, closeSession := newApp(repo, dataFilePath)
+	a = a.WithProfiles(profile.Name, switchProfile, closeSession)
 
 	p := tea.NewProgram(a, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	// The profile open at the end may not be the one opened at startup
+	if final, ok := final.(app.App); ok {
+		final.Close()
+	} else {
+		closeSession()
+	}
+	if err != nil {
 		if _, err := fmt.Fprintf(os.Stderr, "Error running program: %v\n", err); err != nil {
 			os.Exit(2)
 		}
 		os.Exit(1)
 	}
 }
+
+// exitWithCommandError exits with the status of a subcommand that returned err.
+func exitWithCommandError(err error) {
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		if _, err := fmt.Fprintf(os.Stderr, "Error: %v\n", err); err != nil {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// runRecovery runs a command that works on the data file of profile without
+// loading it, so that a data file that cannot be read is still checked and
+// restored from its backups.
+func runRecovery(profile config.Profile, args []string) error {
+	dataFilePath, backups := profile.DatabaseFilename, (*data.Backups)(nil)
+	if profile.Storage != config.StorageSQLite {
+		dataFilePath = profile.DataFilename
+		backups = jsonBackups(dataFilePath)
+	}
+
+	c := cli.New(nil, nil, nil, nil, nil, nil, nil, dataFilePath, backups, os.Stdout)
+	if backups != nil {
+		c.SetDoctor(func() (cli.Doctor, error) {
+			repo, _, _, err := openRepository(profile, false)
+			if err != nil {
+				return nil, err
+			}
+			return repo.(*data.JsonRepository), nil
+		})
+	}
+	return c.Run(args)
+}
+
+// services holds the services working on the data of a profile.
+type services struct {
+	category  *service.CategoryService
+	group     *service.GroupService
+	income    *service.IncomeService
+	rate      *service.RateService
+	recurring *service.RecurringService
+	month     *service.MonthService
+	journal   *service.JournalService
+}
+
+// newServices creates the services working on repo. Every change made through
+// them is recorded in journal.
+func newServices(repo data.Repository, journal *data.Journal) services {
+	journaled := data.NewJournaledRepository(repo, journal)
+
+	return services{
+		category:  service.NewCategoryService(journaled),
+		group:     service.NewGroupService(journaled),
+		income:    service.NewIncomeService(journaled),
+		rate:      service.NewRateService(journaled),
+		recurring: service.NewRecurringService(journaled, journaled, journaled),
+		month:     service.NewMonthService(journaled),
+		journal:   service.NewJournalService(journaled),
+	}
+}
+
+// fileJournal returns the journal kept next to the data file of repo.
+func fileJournal(repo data.Repository, dataFilePath string) *data.Journal {
+	journal := data.NewJournal(data.JournalPath(dataFilePath))
+	if jsonRepo, ok := repo.(*data.JsonRepository); ok {
+		jsonRepo.SetJournal(journal)
+	}
+	return journal
+}
+
+// runDemo runs the interface on sample data kept in memory, so that nothing
+// done in it is saved.
+func runDemo() error {
+	repo := data.NewMemoryRepository()
+	if err := demo.Seed(repo, domain.MonthOf(time.Now()), viper.GetString(config.CurrencyField)); err != nil {
+		return err
+	}
+	s := newServices(repo, data.NewMemoryJournal())
+	a := app.New(s.category, s.group, s.income, s.rate, s.recurring, s.month, s.journal, repo.FilePath())
+
+	_, err := tea.NewProgram(a, tea.WithAltScreen()).Run()
+	return err
+}
+
+// newApp creates the interface working on repo. Changes made to its data file
+// by other programs are picked up while the interface runs. The returned
+// function stops watching the file and closes repo.
+func newApp(repo data.Repository, dataFilePath string) (app.App, func()) {
+	s := newServices(repo, fileJournal(repo, dataFilePath))
+	a := app.New(s.category, s.group, s.income, s.rate, s.recurring, s.month, s.journal, dataFilePath)
+
+	var watcher *data.FileWatcher
+	if jsonRepo, ok := repo.(*data.JsonRepository); ok {
+		var err error
+		if watcher, err = data.NewFileWatcher(dataFilePath); err == nil {
+			a = a.WatchDataFile(jsonRepo, watcher.Changes())
+		}
+	}
+
+	return a, func() {
+		if watcher != nil {
+			_ = watcher.Close()
+		}
+		closeRepository(repo)
+	}
+}
+
+// switchProfile opens the data of the profile named name from the interface.
+// The unlock screen cannot run inside the interface, so an encrypted data
+// file is only opened with the passphrase set in config.PassphraseEnv.
+func switchProfile(name string) (app.App, func(), error) {
+	profile, err := config.GetProfile(name)
+	if err != nil {
+		return app.App{}, nil, err
+	}
+	if profile.Storage != config.StorageSQLite && os.Getenv(config.PassphraseEnv) == "" {
+		if encrypted, err := data.IsEncryptedFile(profile.DataFilename); err == nil && encrypted {
+			return app.App{}, nil, fmt.Errorf("data file is encrypted, start gocost with --profile %s to unlock it", profile.Name)
+		}
+	}
+
+	repo, _, dataFilePath, err := openRepository(profile, false)
+	if err != nil {
+		return app.App{}, nil, err
+	}
+	config.UseProfile(profile)
+	a, closeSession := newApp(repo, dataFilePath)
+	return a, closeSession, nil
+}
+
+// openRepository opens the storage backend of profile and returns it together
+// with its backups, when supported, and the path of its data file.
+// interactive selects how the passphrase of an encrypted data file is asked for.
+func openRepository(profile config.Profile, interactive bool) (data.Repository, *data.Backups, string, error) {
+	switch storage := profile.Storage; storage {
+	case config.StorageSQLite:
+		databaseFilePath := profile.DatabaseFilename
+		repo, err := data.NewSqliteRepository(databaseFilePath, profile.Currency)
+		return repo, nil, databaseFilePath, err
+	case config.StorageJSON, "":
+		dataFilePath := profile.DataFilename
+		repo, err := openJsonRepository(dataFilePath, profile.Currency, interactive)
+		if err != nil {
+			return nil, nil, dataFilePath, err
+		}
+
+		backups := jsonBackups(dataFilePath)
+		repo.SetBackups(backups)
+		if _, err := repo.Migrate(); err != nil {
+			return nil, nil, dataFilePath, err
+		}
+		return repo, backups, dataFilePath, nil
+	default:
+		return nil, nil, "", fmt.Errorf("unknown storage %q, expected %q or %q", storage, config.StorageJSON, config.StorageSQLite)
+	}
+}
+
+// jsonBackups returns the backups of the JSON data file at dataFilePath.
+func jsonBackups(dataFilePath string) *data.Backups {
+	dataDir := viper.GetString(config.DataDirField)
+	if dataDir == "" {
+		dataDir = filepath.Dir(dataFilePath)
+	}
+	return data.NewBackups(filepath.Join(dataDir, config.BackupDirName), data.DefaultBackupLimit)
+}
+
+// openJsonRepository opens the JSON data file. The passphrase of an encrypted
+// file is asked for on the unlock screen when interactive, and otherwise read
+// with config.ReadPassphrase.
+func openJsonRepository(dataFilePath string, currency string, interactive bool) (*data.JsonRepository, error) {
+	repo, err := data.NewJsonRepository(dataFilePath, currency)
+	if !errors.Is(err, data.ErrPassphraseRequired) {
+		return repo, err
+	}
+
+	if !interactive || os.Getenv(config.PassphraseEnv) != "" {
+		passphrase, err := config.ReadPassphrase("Passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		return data.NewEncryptedJsonRepository(dataFilePath, currency, passphrase)
+	}
+
+	unlock := ui.NewUnlockModel(dataFilePath, func(passphrase string) error {
+		var err error
+		repo, err = data.NewEncryptedJsonRepository(dataFilePath, currency, passphrase)
+		return err
+	})
+	model, err := tea.NewProgram(unlock, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+	if unlocked, ok := model.(ui.UnlockModel); !ok || !unlocked.Unlocked() {
+		return nil, errors.New("data file was not unlocked")
+	}
+	return repo, nil
+}
+
+// closeRepository releases the resources held by repositories that need closing.
+func closeRepository(repo data.Repository) {
+	if closer, ok := repo.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error closing data store: %v\n", err)
+		}
+	}
+}
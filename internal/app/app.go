@@ -20,6 +20,11 @@ const (
 	viewCategoryGroup
 	viewCategory
 	viewExpense
+	viewRates
+	viewTrends
+	viewHistory
+	viewProfiles
+	viewImport
 )
 
 // App represents the main application. It now holds services instead of raw data.
@@ -34,9 +39,25 @@ type App struct {
 	isInitialized bool // Flag to track initial model creation
 
 	// Services for business logic
-	categorySvc *service.CategoryService
-	groupSvc    *service.GroupService
-	incomeSvc   *service.IncomeService
+	categorySvc  *service.CategoryService
+	groupSvc     *service.GroupService
+	incomeSvc    *service.IncomeService
+	rateSvc      *service.RateService
+	recurringSvc *service.RecurringService
+	monthSvc     *service.MonthService
+	journalSvc   *service.JournalService
+
+	// Changes of the session that can be undone and redone
+	history undoHistory
+
+	// Profile switching, see WithProfiles
+	profile      string
+	openProfile  ProfileOpener
+	closeSession func()
+
+	// Live reload of the data file, see WatchDataFile
+	reloader    Reloader
+	fileChanges <-chan struct{}
 }
 
 // New creates a new instance of the application.
@@ -44,6 +65,10 @@ func New(
 	categoryService *service.CategoryService,
 	groupService *service.GroupService,
 	incomeService *service.IncomeService,
+	rateService *service.RateService,
+	recurringService *service.RecurringService,
+	monthService *service.MonthService,
+	journalService *service.JournalService,
 	dataFilePath string,
 ) App {
 	now := time.Now()
@@ -56,9 +81,13 @@ func New(
 			CurrentMonth: currentM,
 			CurrentYear:  currentY,
 		},
-		categorySvc: categoryService,
-		groupSvc:    groupService,
-		incomeSvc:   incomeService,
+		categorySvc:  categoryService,
+		groupSvc:     groupService,
+		incomeSvc:    incomeService,
+		rateSvc:      rateService,
+		recurringSvc: recurringService,
+		monthSvc:     monthService,
+		journalSvc:   journalService,
 	}
 
 	// Initial data load and model creation
@@ -71,6 +100,11 @@ func New(
 func (m App) refreshDataForModels() App {
 	monthKey := ui.GetMonthKey(m.CurrentMonth, m.CurrentYear)
 
+	// Recurring templates are applied whenever a month is opened
+	if _, err := m.recurringSvc.Materialize(monthKey); err != nil {
+		log.Printf("Error applying recurring templates: %v", err)
+	}
+
 	groups, err := m.groupSvc.GetAllGroups()
 	if err != nil {
 		log.Printf("Error fetching groups: %v", err)
@@ -86,10 +120,16 @@ func (m App) refreshDataForModels() App {
 		log.Printf("Error fetching incomes: %v", err)
 	}
 
+	rates, err := m.rateSvc.GetRatesForMonth(monthKey)
+	if err != nil {
+		log.Printf("Error fetching exchange rates: %v", err)
+	}
+
 	appData := ui.AppData{
 		Categories:     categories,
 		CategoryGroups: groups,
 		Incomes:        incomes,
+		Rates:          rates,
 	}
 
 	if !m.isInitialized {
@@ -99,25 +139,50 @@ func (m App) refreshDataForModels() App {
 		m.CategoryGroupModel = ui.NewCategoryGroupModel(groups, m.Width, m.Height, monthYear)
 		m.IncomeModel = ui.NewIncomeModel(incomes, monthYear)
 		m.ExpenseModel = ui.NewExpenseModel(domain.Category{}, "")
+		m.RatesModel = ui.NewRatesModel(rates, monthYear)
+		m.TrendsModel = ui.NewTrendsModel(monthYear)
+		m.HistoryModel = ui.NewHistoryModel(monthYear)
+		m.ProfileModel = ui.NewProfileModel()
+		m.ImportModel = ui.NewImportModel(monthYear)
 		m.isInitialized = true
 	} else {
 		m.MonthlyModel = m.MonthlyModel.UpdateData(appData)
 		m.CategoryModel = m.CategoryModel.UpdateData(appData)
 		m.CategoryGroupModel = m.CategoryGroupModel.UpdateData(groups)
 		m.IncomeModel = m.IncomeModel.UpdateData(incomes)
+		m.RatesModel = m.RatesModel.UpdateData(rates)
 
 		m.MonthlyModel = m.MonthlyModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
 		m.CategoryModel = m.CategoryModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
 		m.CategoryGroupModel = m.CategoryGroupModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
 		m.IncomeModel = m.IncomeModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
+		m.RatesModel = m.RatesModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
 	}
 
 	return m
 }
 
+// refreshActiveView refreshes the data of the models, reloading the trends
+// and history views when one of them is shown.
+func (m App) refreshActiveView() (App, tea.Cmd) {
+	app := m.refreshDataForModels()
+	var model tea.Model
+	var cmd tea.Cmd
+	switch app.activeView {
+	case viewTrends:
+		model, cmd = app.handleTrendsViewMsg(ui.TrendsViewMsg{Months: app.TrendsModel.Span()})
+		app = model.(App)
+	case viewHistory:
+		monthKey, category := app.HistoryModel.Filter()
+		model, cmd = app.handleHistoryViewMsg(ui.HistoryViewMsg{MonthKey: monthKey, Category: category})
+		app = model.(App)
+	}
+	return app, cmd
+}
+
 // Init initializes the application.
 func (m App) Init() tea.Cmd {
-	return nil // No initial command needed
+	return m.waitForDataFileChange()
 }
 
 // Update updates the application state.
@@ -126,6 +191,15 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
+		if m.acceptsUndo() {
+			switch msg.String() {
+			case "u":
+				return m.handleUndo()
+			case "ctrl+r":
+				return m.handleRedo()
+			}
+		}
+
 		switch m.activeView {
 		case viewMonthlyOverview:
 			switch msg.String() {
@@ -140,6 +214,15 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "g":
 				m.activeView = viewCategoryGroup
 				return m.refreshDataForModels(), nil
+			case "x":
+				m.activeView = viewRates
+				return m.refreshDataForModels(), nil
+			case "v":
+				return m.handleTrendsViewMsg(ui.TrendsViewMsg{Months: m.TrendsModel.Span()})
+			case "P":
+				return m.handleProfilesViewMsg()
+			case "I":
+				return m.handleImportViewMsg()
 			case "h":
 				m.CurrentYear, m.CurrentMonth = ui.GetPreviousMonth(m.CurrentYear, m.CurrentMonth)
 				return m.refreshDataForModels(), nil
@@ -157,7 +240,7 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.MonthlyModel = mo
 			}
 			return m, monthlyCmd
-		case viewIncome, viewCategoryGroup, viewCategory, viewExpense, viewIncomeForm:
+		case viewIncome, viewCategoryGroup, viewCategory, viewExpense, viewIncomeForm, viewRates, viewTrends, viewHistory, viewProfiles, viewImport:
 			// Delegate message to the active view
 			var updatedModel tea.Model
 			var cmd tea.Cmd
@@ -187,6 +270,31 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if model, ok := updatedModel.(ui.ExpenseModel); ok {
 					m.ExpenseModel = model
 				}
+			case viewRates:
+				updatedModel, cmd = m.RatesModel.Update(msg)
+				if model, ok := updatedModel.(ui.RatesModel); ok {
+					m.RatesModel = model
+				}
+			case viewTrends:
+				updatedModel, cmd = m.TrendsModel.Update(msg)
+				if model, ok := updatedModel.(ui.TrendsModel); ok {
+					m.TrendsModel = model
+				}
+			case viewHistory:
+				updatedModel, cmd = m.HistoryModel.Update(msg)
+				if model, ok := updatedModel.(ui.HistoryModel); ok {
+					m.HistoryModel = model
+				}
+			case viewProfiles:
+				updatedModel, cmd = m.ProfileModel.Update(msg)
+				if model, ok := updatedModel.(ui.ProfileModel); ok {
+					m.ProfileModel = model
+				}
+			case viewImport:
+				updatedModel, cmd = m.ImportModel.Update(msg)
+				if model, ok := updatedModel.(ui.ImportModel); ok {
+					m.ImportModel = model
+				}
 			}
 			return m, cmd
 		}
@@ -210,6 +318,24 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleEditIncomeMsg(msg)
 	case ui.DeleteIncomeMsg:
 		return m.handleDeleteIncomeMsg(msg)
+	case ui.RatesViewMsg:
+		return m.handleRatesViewMsg()
+	case ui.SaveRateMsg:
+		return m.handleSaveRateMsg(msg)
+	case ui.DeleteRateMsg:
+		return m.handleDeleteRateMsg(msg)
+	case ui.TrendsViewMsg:
+		return m.handleTrendsViewMsg(msg)
+	case ui.HistoryViewMsg:
+		return m.handleHistoryViewMsg(msg)
+	case ui.SwitchProfileMsg:
+		return m.handleSwitchProfileMsg(msg)
+	case ui.ExportMonthMsg:
+		return m.handleExportMonthMsg(msg)
+	case ui.LoadStatementMsg:
+		return m.handleLoadStatementMsg(msg)
+	case ui.ApplyImportMsg:
+		return m.handleApplyImportMsg(msg)
 	case ui.GroupAddMsg:
 		return m.handleGroupAddMsg(msg)
 	case ui.GroupDeleteMsg:
@@ -250,6 +376,8 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleCategoryViewMsg()
 	case ui.CategoryViewWithMonthMsg:
 		return m.handleCategoryViewWithMonthMsg(msg)
+	case DataFileChangedMsg:
+		return m.handleDataFileChangedMsg()
 	case StatusClearMsg:
 		return m.ClearStatus(), nil
 	}
@@ -276,6 +404,16 @@ func (m App) View() string {
 		viewContent = m.CategoryModel.View()
 	case viewExpense:
 		viewContent = m.ExpenseModel.View()
+	case viewRates:
+		viewContent = m.RatesModel.View()
+	case viewTrends:
+		viewContent = m.TrendsModel.View()
+	case viewHistory:
+		viewContent = m.HistoryModel.View()
+	case viewProfiles:
+		viewContent = m.ProfileModel.View()
+	case viewImport:
+		viewContent = m.ImportModel.View()
 	default:
 		viewContent = "Error: View not found or not initialized"
 	}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// handleError reports the failure of action, such as "add group", with a
+// message matching the kind of err. Records that no longer exist or already
+// exist mean the views are out of date, so their data is reloaded.
+func (m App) handleError(action string, err error) (App, tea.Cmd) {
+	var (
+		notFound      *domain.NotFoundError
+		alreadyExists *domain.AlreadyExistsError
+		inUse         *domain.InUseError
+		invalid       *domain.ValidationError
+	)
+
+	switch {
+	case errors.As(err, &notFound):
+		app := m.refreshDataForModels()
+		if notFound.ID == "" {
+			return app.SetErrorStatus(fmt.Sprintf("Failed to %s: %v, the view was refreshed", action, err))
+		}
+		return app.SetErrorStatus(fmt.Sprintf("Failed to %s: the %s no longer exists, the view was refreshed",
+			action, domain.RecordName(notFound.Record)))
+
+	case errors.As(err, &alreadyExists):
+		app := m.refreshDataForModels()
+		return app.SetErrorStatus(fmt.Sprintf("Failed to %s: a %s with the same ID already exists, the view was refreshed",
+			action, domain.RecordName(alreadyExists.Record)))
+
+	case errors.As(err, &inUse):
+		return m.SetErrorStatus(fmt.Sprintf("Cannot delete %s '%s' while %s use it, move or delete them in every month first",
+			domain.RecordName(inUse.Record), inUse.Name, domain.PluralRecordName(inUse.UsedBy)))
+
+	case errors.As(err, &invalid):
+		return m.SetErrorStatus(fmt.Sprintf("Failed to %s: %s", action, invalid.Message))
+
+	default:
+		return m.SetErrorStatus(fmt.Sprintf("Failed to %s: %v", action, err))
+	}
+}
+
+// handleCategoryError reports the failure of action on category. When its
+// name was rejected, the name input is reopened with the reason below it.
+func (m App) handleCategoryError(action string, category domain.Category, err error) (App, tea.Cmd) {
+	app, cmd := m.handleError(action, err)
+	message, ok := domain.FieldErrors(err)[domain.FieldName]
+	if !ok || app.CategoryModel.IsMovingCategory() {
+		return app, cmd
+	}
+	var inputCmd tea.Cmd
+	app.CategoryModel, inputCmd = app.CategoryModel.ShowNameError(category, message)
+	return app, tea.Batch(cmd, inputCmd)
+}
+
+// handleGroupError reports the failure of action on group. When its name was
+// rejected, the name input is reopened with the reason below it.
+func (m App) handleGroupError(action string, group domain.CategoryGroup, err error) (App, tea.Cmd) {
+	app, cmd := m.handleError(action, err)
+	message, ok := domain.FieldErrors(err)[domain.FieldName]
+	if !ok {
+		return app, cmd
+	}
+	var inputCmd tea.Cmd
+	app.CategoryGroupModel, inputCmd = app.CategoryGroupModel.ShowNameError(group, message)
+	return app, tea.Batch(cmd, inputCmd)
+}
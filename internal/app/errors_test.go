@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleError(t *testing.T) {
+	app := createTestAppWithMocks(t)
+	monthKey := ui.GetMonthKey(app.CurrentMonth, app.CurrentYear)
+	require.NoError(t, app.groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+	require.NoError(t, app.categorySvc.AddCategory(monthKey, domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+
+	t.Run("Group in use", func(t *testing.T) {
+		model, _ := app.handleGroupDeleteMsg(ui.GroupDeleteMsg{Group: domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}})
+		assert.Contains(t, model.(App).GetStatusMessage(), "Cannot delete group 'Housing' while categories use it")
+	})
+
+	t.Run("Deleted elsewhere", func(t *testing.T) {
+		model, _ := app.handleDeleteIncomeMsg(ui.DeleteIncomeMsg{MonthKey: monthKey, Income: domain.IncomeRecord{IncomeID: "i9"}})
+		assert.Contains(t, model.(App).GetStatusMessage(), "Failed to delete income: the income no longer exists, the view was refreshed")
+
+		model, _ = app.handleCategoryUpdateMsg(ui.CategoryUpdateMsg{MonthKey: monthKey, Category: domain.Category{CatID: "c9", GroupID: "g1", CategoryName: "Food"}})
+		assert.Contains(t, model.(App).GetStatusMessage(), "Failed to update category: the category no longer exists")
+	})
+
+	t.Run("Already exists", func(t *testing.T) {
+		model, _ := app.handleGroupAddMsg(ui.GroupAddMsg{Group: domain.CategoryGroup{GroupID: "g1", GroupName: "Living"}})
+		assert.Contains(t, model.(App).GetStatusMessage(), "a group with the same ID already exists")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		model, _ := app.handleSaveRateMsg(ui.SaveRateMsg{MonthKey: monthKey, Rate: domain.ExchangeRate{Currency: "EUR", Rate: decimal.Zero}})
+		assert.Contains(t, model.(App).GetStatusMessage(), "Failed to save exchange rate: exchange rate must be greater than zero")
+	})
+
+	t.Run("Invalid fields shown in the form", func(t *testing.T) {
+		app.IncomeFormModel = ui.NewIncomeFormModel(app.CurrentMonth, app.CurrentYear, nil)
+		app.activeView = viewIncomeForm
+		model, _ := app.handleSaveIncomeMsg(ui.SaveIncomeMsg{MonthKey: monthKey, Income: domain.IncomeRecord{IncomeID: "i1", Amount: decimal.NewFromInt(10)}})
+		result := model.(App)
+		assert.Equal(t, viewIncomeForm, result.activeView)
+		assert.Contains(t, result.GetStatusMessage(), "Failed to save income: income description cannot be empty")
+		assert.Contains(t, result.IncomeFormModel.View(), "income description cannot be empty")
+
+		app = app.refreshDataForModels()
+		categories, _ := app.CategoryModel.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+		app.CategoryModel = categories.(ui.CategoryModel)
+		model, _ = app.handleCategoryAddMsg(ui.CategoryAddMsg{MonthKey: monthKey, Category: domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "rent"}})
+		result = model.(App)
+		assert.Contains(t, result.GetStatusMessage(), "a category named 'Rent' already exists in this group")
+		assert.Contains(t, result.CategoryModel.View(), "Add Category to Housing")
+		assert.Contains(t, result.CategoryModel.View(), "a category named 'Rent' already exists in this group")
+
+		model, _ = app.handleGroupUpdateMsg(ui.GroupUpdateMsg{Group: domain.CategoryGroup{GroupID: "g1", GroupName: " "}})
+		result = model.(App)
+		assert.True(t, result.CategoryGroupModel.IsEditing())
+		assert.Contains(t, result.GetStatusMessage(), "Failed to update group: group name cannot be empty")
+	})
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/importer"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/spf13/viper"
+)
+
+// handleImportViewMsg asks for the path of a bank statement to import into
+// the current month.
+func (m App) handleImportViewMsg() (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.ImportModel, cmd = m.ImportModel.Open(m.MonthYear)
+	m.activeView = viewImport
+	return m, cmd
+}
+
+// handleLoadStatementMsg reads a bank statement and lists its spending of
+// the current month for review. Categories are picked by the import rules
+// of the config file, or by the categories of the statement. Transactions
+// imported before, those of other months and those that cannot be imported
+// are left out.
+func (m App) handleLoadStatementMsg(msg ui.LoadStatementMsg) (tea.Model, tea.Cmd) {
+	transactions, err := importer.ReadStatement(expandHome(msg.Path))
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to import statement: %v", err))
+	}
+
+	var rules []importer.Rule
+	if err := viper.UnmarshalKey(config.ImportRulesField, &rules); err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to import statement: invalid %s in config: %v", config.ImportRulesField, err))
+	}
+	ruleSet, err := importer.CompileRules(rules)
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to import statement: %v", err))
+	}
+
+	monthKey := ui.GetMonthKey(m.CurrentMonth, m.CurrentYear)
+	plan, err := importer.New(m.categorySvc, m.groupSvc, m.incomeSvc).Plan(transactions, ruleSet, viper.GetString(config.CurrencyField))
+	if err != nil {
+		return m.handleError("import statement", err)
+	}
+	categories, err := m.categorySvc.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return m.handleError("import statement", err)
+	}
+	groups, err := m.groupSvc.GetAllGroups()
+	if err != nil {
+		return m.handleError("import statement", err)
+	}
+	groupNames := make(map[string]string, len(groups))
+	for _, group := range groups {
+		groupNames[group.GroupID] = group.GroupName
+	}
+	choices := make([]ui.ImportCategory, len(categories))
+	labels := make(map[string]string, len(categories))
+	for i, category := range categories {
+		groupName, ok := groupNames[category.GroupID]
+		if !ok {
+			groupName = "Ungrouped"
+		}
+		choices[i] = ui.ImportCategory{
+			CatID: category.CatID,
+			Label: fmt.Sprintf("%s / %s", groupName, category.CategoryName),
+		}
+		labels[category.CatID] = choices[i].Label
+	}
+
+	var items []ui.ImportItem
+	duplicates, outside, skipped := 0, 0, 0
+	for _, item := range plan.Items {
+		switch {
+		case item.Status == importer.StatusIncome:
+			continue
+		case item.MonthKey != monthKey:
+			outside++
+			continue
+		case item.Status == importer.StatusDuplicate:
+			duplicates++
+			continue
+		case item.Status == importer.StatusSkipped:
+			skipped++
+			continue
+		}
+
+		reviewed := ui.ImportItem{Date: item.Date, Payee: item.Payee, Amount: item.Amount, EntryID: item.EntryID}
+		if item.CatID != "" {
+			reviewed.CatID = item.CatID
+			reviewed.Category = labels[item.CatID]
+			reviewed.Accepted = true
+		}
+		items = append(items, reviewed)
+	}
+	m.ImportModel = m.ImportModel.Review(msg.Path, items, choices)
+
+	switch {
+	case skipped > 0:
+		return m.SetErrorStatus(fmt.Sprintf("Left out %d transactions imported before, %d of other months and %d that cannot be imported",
+			duplicates, outside, skipped))
+	case duplicates > 0 || outside > 0:
+		return m.SetSuccessStatus(fmt.Sprintf("Left out %d transactions imported before and %d of other months", duplicates, outside))
+	}
+	return m, nil
+}
+
+// handleApplyImportMsg adds the accepted transactions of a statement as
+// entries of the expenses of their categories, which are found by ID.
+func (m App) handleApplyImportMsg(msg ui.ApplyImportMsg) (tea.Model, tea.Cmd) {
+	before, err := m.categorySvc.GetCategoriesForMonth(msg.MonthKey)
+	if err != nil {
+		return m.handleError("import statement", err)
+	}
+	names := make(map[string]string, len(before))
+	for _, category := range before {
+		names[category.CatID] = category.CategoryName
+	}
+
+	var plan importer.Plan
+	for _, item := range msg.Items {
+		plan.Items = append(plan.Items, importer.Item{
+			Transaction: importer.Transaction{Date: item.Date, Payee: item.Payee, Amount: item.Amount},
+			MonthKey:    msg.MonthKey,
+			Category:    names[item.CatID],
+			CatID:       item.CatID,
+			EntryID:     item.EntryID,
+			Status:      importer.StatusNew,
+		})
+	}
+	// Nothing is imported when one of the transactions fails
+	count, err := importer.New(m.categorySvc, m.groupSvc, m.incomeSvc).Apply(plan)
+	if err != nil {
+		return m.handleError("import statement", err)
+	}
+
+	app := m.refreshDataForModels()
+	if after, err := m.categorySvc.GetCategoriesForMonth(msg.MonthKey); err == nil && count > 0 {
+		app = app.recordChange(statementImported(m.categorySvc, msg.MonthKey, count, changedCategories(before, after)))
+	}
+
+	app.activeView = viewMonthlyOverview
+	return app.SetSuccessStatus(fmt.Sprintf("Imported %d transactions", count))
+}
+
+// categoryChange is a category of a month before and after a change.
+type categoryChange struct {
+	before domain.Category
+	after  domain.Category
+}
+
+// changedCategories returns the categories of before whose expense has
+// other entries in after.
+func changedCategories(before, after []domain.Category) []categoryChange {
+	var changes []categoryChange
+	for _, b := range before {
+		for _, a := range after {
+			if a.CatID == b.CatID && len(a.Expense[a.CatID].Entries) != len(b.Expense[b.CatID].Entries) {
+				changes = append(changes, categoryChange{before: b, after: a})
+			}
+		}
+	}
+	return changes
+}
+
+// statementImported returns the operation of importing count transactions
+// of a statement into the categories of a month.
+func statementImported(categories *service.CategoryService, monthKey string, count int, changes []categoryChange) operation {
+	return operation{
+		description: fmt.Sprintf("import %d transactions", count),
+		undo: func() error {
+			for _, change := range changes {
+				if err := categories.UpdateCategory(monthKey, change.before.Clone()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		redo: func() error {
+			for _, change := range changes {
+				if err := categories.UpdateCategory(monthKey, change.after.Clone()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// expandHome replaces a leading ~ of path with the home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
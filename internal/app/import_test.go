@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportStatement(t *testing.T) {
+	app := createTestAppWithMocks(t)
+	monthKey := ui.GetMonthKey(app.CurrentMonth, app.CurrentYear)
+	require.NoError(t, app.groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Living"}))
+	require.NoError(t, app.groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Leisure"}))
+	require.NoError(t, app.categorySvc.AddCategory(monthKey, domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Groceries"}))
+	require.NoError(t, app.categorySvc.AddCategory(monthKey, domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Other"}))
+	require.NoError(t, app.categorySvc.AddCategory(monthKey, domain.Category{CatID: "c3", GroupID: "g2", CategoryName: "Other"}))
+	app = app.refreshDataForModels()
+
+	date := fmt.Sprintf("%d/3/%d", app.CurrentMonth, app.CurrentYear)
+	statement := filepath.Join(t.TempDir(), "statement.qif")
+	require.NoError(t, os.WriteFile(statement, []byte("!Type:Bank\nD"+date+"\nT-42.10\nPTesco\nLGroceries\n^\nD"+date+
+		"\nT-12.00\nPCinema\n^\nD1/1/2001\nT-5.00\nPOld\n^\n"), 0644))
+
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("I")})
+	app = model.(App)
+	assert.Equal(t, viewImport, app.activeView)
+
+	model, _ = app.handleLoadStatementMsg(ui.LoadStatementMsg{Path: statement})
+	app = model.(App)
+	assert.Contains(t, app.GetStatusMessage(), "0 transactions imported before and 1 of other months")
+	accepted := app.ImportModel.Accepted()
+	require.Len(t, accepted, 1)
+	assert.Equal(t, "c1", accepted[0].CatID)
+	assert.Equal(t, "Living / Groceries", accepted[0].Category)
+
+	// Categories are imported into by ID, whatever other categories share their name
+	cinema := accepted[0]
+	cinema.EntryID, cinema.Payee, cinema.CatID = "cinema", "Cinema", "c3"
+	accepted = append(accepted, cinema)
+
+	model, _ = app.handleApplyImportMsg(ui.ApplyImportMsg{MonthKey: monthKey, Items: accepted})
+	app = model.(App)
+	assert.Equal(t, viewMonthlyOverview, app.activeView)
+	assert.Contains(t, app.GetStatusMessage(), "Imported 2 transactions")
+	categories, err := app.categorySvc.GetCategoriesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Equal(t, "42.1", categories[0].Expense["c1"].Amount.String())
+	assert.Empty(t, categories[1].Expense["c2"].Entries)
+	assert.Equal(t, "Cinema", categories[2].Expense["c3"].Entries[0].Description)
+
+	// Loading the statement again leaves out what was imported
+	model, _ = app.handleLoadStatementMsg(ui.LoadStatementMsg{Path: statement})
+	app = model.(App)
+	assert.Contains(t, app.GetStatusMessage(), "1 transactions imported before")
+	assert.Empty(t, app.ImportModel.Accepted())
+
+	// The categories of the list are named with their group
+	review, _ := app.ImportModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	assert.Contains(t, review.View(), "Leisure / Other")
+
+	// The whole import is undone at once
+	app.activeView = viewMonthlyOverview
+	model, _ = app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	app = model.(App)
+	categories, err = app.categorySvc.GetCategoriesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Empty(t, categories[0].Expense["c1"].Entries)
+
+	model, _ = app.handleLoadStatementMsg(ui.LoadStatementMsg{Path: filepath.Join(t.TempDir(), "statement.pdf")})
+	app = model.(App)
+	assert.Contains(t, app.GetStatusMessage(), "unknown statement format")
+}
@@ -1,24 +1,41 @@
 package app
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/export"
 	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 )
 
-// handlePopulateCategoriesMsg copy categories from the previous month if it exists.
+// handlePopulateCategoriesMsg copies the categories of the previous month,
+// which replace the categories of the current month.
 func (m App) handlePopulateCategoriesMsg(msg ui.PopulateCategoriesMsg) (tea.Model, tea.Cmd) {
-	count, err := m.categorySvc.CopyCategoriesFromMonth(msg.PreviousMonthKey, msg.CurrentMonthKey)
+	before, err := m.categorySvc.GetCategoriesForMonth(msg.CurrentMonthKey)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to copy categories: %v", err))
+		return m.handleError("copy categories", err)
+	}
+	count, err := m.categorySvc.CopyCategoriesFromMonth(msg.PreviousMonthKey, msg.CurrentMonthKey)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return m.handleError("copy categories", err)
 	}
 	if count == 0 {
 		return m.SetErrorStatus(fmt.Sprintf("No categories found in %s to copy from", msg.PreviousMonthKey))
 	}
 
+	// The categories replaced and their expenses are kept so that undoing the copy restores them
 	app := m.refreshDataForModels()
+	if after, err := m.categorySvc.GetCategoriesForMonth(msg.CurrentMonthKey); err == nil {
+		app = app.recordChange(categoriesCopied(m.categorySvc, msg.PreviousMonthKey, msg.CurrentMonthKey, before, after))
+	}
 	app.MonthlyModel = app.MonthlyModel.ResetFocus()
 
 	return app.SetSuccessStatus(fmt.Sprintf("Successfully copied %d categories from %s", count, msg.PreviousMonthKey))
@@ -58,6 +75,36 @@ func (m App) handleModelsWindowResize(msg tea.Msg) (tea.Model, []tea.Cmd) {
 	}
 	cmds = append(cmds, expCmd)
 
+	updatedRatesModel, rateCmd := m.RatesModel.Update(msg)
+	if rateMo, ok := updatedRatesModel.(ui.RatesModel); ok {
+		m.RatesModel = rateMo
+	}
+	cmds = append(cmds, rateCmd)
+
+	updatedTrendsModel, trendCmd := m.TrendsModel.Update(msg)
+	if trendMo, ok := updatedTrendsModel.(ui.TrendsModel); ok {
+		m.TrendsModel = trendMo
+	}
+	cmds = append(cmds, trendCmd)
+
+	updatedHistoryModel, historyCmd := m.HistoryModel.Update(msg)
+	if historyMo, ok := updatedHistoryModel.(ui.HistoryModel); ok {
+		m.HistoryModel = historyMo
+	}
+	cmds = append(cmds, historyCmd)
+
+	updatedProfileModel, profileCmd := m.ProfileModel.Update(msg)
+	if profileMo, ok := updatedProfileModel.(ui.ProfileModel); ok {
+		m.ProfileModel = profileMo
+	}
+	cmds = append(cmds, profileCmd)
+
+	updatedImportModel, importCmd := m.ImportModel.Update(msg)
+	if importMo, ok := updatedImportModel.(ui.ImportModel); ok {
+		m.ImportModel = importMo
+	}
+	cmds = append(cmds, importCmd)
+
 	return m, cmds
 }
 
@@ -79,14 +126,22 @@ func (m App) handleSaveExpenseMsg(msg ui.SaveExpenseMsg) (tea.Model, tea.Cmd) {
 	if category.Expense == nil {
 		category.Expense = make(map[string]domain.ExpenseRecord)
 	}
-	category.Expense[category.CatID] = msg.Expense
+	expense := msg.Expense
+	expense.SyncAmount()
+	category.Expense[category.CatID] = expense
 
+	before, found := m.findCategory(msg.MonthKey, category.CatID)
 	err := m.categorySvc.UpdateCategory(msg.MonthKey, category)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to save expense: %v", err))
+		m.ExpenseModel = m.ExpenseModel.SetFieldErrors(domain.FieldErrors(err))
+		return m.handleError("save expense", err)
 	}
 
 	app := m.refreshDataForModels()
+	if found {
+		description := fmt.Sprintf("save expense for '%s'", category.CategoryName)
+		app = app.recordChange(categoryUpdated(m.categorySvc, description, msg.MonthKey, before, category))
+	}
 	app.MonthlyModel = app.MonthlyModel.SetFocusToCategory(msg.Category)
 	app.activeView = viewMonthlyOverview
 	return app.SetSuccessStatus(fmt.Sprintf("Expense for '%s' saved successfully", msg.Category.CategoryName))
@@ -103,18 +158,23 @@ func (m App) handleEditExpenseMsg(msg ui.EditExpenseMsg) (tea.Model, tea.Cmd) {
 func (m App) handleDeleteExpenseMsg(msg ui.DeleteExpenseMsg) (tea.Model, tea.Cmd) {
 	category := msg.Category
 	category.Expense[category.CatID] = domain.ExpenseRecord{
-		Amount: 0,
-		Budget: 0,
+		Amount: decimal.Zero,
+		Budget: decimal.Zero,
 		Status: "Not Paid",
 		Notes:  "",
 	}
 
+	before, found := m.findCategory(msg.MonthKey, category.CatID)
 	err := m.categorySvc.UpdateCategory(msg.MonthKey, category)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to clear expense: %v", err))
+		return m.handleError("clear expense", err)
 	}
 
 	app := m.refreshDataForModels()
+	if found {
+		description := fmt.Sprintf("clear expense for '%s'", category.CategoryName)
+		app = app.recordChange(categoryUpdated(m.categorySvc, description, msg.MonthKey, before, category))
+	}
 	app.MonthlyModel = app.MonthlyModel.SetFocusToCategory(msg.Category)
 	app.activeView = viewMonthlyOverview
 	return app.SetSuccessStatus(fmt.Sprintf("Expense for category '%s' has been cleared", msg.Category.CategoryName))
@@ -135,12 +195,17 @@ func (m App) handleToggleExpenseStatusMsg(msg ui.ToggleExpenseStatusMsg) (tea.Mo
 	}
 	category.Expense[category.CatID] = currentExpense
 
+	before, found := m.findCategory(msg.MonthKey, category.CatID)
 	err := m.categorySvc.UpdateCategory(msg.MonthKey, category)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to toggle status: %v", err))
+		return m.handleError("toggle status", err)
 	}
 
 	app := m.refreshDataForModels()
+	if found {
+		description := fmt.Sprintf("toggle status of '%s'", category.CategoryName)
+		app = app.recordChange(categoryUpdated(m.categorySvc, description, msg.MonthKey, before, category))
+	}
 	app.MonthlyModel = app.MonthlyModel.SetFocusToCategory(category)
 	return app.SetSuccessStatus(fmt.Sprintf("Status for '%s' toggled to '%s'", category.CategoryName, currentExpense.Status))
 }
@@ -156,29 +221,38 @@ func (m App) handleMonthlyViewMsg() (tea.Model, tea.Cmd) {
 func (m App) handleGroupAddMsg(msg ui.GroupAddMsg) (tea.Model, tea.Cmd) {
 	err := m.groupSvc.AddGroup(msg.Group)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to add group: %v", err))
+		return m.handleGroupError("add group", msg.Group, err)
 	}
 	app := m.refreshDataForModels()
+	app = app.recordChange(groupAdded(m.groupSvc, msg.Group))
 	return app.SetSuccessStatus(fmt.Sprintf("Group '%s' added successfully", msg.Group.GroupName))
 }
 
 // handleGroupDeleteMsg handles the deletion of a category group.
 func (m App) handleGroupDeleteMsg(msg ui.GroupDeleteMsg) (tea.Model, tea.Cmd) {
+	before, findErr := m.groupSvc.GetGroupByID(msg.Group.GroupID)
 	err := m.groupSvc.DeleteGroup(msg.Group.GroupID)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to delete group: %v", err))
+		return m.handleError("delete group", err)
 	}
 	app := m.refreshDataForModels()
+	if findErr == nil {
+		app = app.recordChange(groupDeleted(m.groupSvc, before))
+	}
 	return app.SetSuccessStatus(fmt.Sprintf("Group '%s' deleted successfully", msg.Group.GroupName))
 }
 
 // handleGroupUpdateMsg handles the update of a category group.
 func (m App) handleGroupUpdateMsg(msg ui.GroupUpdateMsg) (tea.Model, tea.Cmd) {
+	before, findErr := m.groupSvc.GetGroupByID(msg.Group.GroupID)
 	err := m.groupSvc.UpdateGroup(msg.Group)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to update group: %v", err))
+		return m.handleGroupError("update group", msg.Group, err)
 	}
 	app := m.refreshDataForModels()
+	if findErr == nil {
+		app = app.recordChange(groupUpdated(m.groupSvc, before, msg.Group))
+	}
 	return app.SetSuccessStatus(fmt.Sprintf("Group '%s' updated successfully", msg.Group.GroupName))
 }
 
@@ -200,28 +274,35 @@ func (m App) handleIncomeViewMsg() (tea.Model, tea.Cmd) {
 func (m App) handleSaveIncomeMsg(msg ui.SaveIncomeMsg) (tea.Model, tea.Cmd) {
 	existingIncomes, _ := m.incomeSvc.GetIncomesForMonth(msg.MonthKey)
 	isUpdate := false
+	var before domain.IncomeRecord
 	for _, income := range existingIncomes {
 		if income.IncomeID == msg.Income.IncomeID {
 			isUpdate = true
+			before = income
 			break
 		}
 	}
 
 	var err error
 	var successMsg string
+	var op operation
 	if isUpdate {
 		err = m.incomeSvc.UpdateIncome(msg.MonthKey, msg.Income)
 		successMsg = fmt.Sprintf("Income '%s' updated successfully", msg.Income.Description)
+		op = incomeUpdated(m.incomeSvc, msg.MonthKey, before, msg.Income)
 	} else {
 		err = m.incomeSvc.AddIncome(msg.MonthKey, msg.Income)
 		successMsg = fmt.Sprintf("Income '%s' added successfully", msg.Income.Description)
+		op = incomeAdded(m.incomeSvc, msg.MonthKey, msg.Income)
 	}
 
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to save income: %v", err))
+		m.IncomeFormModel = m.IncomeFormModel.SetFieldErrors(domain.FieldErrors(err))
+		return m.handleError("save income", err)
 	}
 
 	app := m.refreshDataForModels()
+	app = app.recordChange(op)
 	app.activeView = viewIncome
 	return app.SetSuccessStatus(successMsg)
 }
@@ -237,12 +318,119 @@ func (m App) handleEditIncomeMsg(msg ui.EditIncomeMsg) (tea.Model, tea.Cmd) {
 func (m App) handleDeleteIncomeMsg(msg ui.DeleteIncomeMsg) (tea.Model, tea.Cmd) {
 	err := m.incomeSvc.DeleteIncome(msg.MonthKey, msg.Income.IncomeID)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to delete income: %v", err))
+		return m.handleError("delete income", err)
 	}
 	app := m.refreshDataForModels()
+	app = app.recordChange(incomeDeleted(m.incomeSvc, msg.MonthKey, msg.Income))
 	return app.SetSuccessStatus(fmt.Sprintf("Income '%s' has been deleted", msg.Income.Description))
 }
 
+// handleRatesViewMsg handles the display of the exchange rates.
+func (m App) handleRatesViewMsg() (tea.Model, tea.Cmd) {
+	app := m.refreshDataForModels()
+	app.activeView = viewRates
+	return app, nil
+}
+
+// handleSaveRateMsg handles adding or replacing an exchange rate.
+func (m App) handleSaveRateMsg(msg ui.SaveRateMsg) (tea.Model, tea.Cmd) {
+	rate := msg.Rate
+	rate.Currency = domain.NormalizeCurrency(rate.Currency)
+	before, found := m.findRate(msg.MonthKey, rate.Currency)
+	err := m.rateSvc.SetRate(msg.MonthKey, rate)
+	if err != nil {
+		return m.handleError("save exchange rate", err)
+	}
+	app := m.refreshDataForModels()
+	app = app.recordChange(rateSet(m.rateSvc, msg.MonthKey, before, found, rate))
+	return app.SetSuccessStatus(fmt.Sprintf("Exchange rate for %s saved successfully", msg.Rate.Currency))
+}
+
+// handleDeleteRateMsg handles the deletion of an exchange rate.
+func (m App) handleDeleteRateMsg(msg ui.DeleteRateMsg) (tea.Model, tea.Cmd) {
+	before, found := m.findRate(msg.MonthKey, domain.NormalizeCurrency(msg.Rate.Currency))
+	err := m.rateSvc.DeleteRate(msg.MonthKey, msg.Rate.Currency)
+	if err != nil {
+		return m.handleError("delete exchange rate", err)
+	}
+	app := m.refreshDataForModels()
+	if found {
+		app = app.recordChange(rateDeleted(m.rateSvc, msg.MonthKey, before))
+	}
+	return app.SetSuccessStatus(fmt.Sprintf("Exchange rate for %s has been deleted", msg.Rate.Currency))
+}
+
+// handleTrendsViewMsg loads the months charted by the trends view, ending
+// at the current month, and displays it.
+func (m App) handleTrendsViewMsg(msg ui.TrendsViewMsg) (tea.Model, tea.Cmd) {
+	groups, err := m.groupSvc.GetAllGroups()
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to load trends: %v", err))
+	}
+
+	// The year before the shown months is loaded for year over year changes
+	last := domain.NewMonth(m.CurrentYear, m.CurrentMonth)
+	first := last.AddMonths(1 - msg.Months - ui.TrendHistoryMonths)
+	months, err := m.monthSvc.GetMonthRange(first, last)
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to load trends: %v", err))
+	}
+
+	m.TrendsModel = m.TrendsModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
+	m.TrendsModel = m.TrendsModel.UpdateData(groups, months, msg.Months)
+	m.activeView = viewTrends
+	return m, nil
+}
+
+// handleHistoryViewMsg loads the changes recorded for a month and a category
+// and displays them.
+func (m App) handleHistoryViewMsg(msg ui.HistoryViewMsg) (tea.Model, tea.Cmd) {
+	changes, err := m.journalSvc.GetChanges(domain.ChangeFilter{
+		MonthKey: msg.MonthKey,
+		Category: msg.Category,
+		Limit:    ui.HistoryLimit,
+	})
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to load history: %v", err))
+	}
+
+	m.HistoryModel = m.HistoryModel.SetMonthYear(m.CurrentMonth, m.CurrentYear)
+	m.HistoryModel = m.HistoryModel.UpdateData(changes, msg.MonthKey, msg.Category)
+	m.activeView = viewHistory
+	return m, nil
+}
+
+// handleExportMonthMsg exports the report of a month to the export directory.
+func (m App) handleExportMonthMsg(msg ui.ExportMonthMsg) (tea.Model, tea.Cmd) {
+	month, err := domain.ParseMonth(msg.MonthKey)
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to export month: %v", err))
+	}
+
+	exporter := export.New(m.groupSvc, m.monthSvc)
+	report, err := exporter.Build(month, month, viper.GetString(config.CurrencyField), config.DisplayCurrency())
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to export month: %v", err))
+	}
+
+	var b bytes.Buffer
+	if err := export.Write(&b, report, export.FormatMarkdown); err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to export month: %v", err))
+	}
+
+	exportDir := viper.GetString(config.ExportDirField)
+	if err := os.MkdirAll(exportDir, 0755); err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to export month: %v", err))
+	}
+	fileName := fmt.Sprintf("gocost-%s.%s", month, export.Extension(export.FormatMarkdown))
+	filePath := filepath.Join(exportDir, fileName)
+	if err := os.WriteFile(filePath, b.Bytes(), 0644); err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to export month: %v", err))
+	}
+
+	return m.SetSuccessStatus(fmt.Sprintf("%s %d exported to %s", month.Month, month.Year, filePath))
+}
+
 // handleManageGroupsMsg handles switching to the group management view.
 func (m App) handleManageGroupsMsg() (tea.Model, tea.Cmd) {
 	app := m.refreshDataForModels()
@@ -278,19 +466,25 @@ func (m App) handleSelectedGroupMsg(msg ui.SelectedGroupMsg) (tea.Model, tea.Cmd
 func (m App) handleCategoryAddMsg(msg ui.CategoryAddMsg) (tea.Model, tea.Cmd) {
 	err := m.categorySvc.AddCategory(msg.MonthKey, msg.Category)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to add category: %v", err))
+		return m.handleCategoryError("add category", msg.Category, err)
 	}
 	app := m.refreshDataForModels()
+	app = app.recordChange(categoryAdded(m.categorySvc, msg.MonthKey, msg.Category))
 	return app.SetSuccessStatus(fmt.Sprintf("Category '%s' has been created successfully", msg.Category.CategoryName))
 }
 
 // handleCategoryUpdateMsg handles the update of a category.
 func (m App) handleCategoryUpdateMsg(msg ui.CategoryUpdateMsg) (tea.Model, tea.Cmd) {
+	before, found := m.findCategory(msg.MonthKey, msg.Category.CatID)
 	err := m.categorySvc.UpdateCategory(msg.MonthKey, msg.Category)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to update category: %v", err))
+		return m.handleCategoryError("update category", msg.Category, err)
 	}
 	app := m.refreshDataForModels()
+	if found {
+		description := fmt.Sprintf("update category '%s'", msg.Category.CategoryName)
+		app = app.recordChange(categoryUpdated(m.categorySvc, description, msg.MonthKey, before, msg.Category))
+	}
 	// After moving a category, reset the state in the UI model
 	if app.CategoryModel.IsMovingCategory() {
 		app.CategoryModel = app.CategoryModel.ResetMoveState()
@@ -300,11 +494,15 @@ func (m App) handleCategoryUpdateMsg(msg ui.CategoryUpdateMsg) (tea.Model, tea.C
 
 // handleCategoryDeleteMsg handles the deletion of a category.
 func (m App) handleCategoryDeleteMsg(msg ui.CategoryDeleteMsg) (tea.Model, tea.Cmd) {
+	before, found := m.findCategory(msg.MonthKey, msg.Category.CatID)
 	err := m.categorySvc.DeleteCategory(msg.MonthKey, msg.Category.CatID)
 	if err != nil {
-		return m.SetErrorStatus(fmt.Sprintf("Failed to delete category: %v", err))
+		return m.handleError("delete category", err)
 	}
 	app := m.refreshDataForModels()
+	if found {
+		app = app.recordChange(categoryDeleted(m.categorySvc, msg.MonthKey, before))
+	}
 	return app.SetSuccessStatus(fmt.Sprintf("Category '%s' has been deleted", msg.Category.CategoryName))
 }
 
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/ui"
+)
+
+// ProfileOpener opens the data of the profile named name and returns an App
+// working on it, with a function releasing the resources it holds.
+type ProfileOpener func(name string) (App, func(), error)
+
+// WithProfiles enables switching profiles. profile is the name of the open
+// profile and closeSession releases its resources when another is opened.
+func (m App) WithProfiles(profile string, open ProfileOpener, closeSession func()) App {
+	m.profile = profile
+	m.openProfile = open
+	m.closeSession = closeSession
+	return m
+}
+
+// Close releases the resources of the open profile.
+func (m App) Close() {
+	if m.closeSession != nil {
+		m.closeSession()
+	}
+}
+
+// handleProfilesViewMsg displays the profiles of the config file.
+func (m App) handleProfilesViewMsg() (tea.Model, tea.Cmd) {
+	if m.openProfile == nil {
+		return m.SetErrorStatus("Switching profiles is not available")
+	}
+	profiles, err := config.Profiles()
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to load profiles: %v", err))
+	}
+	m.ProfileModel = m.ProfileModel.UpdateData(profiles, m.profile)
+	m.activeView = viewProfiles
+	return m, nil
+}
+
+// handleSwitchProfileMsg replaces the application with one working on the
+// data of another profile. The month shown and the window size are kept,
+// while the undo history belongs to the previous profile and is dropped.
+func (m App) handleSwitchProfileMsg(msg ui.SwitchProfileMsg) (tea.Model, tea.Cmd) {
+	if msg.Name == m.profile {
+		return m.handleMonthlyViewMsg()
+	}
+	if m.openProfile == nil {
+		return m.SetErrorStatus("Switching profiles is not available")
+	}
+
+	next, closeNext, err := m.openProfile(msg.Name)
+	if err != nil {
+		return m.SetErrorStatus(fmt.Sprintf("Failed to open profile '%s': %v", msg.Name, err))
+	}
+	m.Close()
+
+	next = next.WithProfiles(msg.Name, m.openProfile, closeNext)
+	next.MonthYear = m.MonthYear
+	next = next.refreshDataForModels()
+	model, resizeCmd := next.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.Height})
+	next = model.(App)
+
+	next, statusCmd := next.SetSuccessStatus(fmt.Sprintf("Switched to profile '%s'", msg.Name))
+	return next, tea.Batch(resizeCmd, next.Init(), statusCmd)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSwitchProfileMsg(t *testing.T) {
+	household := createTestAppWithMocks(t)
+	require.NoError(t, household.groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Shared"}))
+
+	closed := map[string]bool{}
+	open := func(name string) (App, func(), error) {
+		if name != "household" {
+			return App{}, nil, errors.New("unknown profile")
+		}
+		return household, func() { closed[name] = true }, nil
+	}
+
+	app := createTestAppWithMocks(t)
+	model, _ := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	assert.Contains(t, model.(App).GetStatusMessage(), "not available")
+
+	app = app.WithProfiles("default", open, func() { closed["default"] = true })
+	app.Width, app.Height = 120, 40
+	app.CurrentYear = 2024
+
+	model, _ = app.handleSwitchProfileMsg(ui.SwitchProfileMsg{Name: "work"})
+	failed := model.(App)
+	assert.Contains(t, failed.GetStatusMessage(), "Failed to open profile 'work'")
+	assert.False(t, closed["default"])
+
+	model, cmd := app.handleSwitchProfileMsg(ui.SwitchProfileMsg{Name: "household"})
+	switched := model.(App)
+	assert.NotNil(t, cmd)
+	assert.True(t, closed["default"], "Expected the previous profile to be closed")
+	assert.Equal(t, "household", switched.profile)
+	assert.Equal(t, 2024, switched.CurrentYear)
+	assert.Equal(t, 120, switched.Width)
+	assert.Contains(t, switched.GetStatusMessage(), "Switched to profile 'household'")
+
+	groups, err := switched.groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Shared", groups[0].GroupName)
+
+	switched.Close()
+	assert.True(t, closed["household"])
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/madalinpopa/gocost/internal/domain"
 	"github.com/madalinpopa/gocost/internal/service"
 	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -29,7 +30,11 @@ func createTestAppWithMocks(t *testing.T) App {
 	categorySvc := service.NewCategoryService(repo)
 	groupSvc := service.NewGroupService(repo)
 	incomeSvc := service.NewIncomeService(repo)
-	return New(categorySvc, groupSvc, incomeSvc, repo.FilePath())
+	rateSvc := service.NewRateService(repo)
+	recurringSvc := service.NewRecurringService(repo, repo, repo)
+	monthSvc := service.NewMonthService(repo)
+	journalSvc := service.NewJournalService(data.NewJournal(data.JournalPath(repo.FilePath())))
+	return New(categorySvc, groupSvc, incomeSvc, rateSvc, recurringSvc, monthSvc, journalSvc, repo.FilePath())
 }
 
 func TestSetStatus(t *testing.T) {
@@ -125,7 +130,11 @@ func TestToggleExpenseStatus(t *testing.T) {
 	categorySvc := service.NewCategoryService(repo)
 	groupSvc := service.NewGroupService(repo)
 	incomeSvc := service.NewIncomeService(repo)
-	app := New(categorySvc, groupSvc, incomeSvc, repo.FilePath())
+	rateSvc := service.NewRateService(repo)
+	recurringSvc := service.NewRecurringService(repo, repo, repo)
+	monthSvc := service.NewMonthService(repo)
+	journalSvc := service.NewJournalService(data.NewJournal(data.JournalPath(repo.FilePath())))
+	app := New(categorySvc, groupSvc, incomeSvc, rateSvc, recurringSvc, monthSvc, journalSvc, repo.FilePath())
 	monthKey := ui.GetMonthKey(app.CurrentMonth, app.CurrentYear)
 
 	// Create test data
@@ -135,8 +144,8 @@ func TestToggleExpenseStatus(t *testing.T) {
 		CategoryName: "Test Category",
 		Expense: map[string]domain.ExpenseRecord{
 			"cat1": {
-				Amount: 100.0,
-				Budget: 120.0,
+				Amount: decimal.NewFromInt(100),
+				Budget: decimal.NewFromInt(120),
 				Status: "Not Paid",
 				Notes:  "Test expense",
 			},
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+)
+
+// maxUndo is the number of changes of a session that can be undone.
+const maxUndo = 100
+
+// operation is a change made during the session, with the service calls
+// that revert and replay it.
+type operation struct {
+	description string
+	undo        func() error
+	redo        func() error
+}
+
+// undoHistory holds the changes of the session that can be undone and the
+// undone ones that can be redone, the most recent last.
+type undoHistory struct {
+	done   []operation
+	undone []operation
+}
+
+// push adds a new change. Changes that were undone can no longer be redone.
+func (h undoHistory) push(op operation) undoHistory {
+	done := append(h.done, op)
+	if len(done) > maxUndo {
+		done = done[len(done)-maxUndo:]
+	}
+	return undoHistory{done: done}
+}
+
+// recordChange adds a change made by a handler to the undo history.
+func (m App) recordChange(op operation) App {
+	m.history = m.history.push(op)
+	return m
+}
+
+// acceptsUndo reports whether the undo and redo keys are handled, which is
+// the case in lists but not while text is typed in.
+func (m App) acceptsUndo() bool {
+	switch m.activeView {
+	case viewMonthlyOverview, viewIncome, viewHistory:
+		return true
+	case viewCategory:
+		return !m.CategoryModel.IsEditing()
+	case viewCategoryGroup:
+		return !m.CategoryGroupModel.IsEditing()
+	case viewRates:
+		return !m.RatesModel.IsEditing()
+	}
+	return false
+}
+
+// handleUndo reverts the most recent change of the session.
+func (m App) handleUndo() (tea.Model, tea.Cmd) {
+	if len(m.history.done) == 0 {
+		return m.SetErrorStatus("Nothing to undo")
+	}
+	op := m.history.done[len(m.history.done)-1]
+	if err := op.undo(); err != nil {
+		return m.handleError("undo "+op.description, err)
+	}
+	m.history.done = m.history.done[:len(m.history.done)-1]
+	m.history.undone = append(m.history.undone, op)
+
+	app, cmd := m.refreshActiveView()
+	app, statusCmd := app.SetSuccessStatus("Undone: " + op.description)
+	return app, tea.Batch(cmd, statusCmd)
+}
+
+// handleRedo replays the most recently undone change.
+func (m App) handleRedo() (tea.Model, tea.Cmd) {
+	if len(m.history.undone) == 0 {
+		return m.SetErrorStatus("Nothing to redo")
+	}
+	op := m.history.undone[len(m.history.undone)-1]
+	if err := op.redo(); err != nil {
+		return m.handleError("redo "+op.description, err)
+	}
+	m.history.undone = m.history.undone[:len(m.history.undone)-1]
+	m.history.done = append(m.history.done, op)
+
+	app, cmd := m.refreshActiveView()
+	app, statusCmd := app.SetSuccessStatus("Redone: " + op.description)
+	return app, tea.Batch(cmd, statusCmd)
+}
+
+// findCategory returns the stored category of a month with the given ID.
+func (m App) findCategory(monthKey string, categoryID string) (domain.Category, bool) {
+	categories, _ := m.categorySvc.GetCategoriesForMonth(monthKey)
+	for _, category := range categories {
+		if category.CatID == categoryID {
+			return category, true
+		}
+	}
+	return domain.Category{}, false
+}
+
+// findRate returns the stored exchange rate of a currency for a month.
+func (m App) findRate(monthKey string, currency string) (domain.ExchangeRate, bool) {
+	rates, _ := m.rateSvc.GetRatesForMonth(monthKey)
+	for _, rate := range rates {
+		if rate.Currency == currency {
+			return rate, true
+		}
+	}
+	return domain.ExchangeRate{}, false
+}
+
+// groupAdded returns the operation of adding group.
+func groupAdded(groups *service.GroupService, group domain.CategoryGroup) operation {
+	return operation{
+		description: fmt.Sprintf("add group '%s'", group.GroupName),
+		undo:        func() error { return groups.DeleteGroup(group.GroupID) },
+		redo:        func() error { return groups.AddGroup(group) },
+	}
+}
+
+// groupUpdated returns the operation of replacing group before with after.
+func groupUpdated(groups *service.GroupService, before, after domain.CategoryGroup) operation {
+	return operation{
+		description: fmt.Sprintf("update group '%s'", after.GroupName),
+		undo:        func() error { return groups.UpdateGroup(before) },
+		redo:        func() error { return groups.UpdateGroup(after) },
+	}
+}
+
+// groupDeleted returns the operation of deleting group.
+func groupDeleted(groups *service.GroupService, group domain.CategoryGroup) operation {
+	return operation{
+		description: fmt.Sprintf("delete group '%s'", group.GroupName),
+		undo:        func() error { return groups.AddGroup(group) },
+		redo:        func() error { return groups.DeleteGroup(group.GroupID) },
+	}
+}
+
+// categoryAdded returns the operation of adding category to a month.
+func categoryAdded(categories *service.CategoryService, monthKey string, category domain.Category) operation {
+	category = category.Clone()
+	return operation{
+		description: fmt.Sprintf("add category '%s'", category.CategoryName),
+		undo:        func() error { return categories.DeleteCategory(monthKey, category.CatID) },
+		redo:        func() error { return categories.AddCategory(monthKey, category) },
+	}
+}
+
+// categoryUpdated returns the operation of replacing the category before of
+// a month with after.
+func categoryUpdated(categories *service.CategoryService, description, monthKey string, before, after domain.Category) operation {
+	before, after = before.Clone(), after.Clone()
+	return operation{
+		description: description,
+		undo:        func() error { return categories.UpdateCategory(monthKey, before) },
+		redo:        func() error { return categories.UpdateCategory(monthKey, after) },
+	}
+}
+
+// categoryDeleted returns the operation of deleting category from a month.
+func categoryDeleted(categories *service.CategoryService, monthKey string, category domain.Category) operation {
+	category = category.Clone()
+	return operation{
+		description: fmt.Sprintf("delete category '%s'", category.CategoryName),
+		undo:        func() error { return categories.AddCategory(monthKey, category) },
+		redo:        func() error { return categories.DeleteCategory(monthKey, category.CatID) },
+	}
+}
+
+// categoriesCopied returns the operation of copying categories to a month,
+// which replaced the categories before of the month with after.
+func categoriesCopied(categories *service.CategoryService, fromMonthKey, monthKey string, before, after []domain.Category) operation {
+	return operation{
+		description: fmt.Sprintf("copy %d categories from %s", len(after), fromMonthKey),
+		undo:        func() error { return categories.ReplaceCategoriesForMonth(monthKey, before) },
+		redo:        func() error { return categories.ReplaceCategoriesForMonth(monthKey, after) },
+	}
+}
+
+// incomeAdded returns the operation of adding income to a month.
+func incomeAdded(incomes *service.IncomeService, monthKey string, income domain.IncomeRecord) operation {
+	return operation{
+		description: fmt.Sprintf("add income '%s'", income.Description),
+		undo:        func() error { return incomes.DeleteIncome(monthKey, income.IncomeID) },
+		redo:        func() error { return incomes.AddIncome(monthKey, income) },
+	}
+}
+
+// incomeUpdated returns the operation of replacing the income before of a
+// month with after.
+func incomeUpdated(incomes *service.IncomeService, monthKey string, before, after domain.IncomeRecord) operation {
+	return operation{
+		description: fmt.Sprintf("update income '%s'", after.Description),
+		undo:        func() error { return incomes.UpdateIncome(monthKey, before) },
+		redo:        func() error { return incomes.UpdateIncome(monthKey, after) },
+	}
+}
+
+// incomeDeleted returns the operation of deleting income from a month.
+func incomeDeleted(incomes *service.IncomeService, monthKey string, income domain.IncomeRecord) operation {
+	return operation{
+		description: fmt.Sprintf("delete income '%s'", income.Description),
+		undo:        func() error { return incomes.AddIncome(monthKey, income) },
+		redo:        func() error { return incomes.DeleteIncome(monthKey, income.IncomeID) },
+	}
+}
+
+// rateSet returns the operation of setting the exchange rate after of a
+// month, which replaced before when found is true.
+func rateSet(rates *service.RateService, monthKey string, before domain.ExchangeRate, found bool, after domain.ExchangeRate) operation {
+	return operation{
+		description: fmt.Sprintf("set exchange rate for %s", after.Currency),
+		undo: func() error {
+			if found {
+				return rates.SetRate(monthKey, before)
+			}
+			return rates.DeleteRate(monthKey, after.Currency)
+		},
+		redo: func() error { return rates.SetRate(monthKey, after) },
+	}
+}
+
+// rateDeleted returns the operation of deleting the exchange rate of a month.
+func rateDeleted(rates *service.RateService, monthKey string, rate domain.ExchangeRate) operation {
+	return operation{
+		description: fmt.Sprintf("delete exchange rate for %s", rate.Currency),
+		undo:        func() error { return rates.SetRate(monthKey, rate) },
+		redo:        func() error { return rates.DeleteRate(monthKey, rate.Currency) },
+	}
+}
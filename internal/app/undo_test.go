@@ -0,0 +1,142 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoRedo(t *testing.T) {
+	app := createTestAppWithMocks(t)
+	monthKey := ui.GetMonthKey(app.CurrentMonth, app.CurrentYear)
+	press := func(app App, key tea.KeyMsg) App {
+		t.Helper()
+		model, _ := app.Update(key)
+		return model.(App)
+	}
+	undoKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")}
+	redoKey := tea.KeyMsg{Type: tea.KeyCtrlR}
+
+	app = press(app, undoKey)
+	assert.Contains(t, app.GetStatusMessage(), "Nothing to undo")
+
+	require.NoError(t, app.groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+	rent := domain.Category{
+		CatID:        "c1",
+		GroupID:      "g1",
+		CategoryName: "Rent",
+		Expense: map[string]domain.ExpenseRecord{
+			"c1": {Amount: decimal.NewFromInt(900), Budget: decimal.NewFromInt(900), Status: "Paid"},
+		},
+	}
+	require.NoError(t, app.categorySvc.AddCategory(monthKey, rent))
+	app = app.refreshDataForModels()
+
+	categories := func(app App) []domain.Category {
+		t.Helper()
+		categories, err := app.categorySvc.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		return categories
+	}
+
+	t.Run("Delete category", func(t *testing.T) {
+		model, _ := app.handleCategoryDeleteMsg(ui.CategoryDeleteMsg{MonthKey: monthKey, Category: rent})
+		app := model.(App)
+		assert.Empty(t, categories(app))
+
+		app = press(app, undoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Undone: delete category 'Rent'")
+		restored := categories(app)
+		require.Len(t, restored, 1)
+		assert.Equal(t, "900", restored[0].Expense["c1"].Amount.String())
+
+		app = press(app, redoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Redone: delete category 'Rent'")
+		assert.Empty(t, categories(app))
+
+		app = press(app, redoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Nothing to redo")
+		app = press(app, undoKey)
+		assert.Len(t, categories(app), 1)
+	})
+
+	t.Run("Clear expense", func(t *testing.T) {
+		model, _ := app.handleDeleteExpenseMsg(ui.DeleteExpenseMsg{MonthKey: monthKey, Category: categories(app)[0]})
+		app := model.(App)
+		assert.True(t, categories(app)[0].Expense["c1"].Amount.IsZero())
+
+		app = press(app, undoKey)
+		expense := categories(app)[0].Expense["c1"]
+		assert.Equal(t, "900", expense.Amount.String())
+		assert.Equal(t, "Paid", expense.Status)
+	})
+
+	t.Run("New change discards redo", func(t *testing.T) {
+		model, _ := app.handleSaveRateMsg(ui.SaveRateMsg{MonthKey: monthKey, Rate: domain.ExchangeRate{Currency: "eur", Rate: decimal.NewFromFloat(1.1)}})
+		app := model.(App)
+		model, _ = app.handleSaveRateMsg(ui.SaveRateMsg{MonthKey: monthKey, Rate: domain.ExchangeRate{Currency: "EUR", Rate: decimal.NewFromFloat(1.2)}})
+		app = model.(App)
+
+		app = press(app, undoKey)
+		rate, found := app.findRate(monthKey, "EUR")
+		require.True(t, found)
+		assert.Equal(t, "1.1", rate.Rate.String())
+
+		model, _ = app.handleGroupAddMsg(ui.GroupAddMsg{Group: domain.CategoryGroup{GroupID: "g2", GroupName: "Living"}})
+		app = model.(App)
+		app = press(app, redoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Nothing to redo")
+
+		app = press(app, undoKey)
+		_, err := app.groupSvc.GetGroupByID("g2")
+		assert.Error(t, err)
+		app = press(app, undoKey)
+		_, found = app.findRate(monthKey, "EUR")
+		assert.False(t, found)
+	})
+
+	t.Run("Copy categories", func(t *testing.T) {
+		prevYear, prevMonth := ui.GetPreviousMonth(app.CurrentYear, app.CurrentMonth)
+		previousMonthKey := ui.GetMonthKey(prevMonth, prevYear)
+		require.NoError(t, app.categorySvc.AddCategory(previousMonthKey, domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Water"}))
+
+		msg := ui.PopulateCategoriesMsg{CurrentMonthKey: monthKey, PreviousMonthKey: previousMonthKey}
+		model, _ := app.handlePopulateCategoriesMsg(msg)
+		app := model.(App)
+		copied := categories(app)
+		require.Len(t, copied, 1)
+		assert.Equal(t, "Water", copied[0].CategoryName)
+
+		// Undoing restores the categories replaced by the copy, with their expenses
+		app = press(app, undoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Undone: copy 1 categories")
+		restored := categories(app)
+		require.Len(t, restored, 1)
+		assert.Equal(t, "Rent", restored[0].CategoryName)
+		assert.Equal(t, "900", restored[0].Expense["c1"].Amount.String())
+
+		app = press(app, redoKey)
+		assert.Equal(t, "Water", categories(app)[0].CategoryName)
+
+		// Copying again replaces the copy, and undoing it restores the first copy
+		model, _ = app.handlePopulateCategoriesMsg(msg)
+		app = model.(App)
+		app = press(app, undoKey)
+		assert.Contains(t, app.GetStatusMessage(), "Undone: copy 1 categories")
+		app = press(app, undoKey)
+		assert.Equal(t, "Rent", categories(app)[0].CategoryName)
+	})
+
+	t.Run("Not while typing", func(t *testing.T) {
+		app := app
+		app.activeView = viewExpense
+		assert.False(t, app.acceptsUndo())
+		app.activeView = viewCategory
+		assert.True(t, app.acceptsUndo())
+	})
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Reloader is implemented by repositories that can pick up changes made to
+// their data file by other programs.
+type Reloader interface {
+	Reload() (bool, error)
+}
+
+// DataFileChangedMsg is sent when the data file was changed on disk.
+type DataFileChangedMsg struct{}
+
+// WatchDataFile reloads the data whenever a value is received from changes.
+func (m App) WatchDataFile(reloader Reloader, changes <-chan struct{}) App {
+	m.reloader = reloader
+	m.fileChanges = changes
+	return m
+}
+
+// waitForDataFileChange returns a command waiting for the next change of the
+// data file, or nil when the file is not watched.
+func (m App) waitForDataFileChange() tea.Cmd {
+	if m.reloader == nil || m.fileChanges == nil {
+		return nil
+	}
+	changes := m.fileChanges
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return DataFileChangedMsg{}
+	}
+}
+
+// handleDataFileChangedMsg reloads the data file and refreshes the views
+// when it was changed by another program.
+func (m App) handleDataFileChangedMsg() (tea.Model, tea.Cmd) {
+	wait := m.waitForDataFileChange()
+
+	reloaded, err := m.reloader.Reload()
+	if err != nil {
+		app, cmd := m.SetErrorStatus(fmt.Sprintf("Failed to reload data file: %v", err))
+		return app, tea.Batch(cmd, wait)
+	}
+	if !reloaded {
+		return m, wait
+	}
+
+	app, cmd := m.refreshActiveView()
+	app, statusCmd := app.SetSuccessStatus("Data file changed on disk and was reloaded")
+	return app, tea.Batch(cmd, statusCmd, wait)
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleDataFileChangedMsg(t *testing.T) {
+	repo := mockRepo(t)
+	app := New(
+		service.NewCategoryService(repo),
+		service.NewGroupService(repo),
+		service.NewIncomeService(repo),
+		service.NewRateService(repo),
+		service.NewRecurringService(repo, repo, repo),
+		service.NewMonthService(repo),
+		service.NewJournalService(data.NewJournal(data.JournalPath(repo.FilePath()))),
+		repo.FilePath(),
+	)
+	assert.Nil(t, app.Init(), "Expected no command when the data file is not watched")
+
+	app = app.WatchDataFile(repo, make(chan struct{}))
+	assert.NotNil(t, app.Init())
+
+	model, cmd := app.handleDataFileChangedMsg()
+	assert.False(t, model.(App).HasStatus(), "Expected no status when the file did not change")
+	assert.NotNil(t, cmd)
+
+	other, err := data.NewJsonRepository(repo.FilePath(), "USD")
+	require.NoError(t, err)
+	require.NoError(t, other.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+
+	model, _ = app.handleDataFileChangedMsg()
+	updated := model.(App)
+	assert.True(t, updated.HasStatus())
+
+	groups, err := updated.groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Housing", groups[0].GroupName)
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+)
+
+// categoryList prints the categories of a month together with their group.
+func (c *CLI) categoryList(args []string) error {
+	fs := c.newFlagSet("category list")
+	month := monthFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	categories, err := c.categorySvc.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return err
+	}
+	if len(categories) == 0 {
+		_, err := fmt.Fprintf(c.out, "No categories for %s.\n", monthKey)
+		return err
+	}
+
+	groupNames := make(map[string]string)
+	if groups, err := c.groupSvc.GetAllGroups(); err == nil {
+		for _, group := range groups {
+			groupNames[group.GroupID] = group.GroupName
+		}
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "GROUP\tCATEGORY\tID")
+	for _, category := range categories {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", groupNames[category.GroupID], category.CategoryName, category.CatID)
+	}
+	return w.Flush()
+}
+
+// categoryAdd creates a new category in a group for a month.
+func (c *CLI) categoryAdd(args []string) error {
+	fs := c.newFlagSet("category add")
+	month := monthFlag(fs)
+	groupRef := fs.String("group", "", "Name or ID of the group")
+	name := fs.String("name", "", "Name of the category")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("group", *groupRef); err != nil {
+		return err
+	}
+	if err := requireFlag("name", *name); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	group, err := c.findGroup(*groupRef)
+	if err != nil {
+		return err
+	}
+
+	category := domain.Category{
+		CatID:        ui.GenerateID(),
+		GroupID:      group.GroupID,
+		CategoryName: strings.TrimSpace(*name),
+		Expense:      make(map[string]domain.ExpenseRecord),
+	}
+	if err := c.categorySvc.AddCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to add category: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Category '%s' added to '%s' for %s (ID: %s)\n",
+		category.CategoryName, group.GroupName, monthKey, category.CatID)
+	return err
+}
+
+// categoryUpdate renames a category or moves it to another group.
+func (c *CLI) categoryUpdate(args []string) error {
+	fs := c.newFlagSet("category update")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	name := fs.String("name", "", "New name of the category")
+	groupRef := fs.String("group", "", "Name or ID of the group to move the category to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*name) != "" {
+		category.CategoryName = strings.TrimSpace(*name)
+	}
+	if strings.TrimSpace(*groupRef) != "" {
+		group, err := c.findGroup(*groupRef)
+		if err != nil {
+			return err
+		}
+		category.GroupID = group.GroupID
+	}
+
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Category '%s' updated\n", category.CategoryName)
+	return err
+}
+
+// categoryDelete removes a category from a month.
+func (c *CLI) categoryDelete(args []string) error {
+	fs := c.newFlagSet("category delete")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if err := c.categorySvc.DeleteCategory(monthKey, category.CatID); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Category '%s' deleted\n", category.CategoryName)
+	return err
+}
+
+// categoryCopy copies the categories of the previous month, or of -from, into a month.
+func (c *CLI) categoryCopy(args []string) error {
+	fs := c.newFlagSet("category copy")
+	month := monthFlag(fs)
+	from := fs.String("from", "", "Month to copy from in YYYY-MM format (defaults to the previous month)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	var fromKey string
+	if strings.TrimSpace(*from) != "" {
+		fromKey, err = parseMonthKey(*from)
+	} else {
+		fromKey, err = previousMonthKey(*month)
+	}
+	if err != nil {
+		return err
+	}
+
+	count, err := c.categorySvc.CopyCategoriesFromMonth(fromKey, monthKey)
+	if err != nil {
+		return fmt.Errorf("failed to copy categories: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Copied %d categories from %s to %s\n", count, fromKey, monthKey)
+	return err
+}
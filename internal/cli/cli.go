@@ -0,0 +1,345 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+)
+
+const (
+	// monthLayout is the layout accepted by the -month flag.
+	monthLayout = domain.MonthLayout
+
+	// entryDateLayout is the layout accepted by the -date flag of expense entries.
+	entryDateLayout = "2006-01-02"
+)
+
+// ErrUsage is returned when a command is invoked with missing or invalid arguments.
+var ErrUsage = errors.New("invalid usage")
+
+// IsRecoveryCommand reports whether the command name works on the data file
+// without loading it first, so that it runs when the data file is damaged.
+func IsRecoveryCommand(name string) bool {
+	return name == "doctor" || name == "restore"
+}
+
+// handlerFunc executes a single subcommand action with its remaining arguments.
+type handlerFunc func(args []string) error
+
+// command describes a top-level subcommand and its actions. Commands
+// without actions are handled by run.
+type command struct {
+	summary string
+	actions map[string]handlerFunc
+	run     handlerFunc
+}
+
+// CLI runs non-interactive subcommands against the application services.
+type CLI struct {
+	out          io.Writer
+	dataFilePath string
+	backups      *data.Backups
+	encrypter    Encrypter
+	openDoctor   func() (Doctor, error)
+
+	categorySvc  *service.CategoryService
+	groupSvc     *service.GroupService
+	incomeSvc    *service.IncomeService
+	rateSvc      *service.RateService
+	recurringSvc *service.RecurringService
+	monthSvc     *service.MonthService
+	journalSvc   *service.JournalService
+
+	commands map[string]command
+}
+
+// New creates a new CLI that writes its output to out. backups may be nil
+// when the storage backend does not keep backups.
+func New(
+	categoryService *service.CategoryService,
+	groupService *service.GroupService,
+	incomeService *service.IncomeService,
+	rateService *service.RateService,
+	recurringService *service.RecurringService,
+	monthService *service.MonthService,
+	journalService *service.JournalService,
+	dataFilePath string,
+	backups *data.Backups,
+	out io.Writer,
+) *CLI {
+	c := &CLI{
+		out:          out,
+		dataFilePath: dataFilePath,
+		backups:      backups,
+		categorySvc:  categoryService,
+		groupSvc:     groupService,
+		incomeSvc:    incomeService,
+		rateSvc:      rateService,
+		recurringSvc: recurringService,
+		monthSvc:     monthService,
+		journalSvc:   journalService,
+	}
+
+	c.commands = map[string]command{
+		"group": {
+			summary: "Manage category groups",
+			actions: map[string]handlerFunc{
+				"list":   c.groupList,
+				"add":    c.groupAdd,
+				"update": c.groupUpdate,
+				"delete": c.groupDelete,
+			},
+		},
+		"category": {
+			summary: "Manage the categories of a month",
+			actions: map[string]handlerFunc{
+				"list":   c.categoryList,
+				"add":    c.categoryAdd,
+				"update": c.categoryUpdate,
+				"delete": c.categoryDelete,
+				"copy":   c.categoryCopy,
+			},
+		},
+		"expense": {
+			summary: "Manage the expenses of a month",
+			actions: map[string]handlerFunc{
+				"list":         c.expenseList,
+				"set":          c.expenseSet,
+				"toggle":       c.expenseToggle,
+				"clear":        c.expenseClear,
+				"entries":      c.expenseEntries,
+				"add-entry":    c.expenseAddEntry,
+				"delete-entry": c.expenseDeleteEntry,
+			},
+		},
+		"income": {
+			summary: "Manage the incomes of a month",
+			actions: map[string]handlerFunc{
+				"list":   c.incomeList,
+				"add":    c.incomeAdd,
+				"update": c.incomeUpdate,
+				"delete": c.incomeDelete,
+			},
+		},
+		"import": {
+			summary: "Import transactions from bank statements and journals",
+			actions: map[string]handlerFunc{
+				"csv":       c.importCSV,
+				"ofx":       c.importOFX,
+				"qif":       c.importQIF,
+				"ledger":    c.importLedger,
+				"beancount": c.importBeancount,
+			},
+		},
+		"rate": {
+			summary: "Manage the exchange rates of a month",
+			actions: map[string]handlerFunc{
+				"list":   c.rateList,
+				"set":    c.rateSet,
+				"delete": c.rateDelete,
+			},
+		},
+		"recurring": {
+			summary: "Manage recurring expense and income templates",
+			actions: map[string]handlerFunc{
+				"list":     c.recurringList,
+				"add":      c.recurringAdd,
+				"update":   c.recurringUpdate,
+				"delete":   c.recurringDelete,
+				"skip":     c.recurringSkip,
+				"override": c.recurringOverride,
+				"apply":    c.recurringApply,
+			},
+		},
+		"export": {
+			summary: "Export a month or a range of months as CSV, JSON or Markdown",
+			run:     c.exportReport,
+		},
+		"encrypt": {
+			summary: "Encrypt the data file with a passphrase or change its passphrase",
+			run:     c.encrypt,
+		},
+		"decrypt": {
+			summary: "Store an encrypted data file in plain text",
+			run:     c.decrypt,
+		},
+		"log": {
+			summary: "Show the journal of changes made to the data",
+			run:     c.changeLog,
+		},
+		"doctor": {
+			summary: "Check the data file for inconsistencies and repair them",
+			run:     c.runDoctor,
+		},
+		"restore": {
+			summary: "List backups of the data file or restore one of them",
+			run:     c.restore,
+		},
+	}
+
+	return c
+}
+
+// IsCommand reports whether name is a known subcommand.
+func (c *CLI) IsCommand(name string) bool {
+	_, ok := c.commands[name]
+	return ok
+}
+
+// Run dispatches args to the matching subcommand action.
+func (c *CLI) Run(args []string) error {
+	if len(args) == 0 {
+		c.printUsage()
+		return ErrUsage
+	}
+
+	cmd, ok := c.commands[args[0]]
+	if !ok {
+		c.printUsage()
+		return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+	}
+
+	if cmd.run != nil {
+		return cmd.run(args[1:])
+	}
+
+	if len(args) < 2 {
+		c.printActions(args[0], cmd)
+		return fmt.Errorf("%w: missing action for %q", ErrUsage, args[0])
+	}
+
+	action, ok := cmd.actions[args[1]]
+	if !ok {
+		c.printActions(args[0], cmd)
+		return fmt.Errorf("%w: unknown action %q for %q", ErrUsage, args[1], args[0])
+	}
+
+	return action(args[2:])
+}
+
+// printUsage writes the list of available subcommands.
+func (c *CLI) printUsage() {
+	_, _ = fmt.Fprintln(c.out, "Usage: gocost [command] [action] [flags]")
+	_, _ = fmt.Fprintln(c.out, "\nCommands:")
+	for _, name := range sortedKeys(c.commands) {
+		_, _ = fmt.Fprintf(c.out, "  %-10s %s\n", name, c.commands[name].summary)
+	}
+	_, _ = fmt.Fprintln(c.out, "\nRun without a command to start the interactive interface.")
+	_, _ = fmt.Fprintln(c.out, "Run 'gocost demo' to explore the interface on sample data that is never saved.")
+}
+
+// printActions writes the list of actions available for a subcommand.
+func (c *CLI) printActions(name string, cmd command) {
+	_, _ = fmt.Fprintf(c.out, "Usage: gocost %s [action] [flags]\n", name)
+	_, _ = fmt.Fprintln(c.out, "\nActions:")
+	for _, action := range sortedKeys(cmd.actions) {
+		_, _ = fmt.Fprintf(c.out, "  %s\n", action)
+	}
+}
+
+// newFlagSet creates a flag set for the given command action that writes its usage to the CLI output.
+func (c *CLI) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	return fs
+}
+
+// monthFlag registers the -month flag on fs and returns a pointer to its value.
+func monthFlag(fs *flag.FlagSet) *string {
+	return fs.String("month", time.Now().Format(monthLayout), "Month in YYYY-MM format")
+}
+
+// parseMonth parses a YYYY-MM value.
+func parseMonth(value string) (domain.Month, error) {
+	month, err := domain.ParseMonth(value)
+	if err != nil {
+		return domain.Month{}, fmt.Errorf("%w: month must be in YYYY-MM format, got %q", ErrUsage, value)
+	}
+	return month, nil
+}
+
+// parseMonthKey converts a YYYY-MM value into the month key used by the repositories.
+func parseMonthKey(value string) (string, error) {
+	month, err := parseMonth(value)
+	if err != nil {
+		return "", err
+	}
+	return month.String(), nil
+}
+
+// previousMonthKey returns the month key of the month before the given YYYY-MM value.
+func previousMonthKey(value string) (string, error) {
+	month, err := parseMonth(value)
+	if err != nil {
+		return "", err
+	}
+	return month.Previous().String(), nil
+}
+
+// requireFlag returns a usage error when value is empty.
+func requireFlag(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%w: -%s is required", ErrUsage, name)
+	}
+	return nil
+}
+
+// findGroup looks up a group by ID or, case-insensitively, by name.
+func (c *CLI) findGroup(ref string) (domain.CategoryGroup, error) {
+	groups, err := c.groupSvc.GetAllGroups()
+	if err != nil {
+		return domain.CategoryGroup{}, err
+	}
+	for _, group := range groups {
+		if group.GroupID == ref || strings.EqualFold(group.GroupName, ref) {
+			return group, nil
+		}
+	}
+	return domain.CategoryGroup{}, fmt.Errorf("group %q not found", ref)
+}
+
+// findCategory looks up a category of the month by ID or, case-insensitively, by name.
+func (c *CLI) findCategory(monthKey, ref string) (domain.Category, error) {
+	categories, err := c.categorySvc.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	for _, category := range categories {
+		if category.CatID == ref || strings.EqualFold(category.CategoryName, ref) {
+			return category, nil
+		}
+	}
+	return domain.Category{}, fmt.Errorf("category %q not found in %s", ref, monthKey)
+}
+
+// findIncome looks up an income of the month by ID or, case-insensitively, by description.
+func (c *CLI) findIncome(monthKey, ref string) (domain.IncomeRecord, error) {
+	incomes, err := c.incomeSvc.GetIncomesForMonth(monthKey)
+	if err != nil {
+		return domain.IncomeRecord{}, err
+	}
+	for _, income := range incomes {
+		if income.IncomeID == ref || strings.EqualFold(income.Description, ref) {
+			return income, nil
+		}
+	}
+	return domain.IncomeRecord{}, fmt.Errorf("income %q not found in %s", ref, monthKey)
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
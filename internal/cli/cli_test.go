@@ -0,0 +1,548 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestCLI creates a CLI backed by a repository in a temporary directory.
+func setupTestCLI(t *testing.T) (*CLI, *bytes.Buffer) {
+	t.Helper()
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "test_data.json")
+	repo, err := data.NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	backups := data.NewBackups(filepath.Join(tempDir, "backups"), data.DefaultBackupLimit)
+	repo.SetBackups(backups)
+	journal := data.NewJournal(data.JournalPath(filePath))
+	repo.SetJournal(journal)
+	journaled := data.NewJournaledRepository(repo, journal)
+
+	out := &bytes.Buffer{}
+	c := New(
+		service.NewCategoryService(journaled),
+		service.NewGroupService(journaled),
+		service.NewIncomeService(journaled),
+		service.NewRateService(journaled),
+		service.NewRecurringService(journaled, journaled, journaled),
+		service.NewMonthService(journaled),
+		service.NewJournalService(journaled),
+		filePath,
+		backups,
+		out,
+	)
+	c.SetEncrypter(repo)
+	c.SetDoctor(func() (Doctor, error) { return repo, nil })
+	return c, out
+}
+
+func TestCLI_Dispatch(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	t.Run("no arguments", func(t *testing.T) {
+		err := c.Run(nil)
+		assert.ErrorIs(t, err, ErrUsage)
+		assert.Contains(t, out.String(), "Commands:")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		err := c.Run([]string{"unknown"})
+		assert.ErrorIs(t, err, ErrUsage)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		err := c.Run([]string{"group", "unknown"})
+		assert.ErrorIs(t, err, ErrUsage)
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		err := c.Run([]string{"income", "list", "-month", "June-2024"})
+		assert.ErrorIs(t, err, ErrUsage)
+	})
+}
+
+func TestCLI_GroupAndCategory(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-05", "-group", "housing", "-name", "Rent"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"category", "list", "-month", "2024-05"}))
+	assert.Contains(t, out.String(), "Housing")
+	assert.Contains(t, out.String(), "Rent")
+
+	require.NoError(t, c.Run([]string{"category", "copy", "-month", "2024-06"}))
+	categories, err := c.categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+
+	err = c.Run([]string{"group", "delete", "-group", "Housing"})
+	assert.Error(t, err)
+
+	require.NoError(t, c.Run([]string{"category", "delete", "-month", "2024-05", "-category", "Rent"}))
+	require.NoError(t, c.Run([]string{"category", "delete", "-month", "2024-06", "-category", "Rent"}))
+	require.NoError(t, c.Run([]string{"group", "delete", "-group", "Housing"}))
+
+	groups, err := c.groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
+func TestCLI_Expense(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Utilities"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-07", "-group", "Utilities", "-name", "Electricity"}))
+
+	require.NoError(t, c.Run([]string{"expense", "set", "-month", "2024-07", "-category", "Electricity", "-budget", "100", "-amount", "85.50"}))
+	category, err := c.findCategory("2024-07", "Electricity")
+	require.NoError(t, err)
+	expense := category.Expense[category.CatID]
+	assert.Equal(t, "85.5", expense.Amount.String())
+	assert.Equal(t, "100", expense.Budget.String())
+	assert.Equal(t, "Not Paid", expense.Status)
+
+	require.NoError(t, c.Run([]string{"expense", "set", "-month", "2024-07", "-category", "Electricity", "-status", "paid"}))
+	category, err = c.findCategory("2024-07", "Electricity")
+	require.NoError(t, err)
+	assert.Equal(t, "Paid", category.Expense[category.CatID].Status)
+	assert.Equal(t, "85.5", category.Expense[category.CatID].Amount.String())
+
+	require.NoError(t, c.Run([]string{"expense", "toggle", "-month", "2024-07", "-category", "Electricity"}))
+	category, err = c.findCategory("2024-07", "Electricity")
+	require.NoError(t, err)
+	assert.Equal(t, "Not Paid", category.Expense[category.CatID].Status)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"expense", "list", "-month", "2024-07"}))
+	assert.Contains(t, out.String(), "85.50")
+
+	require.NoError(t, c.Run([]string{"expense", "clear", "-month", "2024-07", "-category", "Electricity"}))
+	category, err = c.findCategory("2024-07", "Electricity")
+	require.NoError(t, err)
+	assert.True(t, category.Expense[category.CatID].Amount.IsZero())
+
+	err = c.Run([]string{"expense", "set", "-month", "2024-07", "-category", "Electricity", "-status", "maybe"})
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_ExpenseEntries(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Food"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-07", "-group", "Food", "-name", "Groceries"}))
+
+	require.NoError(t, c.Run([]string{"expense", "add-entry", "-month", "2024-07", "-category", "Groceries", "-date", "2024-07-02", "-description", "Market", "-amount", "40"}))
+	require.NoError(t, c.Run([]string{"expense", "add-entry", "-month", "2024-07", "-category", "Groceries", "-amount", "12.50"}))
+
+	category, err := c.findCategory("2024-07", "Groceries")
+	require.NoError(t, err)
+	expense := category.Expense[category.CatID]
+	require.Len(t, expense.Entries, 2)
+	assert.Equal(t, "52.5", expense.Amount.String())
+	assert.Equal(t, 1, expense.Entries[1].Date.Day())
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"expense", "entries", "-month", "2024-07", "-category", "Groceries"}))
+	assert.Contains(t, out.String(), "Market")
+	assert.Contains(t, out.String(), "52.50")
+
+	err = c.Run([]string{"expense", "add-entry", "-month", "2024-07", "-category", "Groceries", "-date", "2024-08-01", "-amount", "5"})
+	assert.ErrorIs(t, err, ErrUsage)
+
+	err = c.Run([]string{"expense", "set", "-month", "2024-07", "-category", "Groceries", "-amount", "10"})
+	assert.ErrorIs(t, err, ErrUsage)
+
+	require.NoError(t, c.Run([]string{"expense", "delete-entry", "-month", "2024-07", "-category", "Groceries", "-entry", expense.Entries[0].EntryID}))
+	category, err = c.findCategory("2024-07", "Groceries")
+	require.NoError(t, err)
+	assert.Len(t, category.Expense[category.CatID].Entries, 1)
+	assert.Equal(t, "12.5", category.Expense[category.CatID].Amount.String())
+}
+
+func TestCLI_Income(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-08", "-description", "Salary", "-amount", "5000"}))
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-08", "-description", "Bonus", "-amount", "250"}))
+
+	err := c.Run([]string{"income", "add", "-month", "2024-08", "-description", "Nothing", "-amount", "0"})
+	assert.ErrorIs(t, err, ErrUsage)
+
+	require.NoError(t, c.Run([]string{"income", "update", "-month", "2024-08", "-income", "salary", "-amount", "5500"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"income", "list", "-month", "2024-08"}))
+	assert.Contains(t, out.String(), "5500.00")
+	assert.Contains(t, out.String(), "5750.00")
+
+	require.NoError(t, c.Run([]string{"income", "delete", "-month", "2024-08", "-income", "Bonus"}))
+	incomes, err := c.incomeSvc.GetIncomesForMonth("2024-08")
+	require.NoError(t, err)
+	assert.Len(t, incomes, 1)
+}
+
+func TestCLI_Rates(t *testing.T) {
+	viper.Set(config.CurrencyField, "USD")
+	t.Cleanup(viper.Reset)
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-08", "-description", "Salary", "-amount", "1000"}))
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-08", "-description", "Freelance", "-amount", "100", "-currency", "eur"}))
+
+	// Without a rate the EUR income is left out of the total
+	out.Reset()
+	require.NoError(t, c.Run([]string{"income", "list", "-month", "2024-08"}))
+	assert.Contains(t, out.String(), "100.00 EUR")
+	assert.Contains(t, out.String(), "1000.00 USD")
+	assert.Contains(t, out.String(), "Missing exchange rates for EUR")
+
+	err := c.Run([]string{"rate", "set", "-month", "2024-08", "-currency", "USD", "-rate", "1"})
+	assert.ErrorIs(t, err, ErrUsage)
+	err = c.Run([]string{"rate", "set", "-month", "2024-08", "-currency", "EUR", "-rate", "-1"})
+	assert.ErrorIs(t, err, ErrUsage)
+
+	require.NoError(t, c.Run([]string{"rate", "set", "-month", "2024-08", "-currency", "EUR", "-rate", "1.1"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"rate", "list", "-month", "2024-08"}))
+	assert.Contains(t, out.String(), "1 EUR = 1.1 USD")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"income", "list", "-month", "2024-08"}))
+	assert.Contains(t, out.String(), "1110.00 USD")
+	assert.NotContains(t, out.String(), "Missing exchange rates")
+
+	require.NoError(t, c.Run([]string{"rate", "delete", "-month", "2024-08", "-currency", "eur"}))
+	rates, err := c.rateSvc.GetRatesForMonth("2024-08")
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+}
+
+func TestCLI_Recurring(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"recurring", "add", "-kind", "expense", "-name", "Rent", "-amount", "1200", "-budget", "1200", "-start", "2024-01"}))
+	require.NoError(t, c.Run([]string{"recurring", "add", "-kind", "income", "-name", "Salary", "-amount", "5000", "-start", "2024-01"}))
+	require.NoError(t, c.Run([]string{"recurring", "add", "-kind", "expense", "-name", "Insurance", "-budget", "300", "-every", "quarterly", "-start", "2024-01"}))
+
+	err := c.Run([]string{"recurring", "add", "-kind", "weekly", "-name", "Gym"})
+	assert.ErrorIs(t, err, ErrUsage)
+	err = c.Run([]string{"recurring", "add", "-kind", "income", "-name", "Bonus"})
+	assert.ErrorIs(t, err, ErrUsage)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"recurring", "list"}))
+	assert.Contains(t, out.String(), "quarterly")
+
+	require.NoError(t, c.Run([]string{"recurring", "skip", "-template", "salary", "-month", "2024-02"}))
+	require.NoError(t, c.Run([]string{"recurring", "override", "-template", "rent", "-month", "2024-02", "-amount", "1250"}))
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-02", "-group", "Housing", "-name", "Rent"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-02", "-group", "Housing", "-name", "Insurance"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"recurring", "apply", "-month", "2024-02"}))
+	assert.Contains(t, out.String(), "Applied 1 recurring templates")
+
+	incomes, err := c.incomeSvc.GetIncomesForMonth("2024-02")
+	require.NoError(t, err)
+	assert.Empty(t, incomes)
+
+	categories, err := c.categorySvc.GetCategoriesForMonth("2024-02")
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "1250", categories[0].Expense[categories[0].CatID].Amount.String())
+	assert.NotContains(t, categories[1].Expense, categories[1].CatID)
+
+	require.NoError(t, c.Run([]string{"recurring", "apply", "-month", "2024-03"}))
+	incomes, err = c.incomeSvc.GetIncomesForMonth("2024-03")
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	assert.Equal(t, "5000", incomes[0].Amount.String())
+
+	require.NoError(t, c.Run([]string{"recurring", "update", "-template", "Salary", "-end", "2024-03"}))
+	require.NoError(t, c.Run([]string{"recurring", "apply", "-month", "2024-04"}))
+	incomes, err = c.incomeSvc.GetIncomesForMonth("2024-04")
+	require.NoError(t, err)
+	assert.Empty(t, incomes)
+
+	require.NoError(t, c.Run([]string{"recurring", "delete", "-template", "Insurance"}))
+	templates, err := c.recurringSvc.GetAllTemplates()
+	require.NoError(t, err)
+	assert.Len(t, templates, 2)
+}
+
+func TestCLI_ImportCSV(t *testing.T) {
+	c, out := setupTestCLI(t)
+	dir := t.TempDir()
+
+	statement := filepath.Join(dir, "statement.csv")
+	require.NoError(t, os.WriteFile(statement, []byte("Date,Description,Amount\n2024-06-03,TESCO 123,-42.10\n2024-06-04,Unknown,-3.00\n"), 0644))
+	rules := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(rules, []byte(`[{"pattern": "tesco", "category": "Groceries", "group": "Living"}]`), 0644))
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Living"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "csv", "-file", statement, "-rules", rules, "-dry-run"}))
+	assert.Contains(t, out.String(), "1 new, 0 duplicate, 1 unmatched")
+	assert.Contains(t, out.String(), "Dry run")
+	categories, err := c.categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "csv", "-file", statement, "-rules", rules}))
+	assert.Contains(t, out.String(), "Imported 1 transactions")
+	categories, err = c.categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "42.1", categories[0].Expense[categories[0].CatID].Amount.String())
+
+	err = c.Run([]string{"import", "csv", "-file", statement, "-layout", "unknown"})
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_ImportOFX(t *testing.T) {
+	c, out := setupTestCLI(t)
+	dir := t.TempDir()
+
+	statement := filepath.Join(dir, "statement.ofx")
+	require.NoError(t, os.WriteFile(statement, []byte("<OFX><BANKTRANLIST>\n<STMTTRN>\n<DTPOSTED>20240603\n<TRNAMT>-42.10\n<FITID>1\n<NAME>TESCO 123\n</STMTTRN>\n</BANKTRANLIST></OFX>\n"), 0644))
+	rules := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(rules, []byte(`[{"pattern": "tesco", "category": "Groceries", "group": "Living"}]`), 0644))
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Living"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "ofx", "-file", statement, "-rules", rules}))
+	assert.Contains(t, out.String(), "Imported 1 transactions")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "ofx", "-file", statement, "-rules", rules, "-dry-run"}))
+	assert.Contains(t, out.String(), "0 new, 1 duplicate")
+}
+
+func TestCLI_ImportJournal(t *testing.T) {
+	c, out := setupTestCLI(t)
+	dir := t.TempDir()
+
+	journal := filepath.Join(dir, "household.beancount")
+	require.NoError(t, os.WriteFile(journal, []byte(`2024-06-03 * "Tesco" "Weekly shop"
+  Expenses:Living:Groceries  42.10 USD
+  Assets:Bank
+
+2024-06-28 * "Employer"
+  Assets:Bank   2500 USD
+  Income:Salary
+`), 0644))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "beancount", "-file", journal, "-dry-run"}))
+	assert.Contains(t, out.String(), "Living / Groceries")
+	assert.Contains(t, out.String(), "2 new, 0 duplicate, 0 skipped; 1 groups and 1 categories to create")
+	assert.Contains(t, out.String(), "Dry run")
+	groups, err := c.groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"import", "beancount", "-file", journal}))
+	assert.Contains(t, out.String(), "Imported 2 postings")
+	incomes, err := c.incomeSvc.GetIncomesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	assert.Equal(t, "2500", incomes[0].Amount.String())
+
+	err = c.Run([]string{"import", "ledger"})
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_Export(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-06", "-description", "Salary", "-amount", "2000"}))
+	require.NoError(t, c.Run([]string{"income", "add", "-month", "2024-07", "-description", "Salary", "-amount", "2100"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"export", "-month", "2024-06"}))
+	assert.Contains(t, out.String(), "# gocost report: June 2024")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"export", "-format", "csv", "-from", "2024-06", "-to", "2024-07"}))
+	assert.Contains(t, out.String(), "2024-06,income,,Salary,,2000.00")
+	assert.Contains(t, out.String(), "2024-07,income,,Salary,,2100.00")
+
+	output := filepath.Join(t.TempDir(), "report.json")
+	out.Reset()
+	require.NoError(t, c.Run([]string{"export", "-format", "json", "-from", "2024-06", "-to", "2024-07", "-output", output}))
+	assert.Contains(t, out.String(), "Exported 2024-06 - 2024-07 to "+output)
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"income": "4100"`)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"export", "-format", "ledger", "-month", "2024-07"}))
+	assert.Contains(t, out.String(), "2024-07-01 * Salary\n    Income:Salary  -2100.00")
+
+	err = c.Run([]string{"export", "-format", "xml"})
+	assert.ErrorIs(t, err, ErrUsage)
+	err = c.Run([]string{"export", "-from", "June"})
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_Restore(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"restore"}))
+	assert.Contains(t, out.String(), "No backups found")
+
+	err := c.Run([]string{"restore", "-latest"})
+	assert.Error(t, err)
+
+	// The first save creates the file, the backup is taken before the second session writes
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+	repo, err := data.NewJsonRepository(c.dataFilePath, "USD")
+	require.NoError(t, err)
+	repo.SetBackups(c.backups)
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Savings", Order: 2}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"restore"}))
+	assert.Contains(t, out.String(), "test_data-")
+
+	// Backups are restored over a data file that cannot be read, without the services
+	require.NoError(t, os.WriteFile(c.dataFilePath, []byte(`{"version": 3, "Categ`), 0600))
+	recovery := New(nil, nil, nil, nil, nil, nil, nil, c.dataFilePath, c.backups, out)
+	require.NoError(t, recovery.Run([]string{"restore", "-latest"}))
+	restored, err := data.NewJsonRepository(c.dataFilePath, "USD")
+	require.NoError(t, err)
+	groups, err := restored.GetAllGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "Housing", groups[0].GroupName)
+}
+
+func TestCLI_EncryptDecrypt(t *testing.T) {
+	c, out := setupTestCLI(t)
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+
+	// The passphrase unlocking the file is not used as the new one
+	t.Setenv(config.PassphraseEnv, "old")
+	t.Setenv(config.NewPassphraseEnv, "secret")
+	out.Reset()
+	require.NoError(t, c.Run([]string{"encrypt"}))
+	assert.Contains(t, out.String(), "Encrypted "+c.dataFilePath)
+	assert.Contains(t, out.String(), "are not encrypted")
+
+	content, err := os.ReadFile(c.dataFilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "Housing")
+
+	_, err = data.NewJsonRepository(c.dataFilePath, "USD")
+	assert.ErrorIs(t, err, data.ErrPassphraseRequired)
+	_, err = data.NewEncryptedJsonRepository(c.dataFilePath, "USD", "wrong")
+	assert.ErrorIs(t, err, data.ErrWrongPassphrase)
+
+	// Commands keep working on the encrypted file
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Living"}))
+	encrypted, err := data.NewEncryptedJsonRepository(c.dataFilePath, "USD", "secret")
+	require.NoError(t, err)
+	groups, err := encrypted.GetAllGroups()
+	require.NoError(t, err)
+	assert.Len(t, groups, 2)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"decrypt"}))
+	assert.Contains(t, out.String(), "Decrypted "+c.dataFilePath)
+	content, err = os.ReadFile(c.dataFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Housing")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"decrypt"}))
+	assert.Contains(t, out.String(), "is not encrypted")
+
+	// Without a terminal the new passphrase must be set explicitly
+	t.Setenv(config.NewPassphraseEnv, "")
+	err = c.Run([]string{"encrypt"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), config.NewPassphraseEnv)
+}
+
+func TestCLI_Log(t *testing.T) {
+	c, out := setupTestCLI(t)
+
+	require.NoError(t, c.Run([]string{"log"}))
+	assert.Contains(t, out.String(), "No changes recorded.")
+
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-06", "-group", "Housing", "-name", "Rent"}))
+	require.NoError(t, c.Run([]string{"expense", "set", "-month", "2024-06", "-category", "Rent", "-amount", "850"}))
+	require.NoError(t, c.Run([]string{"expense", "set", "-month", "2024-06", "-category", "Rent", "-amount", "900"}))
+	require.NoError(t, c.Run([]string{"category", "add", "-month", "2024-07", "-group", "Housing", "-name", "Water"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"log"}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 6)
+	assert.Contains(t, lines[0], "CHANGES")
+	assert.Contains(t, lines[1], "Water")
+	assert.Contains(t, lines[2], "amount: 850 → 900")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"log", "-month", "2024-06", "-category", "rent", "-limit", "2"}))
+	lines = strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.NotContains(t, out.String(), "Water")
+	assert.Contains(t, lines[2], "expense")
+
+	err := c.Run([]string{"log", "-month", "June"})
+	assert.ErrorIs(t, err, ErrUsage)
+}
+
+func TestCLI_Doctor(t *testing.T) {
+	c, out := setupTestCLI(t)
+	require.NoError(t, c.Run([]string{"group", "add", "-name", "Housing"}))
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"doctor"}))
+	assert.Contains(t, out.String(), "No issues found")
+
+	content := `{"version": 3, "CategoryGroups": {}, "monthlyData": {"2024-01": {"incomes": null, "categories": [{"catId": "c1", "groupId": "g1", "categoryName": "Rent"}]}}}`
+	require.NoError(t, os.WriteFile(c.dataFilePath, []byte(content), 0600))
+
+	out.Reset()
+	err := c.Run([]string{"doctor"})
+	assert.ErrorContains(t, err, "found 2 issue(s)")
+	assert.Contains(t, out.String(), "incomes are null")
+	assert.Contains(t, out.String(), `category "Rent" references group "g1"`)
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"doctor", "-repair"}))
+	assert.Contains(t, out.String(), "Repaired 2 issue(s)")
+
+	out.Reset()
+	require.NoError(t, c.Run([]string{"doctor"}))
+	assert.Contains(t, out.String(), "No issues found")
+
+	// A data file that cannot be read points to its backups
+	require.NoError(t, os.WriteFile(c.dataFilePath, []byte(`{"version": 3, "Categ`), 0600))
+	c.SetDoctor(func() (Doctor, error) { return data.NewJsonRepository(c.dataFilePath, "USD") })
+	err = c.Run([]string{"doctor"})
+	assert.ErrorContains(t, err, "run 'gocost restore'")
+}
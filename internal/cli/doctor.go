@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/data"
+)
+
+// Doctor is implemented by storage backends able to check and repair the
+// consistency of their data.
+type Doctor interface {
+	Check() ([]data.Issue, error)
+	Repair() ([]data.Issue, data.Backup, error)
+}
+
+// SetDoctor enables the doctor command. The data file is only opened with
+// open when the command runs, so that a data file that cannot be read is
+// reported instead of stopping gocost before the command starts.
+func (c *CLI) SetDoctor(open func() (Doctor, error)) {
+	c.openDoctor = open
+}
+
+// runDoctor reports the inconsistencies of the data file, and repairs them
+// after taking a backup when -repair is set.
+func (c *CLI) runDoctor(args []string) error {
+	fs := c.newFlagSet("doctor")
+	repair := fs.Bool("repair", false, "Repair the issues after backing up the data file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.openDoctor == nil {
+		return errors.New("doctor is only available for the json storage")
+	}
+	doctor, err := c.openDoctor()
+	if err != nil {
+		return fmt.Errorf("%s cannot be read: %w, run 'gocost restore' to list its backups", c.dataFilePath, err)
+	}
+
+	issues, err := doctor.Check()
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		_, err := fmt.Fprintf(c.out, "No issues found in %s.\n", c.dataFilePath)
+		return err
+	}
+
+	var backup data.Backup
+	if *repair {
+		if issues, backup, err = doctor.Repair(); err != nil {
+			return err
+		}
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "MONTH\tISSUE\tREPAIR")
+	unrepairable := 0
+	for _, issue := range issues {
+		month := issue.MonthKey
+		if month == "" {
+			month = "-"
+		}
+		repairText := issue.Repair
+		if repairText == "" {
+			repairText = "fix by hand"
+			unrepairable++
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", month, issue.Description, repairText)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !*repair {
+		return fmt.Errorf("found %d issue(s), run 'gocost doctor -repair' to repair them", len(issues))
+	}
+	_, err = fmt.Fprintf(c.out, "\nRepaired %d issue(s) of %s, backup %s keeps the previous data.\n",
+		len(issues)-unrepairable, c.dataFilePath, backup.Name)
+	return err
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/madalinpopa/gocost/internal/config"
+)
+
+// Encrypter is implemented by storage backends whose data file can be encrypted.
+type Encrypter interface {
+	Encrypted() bool
+	SetPassphrase(passphrase string) error
+}
+
+// errEncryptionUnsupported is returned when the storage backend cannot be encrypted.
+var errEncryptionUnsupported = errors.New("encryption is only available for the json storage")
+
+// SetEncrypter enables the encrypt and decrypt commands.
+func (c *CLI) SetEncrypter(encrypter Encrypter) {
+	c.encrypter = encrypter
+}
+
+// encrypt encrypts the data file with a new passphrase, or changes the
+// passphrase of an encrypted file. The new passphrase is never taken from
+// config.PassphraseEnv, which unlocks the file.
+func (c *CLI) encrypt(args []string) error {
+	fs := c.newFlagSet("encrypt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.encrypter == nil {
+		return errEncryptionUnsupported
+	}
+
+	passphrase, err := config.ReadNewPassphrase("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("%w: passphrase must not be empty", ErrUsage)
+	}
+	confirmation, err := config.ReadNewPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return err
+	}
+	if confirmation != passphrase {
+		return errors.New("passphrases do not match")
+	}
+
+	wasEncrypted := c.encrypter.Encrypted()
+	if err := c.encrypter.SetPassphrase(passphrase); err != nil {
+		return err
+	}
+
+	if wasEncrypted {
+		_, err = fmt.Fprintf(c.out, "Changed the passphrase of %s\n", c.dataFilePath)
+		return err
+	}
+	if _, err := fmt.Fprintf(c.out, "Encrypted %s\n", c.dataFilePath); err != nil {
+		return err
+	}
+	if c.backups != nil {
+		_, err = fmt.Fprintf(c.out, "Backups in %s made before encrypting are not encrypted, delete them if needed.\n", c.backups.Dir())
+	}
+	return err
+}
+
+// decrypt stores an encrypted data file in plain text again.
+func (c *CLI) decrypt(args []string) error {
+	fs := c.newFlagSet("decrypt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.encrypter == nil {
+		return errEncryptionUnsupported
+	}
+
+	if !c.encrypter.Encrypted() {
+		_, err := fmt.Fprintf(c.out, "%s is not encrypted\n", c.dataFilePath)
+		return err
+	}
+	if err := c.encrypter.SetPassphrase(""); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(c.out, "Decrypted %s\n", c.dataFilePath)
+	return err
+}
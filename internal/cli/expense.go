@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// expenseList prints the expense of every category in a month.
+func (c *CLI) expenseList(args []string) error {
+	fs := c.newFlagSet("expense list")
+	month := monthFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	categories, err := c.categorySvc.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return err
+	}
+	if len(categories) == 0 {
+		_, err := fmt.Fprintf(c.out, "No categories for %s.\n", monthKey)
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CATEGORY\tAMOUNT\tBUDGET\tSTATUS\tNOTES")
+	for _, category := range categories {
+		expense, ok := category.Expense[category.CatID]
+		status := "Not Set"
+		if ok {
+			status = expense.Status
+		}
+		currency := recordCurrency(expense.Currency)
+		_, _ = fmt.Fprintf(w, "%s\t%s %s\t%s %s\t%s\t%s\n",
+			category.CategoryName, expense.Amount.StringFixed(2), currency, expense.Budget.StringFixed(2), currency, status, expense.Notes)
+	}
+	return w.Flush()
+}
+
+// expenseSet creates or updates the expense of a category in a month.
+// Fields whose flags are not provided keep their current value.
+func (c *CLI) expenseSet(args []string) error {
+	fs := c.newFlagSet("expense set")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	amount := fs.String("amount", "", "Spent amount")
+	budget := fs.String("budget", "", "Budgeted amount")
+	status := fs.String("status", "", "Payment status: paid or unpaid")
+	notes := fs.String("notes", "", "Notes for the expense")
+	currency := fs.String("currency", "", "Currency of the expense, defaults to the default currency")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if category.Expense == nil {
+		category.Expense = make(map[string]domain.ExpenseRecord)
+	}
+	expense := category.Expense[category.CatID]
+	if expense.Status == "" {
+		expense.Status = "Not Paid"
+	}
+
+	if set["amount"] {
+		if expense.HasEntries() {
+			return fmt.Errorf("%w: the amount of '%s' is the sum of its entries", ErrUsage, category.CategoryName)
+		}
+		value, err := ui.ValidAmount(*amount)
+		if err != nil {
+			return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+		}
+		expense.Amount = value
+	}
+	if set["budget"] {
+		value, err := ui.ValidAmount(*budget)
+		if err != nil {
+			return fmt.Errorf("%w: invalid budget: %v", ErrUsage, err)
+		}
+		expense.Budget = value
+	}
+	if set["status"] {
+		value, err := parseStatus(*status)
+		if err != nil {
+			return err
+		}
+		expense.Status = value
+	}
+	if set["notes"] {
+		expense.Notes = *notes
+	}
+	if set["currency"] {
+		expense.Currency = parseCurrency(*currency)
+	}
+
+	category.Expense[category.CatID] = expense
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to save expense: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Expense for '%s' saved\n", category.CategoryName)
+	return err
+}
+
+// expenseToggle flips the payment status of a category expense.
+func (c *CLI) expenseToggle(args []string) error {
+	fs := c.newFlagSet("expense toggle")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if category.Expense == nil {
+		category.Expense = make(map[string]domain.ExpenseRecord)
+	}
+
+	expense := category.Expense[category.CatID]
+	if expense.Status == "Paid" {
+		expense.Status = "Not Paid"
+	} else {
+		expense.Status = "Paid"
+	}
+	category.Expense[category.CatID] = expense
+
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to toggle status: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Status for '%s' toggled to '%s'\n", category.CategoryName, expense.Status)
+	return err
+}
+
+// expenseClear resets the expense of a category in a month.
+func (c *CLI) expenseClear(args []string) error {
+	fs := c.newFlagSet("expense clear")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if category.Expense == nil {
+		category.Expense = make(map[string]domain.ExpenseRecord)
+	}
+	category.Expense[category.CatID] = domain.ExpenseRecord{
+		Amount: decimal.Zero,
+		Budget: decimal.Zero,
+		Status: "Not Paid",
+		Notes:  "",
+	}
+
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to clear expense: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Expense for '%s' cleared\n", category.CategoryName)
+	return err
+}
+
+// expenseEntries prints the individual entries of a category expense.
+func (c *CLI) expenseEntries(args []string) error {
+	fs := c.newFlagSet("expense entries")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	expense := category.Expense[category.CatID]
+	if !expense.HasEntries() {
+		_, err := fmt.Fprintf(c.out, "No entries for '%s' in %s.\n", category.CategoryName, monthKey)
+		return err
+	}
+
+	currency := viper.GetString(config.CurrencyField)
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tDATE\tDESCRIPTION\tAMOUNT")
+	for _, entry := range expense.Entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s %s\n",
+			entry.EntryID, entry.Date.Format(entryDateLayout), entry.Description, entry.Amount.StringFixed(2), currency)
+	}
+	_, _ = fmt.Fprintf(w, "\t\tTotal\t%s %s\n", expense.EntriesTotal().StringFixed(2), currency)
+	return w.Flush()
+}
+
+// expenseAddEntry adds a dated entry to a category expense.
+func (c *CLI) expenseAddEntry(args []string) error {
+	fs := c.newFlagSet("expense add-entry")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	date := fs.String("date", "", "Date of the entry in YYYY-MM-DD format (default: today or the first day of the month)")
+	description := fs.String("description", "", "Description of the entry")
+	amount := fs.String("amount", "", "Amount of the entry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	if err := requireFlag("amount", *amount); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	value, err := ui.ValidAmount(*amount)
+	if err != nil {
+		return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+	}
+	entryDate, err := parseEntryDate(*date, *month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if category.Expense == nil {
+		category.Expense = make(map[string]domain.ExpenseRecord)
+	}
+	expense := category.Expense[category.CatID]
+	if expense.Status == "" {
+		expense.Status = "Not Paid"
+	}
+	expense.Entries = append(expense.Entries, domain.ExpenseEntry{
+		EntryID:     ui.GenerateID(),
+		Date:        entryDate,
+		Description: strings.TrimSpace(*description),
+		Amount:      value,
+	})
+	expense.SyncAmount()
+	category.Expense[category.CatID] = expense
+
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to save entry: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Entry added to '%s', total %s\n", category.CategoryName, expense.Amount.StringFixed(2))
+	return err
+}
+
+// expenseDeleteEntry removes an entry from a category expense.
+func (c *CLI) expenseDeleteEntry(args []string) error {
+	fs := c.newFlagSet("expense delete-entry")
+	month := monthFlag(fs)
+	ref := fs.String("category", "", "Name or ID of the category")
+	entryID := fs.String("entry", "", "ID of the entry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("category", *ref); err != nil {
+		return err
+	}
+	if err := requireFlag("entry", *entryID); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	category, err := c.findCategory(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	expense := category.Expense[category.CatID]
+
+	index := -1
+	for i, entry := range expense.Entries {
+		if entry.EntryID == *entryID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("entry %q not found for '%s'", *entryID, category.CategoryName)
+	}
+
+	expense.Entries = append(expense.Entries[:index], expense.Entries[index+1:]...)
+	if expense.HasEntries() {
+		expense.SyncAmount()
+	} else {
+		expense.Amount = decimal.Zero
+	}
+	category.Expense[category.CatID] = expense
+
+	if err := c.categorySvc.UpdateCategory(monthKey, category); err != nil {
+		return fmt.Errorf("failed to delete entry: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Entry deleted from '%s', total %s\n", category.CategoryName, expense.Amount.StringFixed(2))
+	return err
+}
+
+// parseEntryDate parses an entry date and checks that it falls in month.
+// An empty value defaults to today when in month, otherwise the first day of it.
+func parseEntryDate(value string, month string) (time.Time, error) {
+	start, err := time.ParseInLocation(monthLayout, strings.TrimSpace(month), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: month must be in YYYY-MM format, got %q", ErrUsage, month)
+	}
+
+	if strings.TrimSpace(value) == "" {
+		now := time.Now()
+		if now.Year() == start.Year() && now.Month() == start.Month() {
+			return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local), nil
+		}
+		return start, nil
+	}
+
+	date, err := time.ParseInLocation(entryDateLayout, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be in YYYY-MM-DD format, got %q", ErrUsage, value)
+	}
+	if date.Year() != start.Year() || date.Month() != start.Month() {
+		return time.Time{}, fmt.Errorf("%w: date %s is not in %s", ErrUsage, value, month)
+	}
+	return date, nil
+}
+
+// parseStatus maps a user supplied status to the stored expense status.
+func parseStatus(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "paid":
+		return "Paid", nil
+	case "unpaid", "not paid", "not-paid":
+		return "Not Paid", nil
+	default:
+		return "", fmt.Errorf("%w: status must be 'paid' or 'unpaid', got %q", ErrUsage, value)
+	}
+}
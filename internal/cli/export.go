@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/export"
+	"github.com/spf13/viper"
+)
+
+// exportReport writes the report of a month, or of the range selected with
+// -from and -to, to stdout or to the file given with -output.
+func (c *CLI) exportReport(args []string) error {
+	fs := c.newFlagSet("export")
+	format := fs.String("format", export.FormatMarkdown, "Output format: "+strings.Join(export.Formats(), ", "))
+	month := monthFlag(fs)
+	from := fs.String("from", "", "First month of the range in YYYY-MM format")
+	to := fs.String("to", "", "Last month of the range in YYYY-MM format, defaults to -from")
+	output := fs.String("output", "", "File to write the report to, defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	*format = strings.ToLower(strings.TrimSpace(*format))
+	if !slices.Contains(export.Formats(), *format) {
+		return fmt.Errorf("%w: unknown format %q, available: %s", ErrUsage, *format, strings.Join(export.Formats(), ", "))
+	}
+
+	start, end := *month, *month
+	if *from != "" {
+		start, end = *from, *from
+	}
+	if *to != "" {
+		end = *to
+	}
+	fromMonth, err := parseMonth(start)
+	if err != nil {
+		return err
+	}
+	toMonth, err := parseMonth(end)
+	if err != nil {
+		return err
+	}
+
+	exporter := export.New(c.groupSvc, c.monthSvc)
+	report, err := exporter.Build(fromMonth, toMonth, viper.GetString(config.CurrencyField), config.DisplayCurrency())
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = c.out
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := export.Write(w, report, *format); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return nil
+	}
+	months := report.From
+	if report.From != report.To {
+		months = report.From + " - " + report.To
+	}
+	_, err = fmt.Fprintf(c.out, "Exported %s to %s\n", months, *output)
+	return err
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+)
+
+// groupList prints all category groups ordered by their position.
+func (c *CLI) groupList(args []string) error {
+	fs := c.newFlagSet("group list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	groups, err := c.groupSvc.GetAllGroups()
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		_, err := fmt.Fprintln(c.out, "No category groups.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ORDER\tNAME\tID")
+	for _, group := range groups {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", group.Order, group.GroupName, group.GroupID)
+	}
+	return w.Flush()
+}
+
+// groupAdd creates a new category group placed after the existing ones.
+func (c *CLI) groupAdd(args []string) error {
+	fs := c.newFlagSet("group add")
+	name := fs.String("name", "", "Name of the group")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("name", *name); err != nil {
+		return err
+	}
+
+	groups, err := c.groupSvc.GetAllGroups()
+	if err != nil {
+		return err
+	}
+	maxOrder := 0
+	for _, group := range groups {
+		if group.Order > maxOrder {
+			maxOrder = group.Order
+		}
+	}
+
+	group := domain.CategoryGroup{
+		GroupID:   ui.GenerateID(),
+		GroupName: strings.TrimSpace(*name),
+		Order:     maxOrder + 1,
+	}
+	if err := c.groupSvc.AddGroup(group); err != nil {
+		return fmt.Errorf("failed to add group: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Group '%s' added (ID: %s)\n", group.GroupName, group.GroupID)
+	return err
+}
+
+// groupUpdate renames or reorders an existing category group.
+func (c *CLI) groupUpdate(args []string) error {
+	fs := c.newFlagSet("group update")
+	ref := fs.String("group", "", "Name or ID of the group")
+	name := fs.String("name", "", "New name of the group")
+	order := fs.Int("order", 0, "New position of the group")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("group", *ref); err != nil {
+		return err
+	}
+
+	group, err := c.findGroup(*ref)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*name) != "" {
+		group.GroupName = strings.TrimSpace(*name)
+	}
+	if *order > 0 {
+		group.Order = *order
+	}
+
+	if err := c.groupSvc.UpdateGroup(group); err != nil {
+		return fmt.Errorf("failed to update group: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Group '%s' updated\n", group.GroupName)
+	return err
+}
+
+// groupDelete removes a category group that is no longer used.
+func (c *CLI) groupDelete(args []string) error {
+	fs := c.newFlagSet("group delete")
+	ref := fs.String("group", "", "Name or ID of the group")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("group", *ref); err != nil {
+		return err
+	}
+
+	group, err := c.findGroup(*ref)
+	if err != nil {
+		return err
+	}
+	if err := c.groupSvc.DeleteGroup(group.GroupID); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Group '%s' deleted\n", group.GroupName)
+	return err
+}
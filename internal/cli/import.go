@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/importer"
+	"github.com/spf13/viper"
+)
+
+// importCSV imports the transactions of a bank statement in CSV format as
+// expense entries, assigning them to categories with the import rules.
+func (c *CLI) importCSV(args []string) error {
+	fs := c.newFlagSet("import csv")
+	file := fs.String("file", "", "Path of the CSV statement")
+	layoutName := fs.String("layout", importer.DefaultLayout, "Column layout of the statement")
+	rulesFile := fs.String("rules", "", "JSON file with the import rules, defaults to importRules in the config")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without changing any data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("file", *file); err != nil {
+		return err
+	}
+
+	layout, err := importLayout(*layoutName)
+	if err != nil {
+		return err
+	}
+	rules, err := importRules(*rulesFile)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	transactions, err := importer.ReadCSV(f, layout)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+	return c.runImport(transactions, rules, *dryRun)
+}
+
+// importOFX imports the transactions of a bank statement in OFX or QFX
+// format as expense entries, assigning them to categories with the import rules.
+func (c *CLI) importOFX(args []string) error {
+	return c.importStatement(args, "ofx", importer.ReadOFX)
+}
+
+// importQIF imports the transactions of a bank statement in QIF format as
+// expense entries, assigning them to categories with the import rules or the
+// categories of the statement.
+func (c *CLI) importQIF(args []string) error {
+	return c.importStatement(args, "qif", importer.ReadQIF)
+}
+
+// importStatement imports the statement read with read, named format in usage messages.
+func (c *CLI) importStatement(args []string, format string, read func(io.Reader) ([]importer.Transaction, error)) error {
+	fs := c.newFlagSet("import " + format)
+	file := fs.String("file", "", "Path of the statement")
+	rulesFile := fs.String("rules", "", "JSON file with the import rules, defaults to importRules in the config")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without changing any data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("file", *file); err != nil {
+		return err
+	}
+
+	rules, err := importRules(*rulesFile)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	transactions, err := read(f)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+	return c.runImport(transactions, rules, *dryRun)
+}
+
+// runImport previews the import of transactions and applies it unless dryRun is set.
+func (c *CLI) runImport(transactions []importer.Transaction, rules importer.RuleSet, dryRun bool) error {
+	im := importer.New(c.categorySvc, c.groupSvc, c.incomeSvc)
+	plan, err := im.Plan(transactions, rules, viper.GetString(config.CurrencyField))
+	if err != nil {
+		return err
+	}
+	if len(plan.Items) == 0 {
+		_, err := fmt.Fprintln(c.out, "No transactions found.")
+		return err
+	}
+
+	currency := viper.GetString(config.CurrencyField)
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tPAYEE\tAMOUNT\tMONTH\tCATEGORY\tSTATUS")
+	for _, item := range plan.Items {
+		category := item.Category
+		if category == "" {
+			category = "-"
+		}
+		status := string(item.Status)
+		if item.Reason != "" {
+			status += ": " + item.Reason
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%s\t%s\n",
+			item.Date.Format(entryDateLayout), item.Payee, item.Amount.StringFixed(2), currency, item.MonthKey, category, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(c.out, "\n%d new, %d duplicate, %d unmatched, %d missing category, %d income, %d skipped\n",
+		plan.Count(importer.StatusNew), plan.Count(importer.StatusDuplicate), plan.Count(importer.StatusUnmatched),
+		plan.Count(importer.StatusMissingCategory), plan.Count(importer.StatusIncome), plan.Count(importer.StatusSkipped))
+
+	if dryRun {
+		_, err := fmt.Fprintln(c.out, "Dry run: nothing was imported.")
+		return err
+	}
+
+	count, err := im.Apply(plan)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "Imported %d transactions\n", count)
+	return err
+}
+
+// importLedger imports the expenses and incomes of a ledger or hledger journal.
+func (c *CLI) importLedger(args []string) error {
+	return c.importJournal(args, importer.FormatLedger)
+}
+
+// importBeancount imports the expenses and incomes of a beancount journal.
+func (c *CLI) importBeancount(args []string) error {
+	return c.importJournal(args, importer.FormatBeancount)
+}
+
+// importJournal previews the import of the journal in format and applies it
+// unless the dry run flag is set.
+func (c *CLI) importJournal(args []string, format string) error {
+	fs := c.newFlagSet("import " + format)
+	file := fs.String("file", "", "Path of the journal")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without changing any data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("file", *file); err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	transactions, err := importer.ReadJournal(f, format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+
+	currency := viper.GetString(config.CurrencyField)
+	im := importer.New(c.categorySvc, c.groupSvc, c.incomeSvc)
+	plan, err := im.PlanJournal(transactions, currency)
+	if err != nil {
+		return err
+	}
+	if len(plan.Items) == 0 {
+		_, err := fmt.Fprintln(c.out, "No expense or income postings found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DATE\tPAYEE\tACCOUNT\tAMOUNT\tMONTH\tRECORD\tSTATUS")
+	for _, item := range plan.Items {
+		record := "income"
+		if !item.Income {
+			record = item.Group + " / " + item.Category
+		}
+		status := string(item.Status)
+		if item.Reason != "" {
+			status += ": " + item.Reason
+		}
+		itemCurrency := item.Currency
+		if itemCurrency == "" {
+			itemCurrency = currency
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s %s\t%s\t%s\t%s\n",
+			item.Date.Format(entryDateLayout), item.Payee, item.Account, item.Amount.StringFixed(2), itemCurrency,
+			item.MonthKey, record, status)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	groups, categories := plan.Created()
+	_, _ = fmt.Fprintf(c.out, "\n%d new, %d duplicate, %d skipped; %d groups and %d categories to create\n",
+		plan.Count(importer.StatusNew), plan.Count(importer.StatusDuplicate), plan.Count(importer.StatusSkipped),
+		groups, categories)
+
+	if *dryRun {
+		_, err := fmt.Fprintln(c.out, "Dry run: nothing was imported.")
+		return err
+	}
+
+	count, err := im.ApplyJournal(plan)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "Imported %d postings\n", count)
+	return err
+}
+
+// importLayout returns the layout named name, looking at the layouts of the
+// config file before the built-in ones.
+func importLayout(name string) (importer.Layout, error) {
+	layouts := importer.BuiltinLayouts()
+	var configured map[string]importer.Layout
+	if err := viper.UnmarshalKey(config.ImportLayoutsField, &configured); err != nil {
+		return importer.Layout{}, fmt.Errorf("invalid %s in config: %w", config.ImportLayoutsField, err)
+	}
+	for key, layout := range configured {
+		layouts[strings.ToLower(key)] = layout
+	}
+
+	layout, ok := layouts[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return importer.Layout{}, fmt.Errorf("%w: unknown layout %q, available: %s", ErrUsage, name, strings.Join(sortedKeys(layouts), ", "))
+	}
+	return layout, nil
+}
+
+// importRules loads the import rules from path, or from the config file when path is empty.
+func importRules(path string) (importer.RuleSet, error) {
+	var rules []importer.Rule
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return importer.RuleSet{}, err
+		}
+		if err := json.Unmarshal(content, &rules); err != nil {
+			return importer.RuleSet{}, fmt.Errorf("invalid rules file %s: %w", path, err)
+		}
+	} else if err := viper.UnmarshalKey(config.ImportRulesField, &rules); err != nil {
+		return importer.RuleSet{}, fmt.Errorf("invalid %s in config: %w", config.ImportRulesField, err)
+	}
+	return importer.CompileRules(rules)
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// incomeList prints the incomes of a month and their total.
+func (c *CLI) incomeList(args []string) error {
+	fs := c.newFlagSet("income list")
+	month := monthFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	incomes, err := c.incomeSvc.GetIncomesForMonth(monthKey)
+	if err != nil {
+		return err
+	}
+	if len(incomes) == 0 {
+		_, err := fmt.Fprintf(c.out, "No income entries for %s.\n", monthKey)
+		return err
+	}
+
+	converter, err := c.rateSvc.ConverterForMonth(monthKey, viper.GetString(config.CurrencyField))
+	if err != nil {
+		return err
+	}
+
+	displayCurrency := config.DisplayCurrency()
+	var total decimal.Decimal
+	var missing []string
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DESCRIPTION\tAMOUNT\tID")
+	for _, income := range incomes {
+		if value, ok := converter.Convert(income.Amount, income.Currency, displayCurrency); ok {
+			total = total.Add(value)
+		} else {
+			missing = append(missing, converter.Currency(income.Currency))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s %s\t%s\n", income.Description, income.Amount.StringFixed(2), recordCurrency(income.Currency), income.IncomeID)
+	}
+	_, _ = fmt.Fprintf(w, "Total\t%s %s\t\n", total.StringFixed(2), displayCurrency)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		_, err = fmt.Fprintf(c.out, "Missing exchange rates for %s, left out of the total.\n", strings.Join(missing, ", "))
+	}
+	return err
+}
+
+// incomeAdd records a new income for a month.
+func (c *CLI) incomeAdd(args []string) error {
+	fs := c.newFlagSet("income add")
+	month := monthFlag(fs)
+	description := fs.String("description", "", "Description of the income")
+	amount := fs.String("amount", "", "Amount of the income")
+	currency := fs.String("currency", "", "Currency of the income, defaults to the default currency")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("description", *description); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	value, err := ui.ValidAmount(*amount)
+	if err != nil {
+		return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+	}
+
+	income := domain.IncomeRecord{
+		IncomeID:    ui.GenerateID(),
+		Description: strings.TrimSpace(*description),
+		Amount:      value,
+		Currency:    parseCurrency(*currency),
+	}
+	if err := c.incomeSvc.AddIncome(monthKey, income); err != nil {
+		return fmt.Errorf("failed to add income: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Income '%s' added for %s (ID: %s)\n", income.Description, monthKey, income.IncomeID)
+	return err
+}
+
+// incomeUpdate changes the description or amount of an existing income.
+func (c *CLI) incomeUpdate(args []string) error {
+	fs := c.newFlagSet("income update")
+	month := monthFlag(fs)
+	ref := fs.String("income", "", "Description or ID of the income")
+	description := fs.String("description", "", "New description of the income")
+	amount := fs.String("amount", "", "New amount of the income")
+	currency := fs.String("currency", "", "New currency of the income")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("income", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	income, err := c.findIncome(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if strings.TrimSpace(*description) != "" {
+		income.Description = strings.TrimSpace(*description)
+	}
+	if set["amount"] {
+		value, err := ui.ValidAmount(*amount)
+		if err != nil {
+			return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+		}
+		income.Amount = value
+	}
+	if set["currency"] {
+		income.Currency = parseCurrency(*currency)
+	}
+
+	if err := c.incomeSvc.UpdateIncome(monthKey, income); err != nil {
+		return fmt.Errorf("failed to update income: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Income '%s' updated\n", income.Description)
+	return err
+}
+
+// incomeDelete removes an income from a month.
+func (c *CLI) incomeDelete(args []string) error {
+	fs := c.newFlagSet("income delete")
+	month := monthFlag(fs)
+	ref := fs.String("income", "", "Description or ID of the income")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("income", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	income, err := c.findIncome(monthKey, *ref)
+	if err != nil {
+		return err
+	}
+	if err := c.incomeSvc.DeleteIncome(monthKey, income.IncomeID); err != nil {
+		return fmt.Errorf("failed to delete income: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Income '%s' deleted\n", income.Description)
+	return err
+}
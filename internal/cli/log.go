@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// defaultLogLimit is the number of changes printed by the log command by default.
+const defaultLogLimit = 50
+
+// changeLog prints the journal of changes, newest first, optionally limited
+// to a month or a category.
+func (c *CLI) changeLog(args []string) error {
+	fs := c.newFlagSet("log")
+	month := fs.String("month", "", "Only show changes of a month in YYYY-MM format")
+	category := fs.String("category", "", "Only show changes of a category, by name or ID")
+	limit := fs.Int("limit", defaultLogLimit, "Maximum number of changes to show, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := domain.ChangeFilter{Category: strings.TrimSpace(*category), Limit: max(*limit, 0)}
+	if strings.TrimSpace(*month) != "" {
+		monthKey, err := parseMonthKey(*month)
+		if err != nil {
+			return err
+		}
+		filter.MonthKey = monthKey
+	}
+
+	changes, err := c.journalSvc.GetChanges(filter)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(c.out, "No changes recorded.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tUSER\tACTION\tRECORD\tMONTH\tNAME\tCHANGES")
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			change.Time.Local().Format("2006-01-02 15:04:05"),
+			change.User,
+			change.Action,
+			change.Record,
+			change.MonthKey,
+			change.Name,
+			change.Summary(),
+		)
+	}
+	return w.Flush()
+}
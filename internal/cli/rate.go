@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// rateList prints the exchange rates of a month.
+func (c *CLI) rateList(args []string) error {
+	fs := c.newFlagSet("rate list")
+	month := monthFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	rates, err := c.rateSvc.GetRatesForMonth(monthKey)
+	if err != nil {
+		return err
+	}
+	if len(rates) == 0 {
+		_, err := fmt.Fprintf(c.out, "No exchange rates for %s.\n", monthKey)
+		return err
+	}
+
+	base := viper.GetString(config.CurrencyField)
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CURRENCY\tRATE")
+	for _, rate := range rates {
+		_, _ = fmt.Fprintf(w, "%s\t1 %s = %s %s\n", rate.Currency, rate.Currency, rate.Rate.String(), base)
+	}
+	return w.Flush()
+}
+
+// rateSet adds or replaces the exchange rate of a currency for a month.
+func (c *CLI) rateSet(args []string) error {
+	fs := c.newFlagSet("rate set")
+	month := monthFlag(fs)
+	currency := fs.String("currency", "", "Currency code, e.g. EUR")
+	rate := fs.String("rate", "", "Value of one unit of the currency in the default currency")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("currency", *currency); err != nil {
+		return err
+	}
+	if err := requireFlag("rate", *rate); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	code := domain.NormalizeCurrency(*currency)
+	if code == domain.NormalizeCurrency(viper.GetString(config.CurrencyField)) {
+		return fmt.Errorf("%w: %s is the default currency", ErrUsage, code)
+	}
+	value, err := decimal.NewFromString(*rate)
+	if err != nil || !value.IsPositive() {
+		return fmt.Errorf("%w: rate must be a number greater than zero, got %q", ErrUsage, *rate)
+	}
+
+	if err := c.rateSvc.SetRate(monthKey, domain.ExchangeRate{Currency: code, Rate: value}); err != nil {
+		return fmt.Errorf("failed to set exchange rate: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Exchange rate for %s set to %s in %s\n", code, value.String(), monthKey)
+	return err
+}
+
+// rateDelete removes the exchange rate of a currency from a month.
+func (c *CLI) rateDelete(args []string) error {
+	fs := c.newFlagSet("rate delete")
+	month := monthFlag(fs)
+	currency := fs.String("currency", "", "Currency code, e.g. EUR")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("currency", *currency); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	code := domain.NormalizeCurrency(*currency)
+	if err := c.rateSvc.DeleteRate(monthKey, code); err != nil {
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Exchange rate for %s deleted from %s\n", code, monthKey)
+	return err
+}
+
+// parseCurrency normalizes a -currency value. The default currency is
+// stored as empty so that records follow it.
+func parseCurrency(value string) string {
+	currency := domain.NormalizeCurrency(value)
+	if currency == domain.NormalizeCurrency(viper.GetString(config.CurrencyField)) {
+		return ""
+	}
+	return currency
+}
+
+// recordCurrency returns the currency a record is kept in.
+func recordCurrency(currency string) string {
+	if currency == "" {
+		return viper.GetString(config.CurrencyField)
+	}
+	return currency
+}
@@ -0,0 +1,353 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+)
+
+// templateIntervals maps the named values of the -every flag to months.
+var templateIntervals = map[string]int{
+	"monthly":   1,
+	"quarterly": 3,
+	"yearly":    12,
+}
+
+// templateFlags holds the flags shared by recurring add and update.
+type templateFlags struct {
+	name     *string
+	amount   *string
+	budget   *string
+	currency *string
+	every    *string
+	start    *string
+	end      *string
+}
+
+// registerTemplateFlags registers the template flags on fs.
+func registerTemplateFlags(fs *flag.FlagSet) templateFlags {
+	return templateFlags{
+		name:     fs.String("name", "", "Category name for expenses, description for incomes"),
+		amount:   fs.String("amount", "", "Amount of every occurrence"),
+		budget:   fs.String("budget", "", "Budget of every occurrence, expenses only"),
+		currency: fs.String("currency", "", "Currency, defaults to the default currency"),
+		every:    fs.String("every", "monthly", "monthly, quarterly, yearly or a number of months"),
+		start:    fs.String("start", time.Now().Format(monthLayout), "First month in YYYY-MM format"),
+		end:      fs.String("end", "", "Last month in YYYY-MM format, empty for no end"),
+	}
+}
+
+// apply copies the flags that were set into template.
+func (f templateFlags) apply(fs *flag.FlagSet, template *domain.RecurringTemplate, creating bool) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if set["name"] {
+		template.Name = strings.TrimSpace(*f.name)
+	}
+	if set["amount"] {
+		value, err := ui.ValidAmount(*f.amount)
+		if err != nil {
+			return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+		}
+		template.Amount = value
+	}
+	if set["budget"] {
+		value, err := ui.ValidAmount(*f.budget)
+		if err != nil {
+			return fmt.Errorf("%w: invalid budget: %v", ErrUsage, err)
+		}
+		template.Budget = value
+	}
+	if set["currency"] {
+		template.Currency = parseCurrency(*f.currency)
+	}
+	if creating || set["every"] {
+		interval, err := parseInterval(*f.every)
+		if err != nil {
+			return err
+		}
+		template.Interval = interval
+	}
+	if creating || set["start"] {
+		monthKey, err := parseMonthKey(*f.start)
+		if err != nil {
+			return err
+		}
+		template.StartMonth = monthKey
+	}
+	if set["end"] {
+		template.EndMonth = ""
+		if strings.TrimSpace(*f.end) != "" {
+			monthKey, err := parseMonthKey(*f.end)
+			if err != nil {
+				return err
+			}
+			template.EndMonth = monthKey
+		}
+	}
+	return nil
+}
+
+// recurringList prints all recurring templates.
+func (c *CLI) recurringList(args []string) error {
+	fs := c.newFlagSet("recurring list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	templates, err := c.recurringSvc.GetAllTemplates()
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		_, err := fmt.Fprintln(c.out, "No recurring templates.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tKIND\tAMOUNT\tBUDGET\tEVERY\tSTART\tEND\tID")
+	for _, template := range templates {
+		currency := recordCurrency(template.Currency)
+		end := template.EndMonth
+		if end == "" {
+			end = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s %s\t%s %s\t%s\t%s\t%s\t%s\n",
+			template.Name, template.Kind, template.Amount.StringFixed(2), currency, template.Budget.StringFixed(2), currency,
+			formatInterval(template.Interval), template.StartMonth, end, template.TemplateID)
+	}
+	return w.Flush()
+}
+
+// recurringAdd creates a new recurring template.
+func (c *CLI) recurringAdd(args []string) error {
+	fs := c.newFlagSet("recurring add")
+	kind := fs.String("kind", "", "Kind of the template: expense or income")
+	flags := registerTemplateFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("kind", *kind); err != nil {
+		return err
+	}
+	if err := requireFlag("name", *flags.name); err != nil {
+		return err
+	}
+
+	template := domain.RecurringTemplate{
+		TemplateID: ui.GenerateID(),
+		Kind:       strings.ToLower(strings.TrimSpace(*kind)),
+	}
+	if template.Kind != domain.TemplateExpense && template.Kind != domain.TemplateIncome {
+		return fmt.Errorf("%w: kind must be expense or income, got %q", ErrUsage, *kind)
+	}
+	if template.Kind == domain.TemplateIncome {
+		if err := requireFlag("amount", *flags.amount); err != nil {
+			return err
+		}
+	}
+	if err := flags.apply(fs, &template, true); err != nil {
+		return err
+	}
+
+	if err := c.recurringSvc.AddTemplate(template); err != nil {
+		return fmt.Errorf("failed to add recurring template: %w", err)
+	}
+
+	_, err := fmt.Fprintf(c.out, "Recurring %s '%s' added (ID: %s)\n", template.Kind, template.Name, template.TemplateID)
+	return err
+}
+
+// recurringUpdate changes an existing recurring template.
+func (c *CLI) recurringUpdate(args []string) error {
+	fs := c.newFlagSet("recurring update")
+	ref := fs.String("template", "", "Name or ID of the template")
+	flags := registerTemplateFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("template", *ref); err != nil {
+		return err
+	}
+
+	template, err := c.findTemplate(*ref)
+	if err != nil {
+		return err
+	}
+	if err := flags.apply(fs, &template, false); err != nil {
+		return err
+	}
+
+	if err := c.recurringSvc.UpdateTemplate(template); err != nil {
+		return fmt.Errorf("failed to update recurring template: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Recurring %s '%s' updated\n", template.Kind, template.Name)
+	return err
+}
+
+// recurringDelete removes a recurring template. Records it already created are kept.
+func (c *CLI) recurringDelete(args []string) error {
+	fs := c.newFlagSet("recurring delete")
+	ref := fs.String("template", "", "Name or ID of the template")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("template", *ref); err != nil {
+		return err
+	}
+
+	template, err := c.findTemplate(*ref)
+	if err != nil {
+		return err
+	}
+	if err := c.recurringSvc.DeleteTemplate(template.TemplateID); err != nil {
+		return fmt.Errorf("failed to delete recurring template: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Recurring %s '%s' deleted\n", template.Kind, template.Name)
+	return err
+}
+
+// recurringSkip skips a single occurrence of a recurring template.
+func (c *CLI) recurringSkip(args []string) error {
+	fs := c.newFlagSet("recurring skip")
+	month := monthFlag(fs)
+	ref := fs.String("template", "", "Name or ID of the template")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("template", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	template, err := c.findTemplate(*ref)
+	if err != nil {
+		return err
+	}
+	if err := c.recurringSvc.SkipOccurrence(template.TemplateID, monthKey); err != nil {
+		return fmt.Errorf("failed to skip occurrence: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Recurring %s '%s' skipped in %s\n", template.Kind, template.Name, monthKey)
+	return err
+}
+
+// recurringOverride changes the amount or budget of a single occurrence of a recurring template.
+func (c *CLI) recurringOverride(args []string) error {
+	fs := c.newFlagSet("recurring override")
+	month := monthFlag(fs)
+	ref := fs.String("template", "", "Name or ID of the template")
+	amount := fs.String("amount", "", "Amount of this occurrence")
+	budget := fs.String("budget", "", "Budget of this occurrence")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlag("template", *ref); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	var amountValue, budgetValue *decimal.Decimal
+	if strings.TrimSpace(*amount) != "" {
+		value, err := ui.ValidAmount(*amount)
+		if err != nil {
+			return fmt.Errorf("%w: invalid amount: %v", ErrUsage, err)
+		}
+		amountValue = &value
+	}
+	if strings.TrimSpace(*budget) != "" {
+		value, err := ui.ValidAmount(*budget)
+		if err != nil {
+			return fmt.Errorf("%w: invalid budget: %v", ErrUsage, err)
+		}
+		budgetValue = &value
+	}
+	if amountValue == nil && budgetValue == nil {
+		return fmt.Errorf("%w: -amount or -budget is required", ErrUsage)
+	}
+
+	template, err := c.findTemplate(*ref)
+	if err != nil {
+		return err
+	}
+	if err := c.recurringSvc.OverrideOccurrence(template.TemplateID, monthKey, amountValue, budgetValue); err != nil {
+		return fmt.Errorf("failed to override occurrence: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.out, "Recurring %s '%s' overridden in %s\n", template.Kind, template.Name, monthKey)
+	return err
+}
+
+// recurringApply applies the recurring templates to a month.
+func (c *CLI) recurringApply(args []string) error {
+	fs := c.newFlagSet("recurring apply")
+	month := monthFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	monthKey, err := parseMonthKey(*month)
+	if err != nil {
+		return err
+	}
+
+	count, err := c.recurringSvc.Materialize(monthKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.out, "Applied %d recurring templates to %s\n", count, monthKey)
+	return err
+}
+
+// findTemplate looks up a recurring template by ID or, case-insensitively, by name.
+func (c *CLI) findTemplate(ref string) (domain.RecurringTemplate, error) {
+	templates, err := c.recurringSvc.GetAllTemplates()
+	if err != nil {
+		return domain.RecurringTemplate{}, err
+	}
+	for _, template := range templates {
+		if template.TemplateID == ref || strings.EqualFold(template.Name, ref) {
+			return template, nil
+		}
+	}
+	return domain.RecurringTemplate{}, fmt.Errorf("recurring template %q not found", ref)
+}
+
+// parseInterval converts an -every value into a number of months.
+func parseInterval(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if interval, ok := templateIntervals[value]; ok {
+		return interval, nil
+	}
+	interval, err := strconv.Atoi(value)
+	if err != nil || interval < 1 {
+		return 0, fmt.Errorf("%w: every must be monthly, quarterly, yearly or a number of months, got %q", ErrUsage, value)
+	}
+	return interval, nil
+}
+
+// formatInterval returns the name of an interval of months.
+func formatInterval(interval int) string {
+	for name, months := range templateIntervals {
+		if months == interval {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d months", interval)
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// restore lists the backups of the data file, or restores the one selected with -backup or -latest.
+func (c *CLI) restore(args []string) error {
+	fs := c.newFlagSet("restore")
+	name := fs.String("backup", "", "Name of the backup to restore")
+	latest := fs.Bool("latest", false, "Restore the most recent backup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if c.backups == nil {
+		return errors.New("backups are only available for the json storage")
+	}
+
+	backups, err := c.backups.List(c.dataFilePath)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(*name) == "" && !*latest {
+		if len(backups) == 0 {
+			_, err := fmt.Fprintf(c.out, "No backups found in %s.\n", c.backups.Dir())
+			return err
+		}
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "CREATED\tSIZE\tNAME")
+		for _, backup := range backups {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", backup.CreatedAt.Format("2006-01-02 15:04:05"), backup.Size, backup.Name)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(c.out, "\nRun 'gocost restore -backup <name>' to restore a backup.")
+		return err
+	}
+
+	selected := strings.TrimSpace(*name)
+	if *latest {
+		if len(backups) == 0 {
+			return fmt.Errorf("no backups found in %s", c.backups.Dir())
+		}
+		selected = backups[0].Name
+	}
+
+	restored, err := c.backups.Restore(selected, c.dataFilePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.out, "Restored %s from backup %s\n", c.dataFilePath, restored.Name)
+	return err
+}
@@ -8,21 +8,53 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/viper"
 )
 
 const (
-	CurrencyField = "currency"
-	DataDirField  = "dataDir"
-	DataFileField = "dataFilename"
-
-	DefaultCurrency     = "USD"
-	dataDir             = ".gocost"
-	defaultDataFilename = "expenses_data.json"
-	defaultConfigName   = "config"
-	defaultConfigType   = "json"
+	CurrencyField        = "currency"
+	DisplayCurrencyField = "displayCurrency"
+	DataDirField         = "dataDir"
+	DataFileField        = "dataFilename"
+	StorageField         = "storage"
+	DatabaseFileField    = "databaseFilename"
+	ImportLayoutsField   = "importLayouts"
+	ImportRulesField     = "importRules"
+	ExportDirField       = "exportDir"
+
+	BackupDirName = "backups"
+	ExportDirName = "exports"
+
+	StorageJSON   = "json"
+	StorageSQLite = "sqlite"
+
+	// PassphraseEnv is the environment variable holding the passphrase of
+	// an encrypted data file, for runs without a terminal.
+	PassphraseEnv = "GOCOST_PASSPHRASE"
+	// NewPassphraseEnv is the environment variable holding the passphrase
+	// a data file is encrypted with, for runs without a terminal. It is
+	// separate from PassphraseEnv so changing a passphrase does not reuse
+	// the one unlocking the file.
+	NewPassphraseEnv = "GOCOST_NEW_PASSPHRASE"
+
+	DefaultCurrency         = "USD"
+	dataDir                 = ".gocost"
+	defaultDataFilename     = "expenses_data.json"
+	defaultDatabaseFilename = "expenses_data.db"
+	defaultConfigName       = "config"
+	defaultConfigType       = "json"
 )
 
+// DisplayCurrency returns the currency totals are converted into. It defaults
+// to the currency when no display currency is configured.
+func DisplayCurrency() string {
+	if currency := strings.ToUpper(strings.TrimSpace(viper.GetString(DisplayCurrencyField))); currency != "" {
+		return currency
+	}
+	return viper.GetString(CurrencyField)
+}
+
 // PromptForCurrency asks the user to enter a default currency
 func PromptForCurrency() string {
 	fmt.Println("Welcome to gocost! Please enter a default currency:")
@@ -48,6 +80,38 @@ func PromptForCurrency() string {
 	return currency
 }
 
+// ReadPassphrase returns the passphrase set in PassphraseEnv, or asks for it
+// on the terminal without echoing it.
+func ReadPassphrase(prompt string) (string, error) {
+	return readSecret(PassphraseEnv, prompt)
+}
+
+// ReadNewPassphrase returns the passphrase set in NewPassphraseEnv, or asks
+// for it on the terminal without echoing it.
+func ReadNewPassphrase(prompt string) (string, error) {
+	return readSecret(NewPassphraseEnv, prompt)
+}
+
+// readSecret returns the value of the environment variable env, or asks for
+// it on the terminal without echoing it.
+func readSecret(env, prompt string) (string, error) {
+	if secret := os.Getenv(env); secret != "" {
+		return secret, nil
+	}
+	fd := os.Stdin.Fd()
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("a passphrase is required, set %s when not running in a terminal", env)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(secret), nil
+}
+
 // getDefaultDataDir returns the default data directory path.
 func getDefaultDataDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -98,6 +162,11 @@ func LoadConfig(defaultCurrency string, configFilePath string) error {
 	viper.SetConfigName(defaultConfigName)
 	viper.SetConfigType(defaultConfigType)
 
+	// Storage settings are defaulted for configs written before they existed.
+	viper.SetDefault(StorageField, StorageJSON)
+	viper.SetDefault(DatabaseFileField, filepath.Join(dataDirPath, defaultDatabaseFilename))
+	viper.SetDefault(ExportDirField, filepath.Join(dataDirPath, ExportDirName))
+
 	if err := viper.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
 		if errors.As(err, &configFileNotFoundError) {
@@ -501,6 +501,16 @@ func TestLoadConfig(t *testing.T) {
 		if viper.GetString(DataFileField) != "" {
 			t.Errorf("expected DataFileField to be empty for existing config, got %s", viper.GetString(DataFileField))
 		}
+
+		// Verify storage falls back to the JSON backend for configs written before it existed
+		if viper.GetString(StorageField) != StorageJSON {
+			t.Errorf("expected storage %s, got %s", StorageJSON, viper.GetString(StorageField))
+		}
+
+		expectedDatabaseFile := filepath.Join(configDir, defaultDatabaseFilename)
+		if viper.GetString(DatabaseFileField) != expectedDatabaseFile {
+			t.Errorf("expected databaseFilename %s, got %s", expectedDatabaseFile, viper.GetString(DatabaseFileField))
+		}
 	})
 
 	t.Run("loads complete config file with all values", func(t *testing.T) {
@@ -673,3 +683,18 @@ func TestGetDefaultDataDirPathValidation(t *testing.T) {
 		}
 	})
 }
+
+func TestDisplayCurrency(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.Set(CurrencyField, "EUR")
+	if got := DisplayCurrency(); got != "EUR" {
+		t.Errorf("expected EUR without a display currency, got %s", got)
+	}
+
+	viper.Set(DisplayCurrencyField, " usd ")
+	if got := DisplayCurrency(); got != "USD" {
+		t.Errorf("expected USD, got %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	ProfilesField = "profiles"
+
+	// DefaultProfile is the name of the profile made of the top-level
+	// settings of the config file.
+	DefaultProfile = "default"
+)
+
+// Profile is a named budget with its own data file and currency, such as a
+// personal and a shared household budget. Empty fields of the profiles of the
+// config file fall back to the top-level settings, and data files default to
+// the profile name in the data directory.
+type Profile struct {
+	Name             string `mapstructure:"-"`
+	Currency         string `mapstructure:"currency"`
+	Storage          string `mapstructure:"storage"`
+	DataFilename     string `mapstructure:"dataFilename"`
+	DatabaseFilename string `mapstructure:"databaseFilename"`
+}
+
+var (
+	// activeProfile is the name of the profile selected with UseProfile.
+	activeProfile = DefaultProfile
+
+	// configCurrency is the top-level currency while another profile
+	// overrides it.
+	configCurrency string
+)
+
+// ActiveProfile returns the name of the profile in use.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// UseProfile makes profile the one in use, so that its currency is the
+// currency setting.
+func UseProfile(profile Profile) {
+	if activeProfile == DefaultProfile {
+		configCurrency = viper.GetString(CurrencyField)
+	}
+	activeProfile = profile.Name
+	viper.Set(CurrencyField, profile.Currency)
+}
+
+// Profiles returns the default profile followed by the profiles of the
+// config file in alphabetical order.
+func Profiles() ([]Profile, error) {
+	var configured map[string]Profile
+	if err := viper.UnmarshalKey(ProfilesField, &configured); err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	base := defaultProfile()
+	names := make([]string, 0, len(configured))
+	for name := range configured {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	profiles := []Profile{base}
+	for _, name := range names {
+		if name == DefaultProfile {
+			return nil, fmt.Errorf("profile name %q is reserved for the top-level settings", DefaultProfile)
+		}
+		profiles = append(profiles, resolveProfile(name, configured[name], base))
+	}
+	return profiles, nil
+}
+
+// GetProfile returns the profile named name, the default profile when name
+// is empty. Names are case-insensitive.
+func GetProfile(name string) (Profile, error) {
+	profiles, err := Profiles()
+	if err != nil {
+		return Profile{}, err
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+		names[i] = profile.Name
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q, available profiles: %s", name, strings.Join(names, ", "))
+}
+
+// defaultProfile returns the profile made of the top-level settings.
+func defaultProfile() Profile {
+	currency := viper.GetString(CurrencyField)
+	if activeProfile != DefaultProfile {
+		currency = configCurrency
+	}
+	return Profile{
+		Name:             DefaultProfile,
+		Currency:         currency,
+		Storage:          viper.GetString(StorageField),
+		DataFilename:     viper.GetString(DataFileField),
+		DatabaseFilename: viper.GetString(DatabaseFileField),
+	}
+}
+
+// resolveProfile fills the empty fields of a profile of the config file from
+// base. Relative file names are in the data directory.
+func resolveProfile(name string, profile Profile, base Profile) Profile {
+	dataDirPath := viper.GetString(DataDirField)
+	if dataDirPath == "" {
+		dataDirPath = filepath.Dir(base.DataFilename)
+	}
+	inDataDir := func(filename, fallback string) string {
+		if filename == "" {
+			filename = fallback
+		}
+		if filepath.IsAbs(filename) {
+			return filename
+		}
+		return filepath.Join(dataDirPath, filename)
+	}
+
+	profile.Name = name
+	profile.Currency = strings.ToUpper(strings.TrimSpace(profile.Currency))
+	if profile.Currency == "" {
+		profile.Currency = base.Currency
+	}
+	if profile.Storage == "" {
+		profile.Storage = base.Storage
+	}
+	profile.DataFilename = inDataDir(profile.DataFilename, name+".json")
+	profile.DatabaseFilename = inDataDir(profile.DatabaseFilename, name+".db")
+	return profile
+}
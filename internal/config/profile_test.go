@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestProfiles(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+		activeProfile = DefaultProfile
+	})
+
+	dataDirPath := t.TempDir()
+	viper.Set(CurrencyField, "USD")
+	viper.Set(DataDirField, dataDirPath)
+	viper.Set(DataFileField, filepath.Join(dataDirPath, defaultDataFilename))
+	viper.Set(StorageField, StorageJSON)
+	viper.Set(ProfilesField, map[string]any{
+		"household": map[string]any{"currency": "eur", "dataFilename": "shared.json"},
+		"archive":   map[string]any{"storage": StorageSQLite, "databaseFilename": "/srv/gocost/archive.db"},
+	})
+
+	profiles, err := Profiles()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	if got := strings.Join(names, ","); got != "default,archive,household" {
+		t.Errorf("expected default,archive,household, got %s", got)
+	}
+
+	household, err := GetProfile(" Household ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if household.Currency != "EUR" {
+		t.Errorf("expected EUR, got %s", household.Currency)
+	}
+	if want := filepath.Join(dataDirPath, "shared.json"); household.DataFilename != want {
+		t.Errorf("expected %s, got %s", want, household.DataFilename)
+	}
+	if household.Storage != StorageJSON {
+		t.Errorf("expected the storage of the top-level settings, got %s", household.Storage)
+	}
+
+	archive, err := GetProfile("archive")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if archive.Currency != "USD" || archive.DatabaseFilename != "/srv/gocost/archive.db" {
+		t.Errorf("unexpected archive profile %+v", archive)
+	}
+	if want := filepath.Join(dataDirPath, "archive.json"); archive.DataFilename != want {
+		t.Errorf("expected %s, got %s", want, archive.DataFilename)
+	}
+
+	if _, err := GetProfile("work"); err == nil || !strings.Contains(err.Error(), "default, archive, household") {
+		t.Errorf("expected an error listing the profiles, got %v", err)
+	}
+
+	t.Run("UseProfile", func(t *testing.T) {
+		UseProfile(household)
+		if ActiveProfile() != "household" || viper.GetString(CurrencyField) != "EUR" {
+			t.Errorf("expected household with EUR, got %s with %s", ActiveProfile(), viper.GetString(CurrencyField))
+		}
+
+		// The top-level currency is kept while it is overridden
+		base, err := GetProfile("")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if base.Name != DefaultProfile || base.Currency != "USD" {
+			t.Errorf("expected the default profile with USD, got %+v", base)
+		}
+
+		UseProfile(base)
+		if ActiveProfile() != DefaultProfile || viper.GetString(CurrencyField) != "USD" {
+			t.Errorf("expected default with USD, got %s with %s", ActiveProfile(), viper.GetString(CurrencyField))
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBackupLimit is the number of backups kept for a data file.
+	DefaultBackupLimit = 10
+
+	backupTimeLayout = "20060102-150405.000"
+
+	// dataFileMode is the permission of data files and their copies, which
+	// are only readable by their owner.
+	dataFileMode = 0600
+)
+
+// Backup describes a timestamped copy of a data file.
+type Backup struct {
+	Name      string
+	Path      string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Backups manages a rolling set of timestamped copies of a data file.
+type Backups struct {
+	dir   string
+	limit int
+}
+
+// NewBackups creates a Backups that stores copies in dir and keeps at most limit of them.
+func NewBackups(dir string, limit int) *Backups {
+	if limit <= 0 {
+		limit = DefaultBackupLimit
+	}
+	return &Backups{dir: dir, limit: limit}
+}
+
+// Dir returns the directory the backups are stored in.
+func (b *Backups) Dir() string {
+	return b.dir
+}
+
+// Create copies the file at sourcePath into the backup directory and prunes
+// the oldest copies beyond the limit. It returns an empty Backup when there is
+// no source file yet.
+func (b *Backups) Create(sourcePath string) (Backup, error) {
+	content, err := os.ReadFile(sourcePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Backup{}, nil
+	}
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to read %s for backup: %w", sourcePath, err)
+	}
+
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return Backup{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Backups created within the same millisecond get distinct names.
+	now := time.Now()
+	prefix, ext := backupNameParts(sourcePath)
+	name := prefix + now.Format(backupTimeLayout) + ext
+	path := filepath.Join(b.dir, name)
+	for {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		now = now.Add(time.Millisecond)
+		name = prefix + now.Format(backupTimeLayout) + ext
+		path = filepath.Join(b.dir, name)
+	}
+
+	if err := writeFileAtomic(path, content, dataFileMode); err != nil {
+		return Backup{}, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := b.prune(sourcePath); err != nil {
+		return Backup{}, err
+	}
+
+	return Backup{Name: name, Path: path, CreatedAt: now, Size: int64(len(content))}, nil
+}
+
+// List returns the backups of the file at sourcePath, newest first.
+func (b *Backups) List(sourcePath string) ([]Backup, error) {
+	entries, err := os.ReadDir(b.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	prefix, ext := backupNameParts(sourcePath)
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		createdAt, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{
+			Name:      name,
+			Path:      filepath.Join(b.dir, name),
+			CreatedAt: createdAt,
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Restore replaces the file at targetPath with the named backup. The current
+// file is backed up first so that the restore itself can be undone.
+func (b *Backups) Restore(name string, targetPath string) (Backup, error) {
+	backups, err := b.List(targetPath)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var selected *Backup
+	for i := range backups {
+		if backups[i].Name == name {
+			selected = &backups[i]
+			break
+		}
+	}
+	if selected == nil {
+		return Backup{}, fmt.Errorf("backup %q not found in %s", name, b.dir)
+	}
+
+	content, err := os.ReadFile(selected.Path)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if _, err := b.Create(targetPath); err != nil {
+		return Backup{}, err
+	}
+
+	if err := writeFileAtomic(targetPath, content, dataFileMode); err != nil {
+		return Backup{}, fmt.Errorf("failed to restore backup: %w", err)
+	}
+	return *selected, nil
+}
+
+// prune removes the oldest backups of sourcePath beyond the limit.
+func (b *Backups) prune(sourcePath string) error {
+	backups, err := b.List(sourcePath)
+	if err != nil {
+		return err
+	}
+	for i := b.limit; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// backupNameParts returns the name prefix and extension used for the backups of sourcePath.
+func backupNameParts(sourcePath string) (string, string) {
+	base := filepath.Base(sourcePath)
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// writeFileAtomic writes data to a temporary file next to path, flushes it to
+// disk and renames it over path, so readers never observe a partial file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file unless it has been renamed into place.
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	renamed = true
+
+	// Persist the rename itself. Not every platform supports syncing a directory.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
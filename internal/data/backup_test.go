@@ -0,0 +1,119 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0644))
+	require.NoError(t, writeFileAtomic(path, []byte("new"), 0644))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestBackups(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "expenses_data.json")
+	backupDir := filepath.Join(dir, "backups")
+
+	t.Run("Create without source file", func(t *testing.T) {
+		backups := NewBackups(backupDir, 3)
+		backup, err := backups.Create(dataPath)
+		require.NoError(t, err)
+		assert.Empty(t, backup.Name)
+	})
+
+	t.Run("Create and prune", func(t *testing.T) {
+		backups := NewBackups(backupDir, 3)
+		require.NoError(t, os.MkdirAll(backupDir, 0755))
+
+		// Seed older backups with distinct timestamps
+		base := time.Now().Add(-time.Hour)
+		for i := range 4 {
+			name := "expenses_data-" + base.Add(time.Duration(i)*time.Minute).Format(backupTimeLayout) + ".json"
+			require.NoError(t, os.WriteFile(filepath.Join(backupDir, name), []byte("{}"), 0644))
+		}
+		// Files of other data files are ignored
+		require.NoError(t, os.WriteFile(filepath.Join(backupDir, "other-20240101-000000.000.json"), []byte("{}"), 0644))
+
+		require.NoError(t, os.WriteFile(dataPath, []byte(`{"current":true}`), 0644))
+		backup, err := backups.Create(dataPath)
+		require.NoError(t, err)
+
+		list, err := backups.List(dataPath)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, backup.Name, list[0].Name)
+		assert.True(t, list[0].CreatedAt.After(list[1].CreatedAt))
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		backups := NewBackups(backupDir, 10)
+		list, err := backups.List(dataPath)
+		require.NoError(t, err)
+		oldest := list[len(list)-1]
+
+		restored, err := backups.Restore(oldest.Name, dataPath)
+		require.NoError(t, err)
+		assert.Equal(t, oldest.Name, restored.Name)
+
+		content, err := os.ReadFile(dataPath)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(content))
+
+		// The replaced file was backed up before restoring
+		after, err := backups.List(dataPath)
+		require.NoError(t, err)
+		assert.Len(t, after, len(list)+1)
+	})
+
+	t.Run("Restore unknown backup", func(t *testing.T) {
+		backups := NewBackups(backupDir, 10)
+		_, err := backups.Restore("missing.json", dataPath)
+		assert.Error(t, err)
+	})
+}
+
+func TestJsonRepository_BackupOncePerSession(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "expenses_data.json")
+	backups := NewBackups(filepath.Join(dir, "backups"), 5)
+
+	repo, err := NewJsonRepository(dataPath, "USD")
+	require.NoError(t, err)
+	repo.SetBackups(backups)
+
+	// The first save has no file to back up yet
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Group 1"}))
+	list, err := backups.List(dataPath)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	// A new session backs up the existing file once
+	repo, err = NewJsonRepository(dataPath, "USD")
+	require.NoError(t, err)
+	repo.SetBackups(backups)
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Group 2"}))
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g3", GroupName: "Group 3"}))
+
+	list, err = backups.List(dataPath)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
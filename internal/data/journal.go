@@ -0,0 +1,196 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// JournalPath returns the path of the change journal kept next to the data
+// file at dataFilePath.
+func JournalPath(dataFilePath string) string {
+	return strings.TrimSuffix(dataFilePath, filepath.Ext(dataFilePath)) + ".journal.jsonl"
+}
+
+// Journal is an append-only file of changes, one JSON object per line. When
+// the data file is encrypted, every line is encrypted too.
+type Journal struct {
+	path   string
+	user   string
+	cipher *fileCipher
+
+	changes []domain.Change // Changes of a journal kept in memory, see NewMemoryJournal
+}
+
+// NewJournal creates a Journal stored at path. Changes are recorded under
+// the name of the current user.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path, user: currentUserName()}
+}
+
+// NewMemoryJournal creates a Journal kept in memory, for data that is not
+// stored in a file either.
+func NewMemoryJournal() *Journal {
+	return &Journal{user: currentUserName()}
+}
+
+// currentUserName returns the login name of the user running gocost.
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Path returns the path of the journal file, empty when it is kept in memory.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Append adds change to the end of the journal, setting its time and user
+// when they are empty.
+func (j *Journal) Append(change domain.Change) error {
+	if change.Time.IsZero() {
+		change.Time = time.Now()
+	}
+	if change.User == "" {
+		change.User = j.user
+	}
+	if j.path == "" {
+		j.changes = append(j.changes, change)
+		return nil
+	}
+
+	line, err := j.encode(change)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, dataFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return f.Close()
+}
+
+// GetChanges returns the changes selected by filter, newest first.
+func (j *Journal) GetChanges(filter domain.ChangeFilter) ([]domain.Change, error) {
+	changes, err := j.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []domain.Change{}
+	for i := len(changes) - 1; i >= 0; i-- {
+		if !filter.Matches(changes[i]) {
+			continue
+		}
+		selected = append(selected, changes[i])
+		if filter.Limit > 0 && len(selected) == filter.Limit {
+			break
+		}
+	}
+	return selected, nil
+}
+
+// readAll returns every change of the journal, oldest first. Lines that
+// cannot be decoded, such as one cut short by a crash, are skipped.
+func (j *Journal) readAll() ([]domain.Change, error) {
+	if j.path == "" {
+		return j.changes, nil
+	}
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var changes []domain.Change
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			change, decodeErr := j.decode(line)
+			if errors.Is(decodeErr, ErrPassphraseRequired) || errors.Is(decodeErr, ErrWrongPassphrase) {
+				return nil, decodeErr
+			}
+			if decodeErr == nil {
+				changes = append(changes, change)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return changes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+	}
+}
+
+// encode returns the journal line of change.
+func (j *Journal) encode(change domain.Change) ([]byte, error) {
+	line, err := json.Marshal(change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change: %w", err)
+	}
+	if j.cipher == nil {
+		return line, nil
+	}
+	return j.cipher.seal(line)
+}
+
+// decode parses a journal line.
+func (j *Journal) decode(line []byte) (domain.Change, error) {
+	if isEncrypted(line) {
+		if j.cipher == nil {
+			return domain.Change{}, ErrPassphraseRequired
+		}
+		var err error
+		if line, err = j.cipher.open(line); err != nil {
+			return domain.Change{}, err
+		}
+	}
+	var change domain.Change
+	err := json.Unmarshal(line, &change)
+	return change, err
+}
+
+// reseal rewrites the journal with fileCipher, or in plain text when it is nil.
+func (j *Journal) reseal(fileCipher *fileCipher) error {
+	changes, err := j.readAll()
+	if err != nil {
+		return err
+	}
+	j.cipher = fileCipher
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var content bytes.Buffer
+	for _, change := range changes {
+		line, err := j.encode(change)
+		if err != nil {
+			return err
+		}
+		content.Write(line)
+		content.WriteByte('\n')
+	}
+	return writeFileAtomic(j.path, content.Bytes(), dataFileMode)
+}
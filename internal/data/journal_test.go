@@ -0,0 +1,159 @@
+package data
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("data", "expenses_data.journal.jsonl"), JournalPath(filepath.Join("data", "expenses_data.json")))
+	assert.Equal(t, filepath.Join("data", "gocost.journal.jsonl"), JournalPath(filepath.Join("data", "gocost.db")))
+}
+
+func TestJournaledRepository(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "test_data.json")
+	repo, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	journal := NewJournal(JournalPath(filePath))
+	repo.SetJournal(journal)
+	journaled := NewJournaledRepository(repo, journal)
+
+	require.NoError(t, journaled.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+	require.NoError(t, journaled.UpdateGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Home"}))
+
+	rent := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}
+	require.NoError(t, journaled.AddCategory("2024-06", rent))
+	rent.Expense = map[string]domain.ExpenseRecord{
+		"c1": {Budget: decimal.NewFromInt(900), Amount: decimal.NewFromInt(850), Status: "Not Paid"},
+	}
+	require.NoError(t, journaled.UpdateCategory("2024-06", rent))
+	rent.Expense["c1"] = domain.ExpenseRecord{Budget: decimal.NewFromInt(900), Amount: decimal.NewFromInt(900), Status: "Paid"}
+	require.NoError(t, journaled.UpdateCategory("2024-06", rent))
+	// Saving the category unchanged records nothing
+	require.NoError(t, journaled.UpdateCategory("2024-06", rent))
+
+	require.NoError(t, journaled.AddIncome("2024-07", domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(3000)}))
+	require.NoError(t, journaled.DeleteIncome("2024-07", "i1"))
+
+	info, err := os.Stat(journal.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	t.Run("All changes newest first", func(t *testing.T) {
+		changes, err := journaled.GetChanges(domain.ChangeFilter{})
+		require.NoError(t, err)
+		require.Len(t, changes, 7)
+
+		assert.Equal(t, domain.ChangeDelete, changes[0].Action)
+		assert.Equal(t, domain.RecordIncome, changes[0].Record)
+		assert.Equal(t, "Salary", changes[0].Name)
+		assert.Empty(t, changes[0].After)
+		assert.Equal(t, journal.user, changes[0].User)
+		assert.False(t, changes[0].Time.IsZero())
+
+		assert.Equal(t, domain.ChangeUpdate, changes[2].Action)
+		assert.Equal(t, domain.RecordExpense, changes[2].Record)
+		assert.Equal(t, "2024-06", changes[2].MonthKey)
+		assert.Equal(t, "amount: 850 → 900, status: Not Paid → Paid", changes[2].Summary())
+
+		assert.Equal(t, domain.ChangeAdd, changes[3].Action)
+		assert.Equal(t, domain.RecordExpense, changes[3].Record)
+
+		assert.Equal(t, domain.RecordGroup, changes[5].Record)
+		assert.Equal(t, "groupName: Housing → Home", changes[5].Summary())
+		assert.Equal(t, domain.ChangeAdd, changes[6].Action)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		changes, err := journaled.GetChanges(domain.ChangeFilter{MonthKey: "2024-06"})
+		require.NoError(t, err)
+		assert.Len(t, changes, 3)
+
+		changes, err = journaled.GetChanges(domain.ChangeFilter{Category: "rent"})
+		require.NoError(t, err)
+		assert.Len(t, changes, 3)
+
+		changes, err = journaled.GetChanges(domain.ChangeFilter{Category: "c1", MonthKey: "2024-07"})
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		changes, err = journaled.GetChanges(domain.ChangeFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, domain.RecordIncome, changes[1].Record)
+	})
+
+	t.Run("Skips damaged lines", func(t *testing.T) {
+		f, err := os.OpenFile(journal.Path(), os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.WriteString("{\"time\":\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		changes, err := journaled.GetChanges(domain.ChangeFilter{})
+		require.NoError(t, err)
+		assert.Len(t, changes, 7)
+	})
+
+	t.Run("Encrypted with the data file", func(t *testing.T) {
+		defer func(iterations int) { keyIterations = iterations }(keyIterations)
+		keyIterations = 1000
+
+		require.NoError(t, repo.SetPassphrase("secret"))
+		require.NoError(t, journaled.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living"}))
+
+		content, err := os.ReadFile(journal.Path())
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "Housing")
+		assert.NotContains(t, string(content), "Living")
+
+		changes, err := journaled.GetChanges(domain.ChangeFilter{})
+		require.NoError(t, err)
+		assert.Len(t, changes, 8)
+
+		_, err = NewJournal(journal.Path()).GetChanges(domain.ChangeFilter{})
+		assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+		require.NoError(t, repo.SetPassphrase(""))
+		content, err = os.ReadFile(journal.Path())
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "Living")
+	})
+}
+
+func TestJournaledRepository_Atomically(t *testing.T) {
+	journal := NewMemoryJournal()
+	journaled := NewJournaledRepository(NewMemoryRepository(), journal)
+	require.NoError(t, journaled.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+	require.NoError(t, journaled.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+	require.NoError(t, journaled.AddCategory("2024-07", domain.Category{CatID: "c9", GroupID: "g1", CategoryName: "Old"}))
+
+	err := journaled.Atomically(func() error {
+		require.NoError(t, journaled.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living"}))
+		return errors.New("failed")
+	})
+	require.Error(t, err)
+	changes, err := journaled.GetChanges(domain.ChangeFilter{})
+	require.NoError(t, err)
+	assert.Len(t, changes, 3, "changes of a failed unit of work are not recorded")
+
+	count, err := journaled.CopyCategoriesFromMonth("2024-06", "2024-07")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	changes, err = journaled.GetChanges(domain.ChangeFilter{MonthKey: "2024-07"})
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, domain.ChangeAdd, changes[0].Action)
+	assert.Equal(t, "Rent", changes[0].Name)
+	assert.Equal(t, domain.ChangeDelete, changes[1].Action)
+	assert.Equal(t, "Old", changes[1].Name)
+}
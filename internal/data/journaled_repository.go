@@ -0,0 +1,268 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// JournaledRepository is a Repository recording every change made through
+// it in a Journal, with the values of the record before and after it.
+type JournaledRepository struct {
+	Repository
+	journal *Journal
+
+	units   int             // Number of units of work running, see Atomically
+	pending []domain.Change // Changes of the running unit of work
+}
+
+// NewJournaledRepository creates a JournaledRepository recording the changes
+// made to repo in journal.
+func NewJournaledRepository(repo Repository, journal *Journal) *JournaledRepository {
+	return &JournaledRepository{Repository: repo, journal: journal}
+}
+
+// GetChanges returns the journal entries selected by filter, newest first.
+func (r *JournaledRepository) GetChanges(filter domain.ChangeFilter) ([]domain.Change, error) {
+	return r.journal.GetChanges(filter)
+}
+
+// Atomically runs fn as a unit of work of the underlying repository. The
+// changes of the unit are recorded once it is saved, and not at all when it
+// fails.
+func (r *JournaledRepository) Atomically(fn func() error) error {
+	recorded := len(r.pending)
+	r.units++
+	err := r.Repository.Atomically(fn)
+	r.units--
+	if err != nil {
+		r.pending = r.pending[:recorded]
+		return err
+	}
+	if r.units > 0 {
+		return nil
+	}
+
+	changes := r.pending
+	r.pending = nil
+	for _, change := range changes {
+		if err := r.journal.Append(change); err != nil {
+			return fmt.Errorf("changes were saved but not recorded: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *JournaledRepository) AddGroup(group domain.CategoryGroup) error {
+	if err := r.Repository.AddGroup(group); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeAdd, domain.RecordGroup, "", group.GroupID, group.GroupName, nil, group)
+}
+
+func (r *JournaledRepository) UpdateGroup(group domain.CategoryGroup) error {
+	before, _ := r.Repository.GetGroupByID(group.GroupID)
+	if err := r.Repository.UpdateGroup(group); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeUpdate, domain.RecordGroup, "", group.GroupID, group.GroupName, before, group)
+}
+
+func (r *JournaledRepository) DeleteGroup(groupID string) error {
+	before, _ := r.Repository.GetGroupByID(groupID)
+	if err := r.Repository.DeleteGroup(groupID); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeDelete, domain.RecordGroup, "", groupID, before.GroupName, before, nil)
+}
+
+func (r *JournaledRepository) AddCategory(monthKey string, category domain.Category) error {
+	if err := r.Repository.AddCategory(monthKey, category); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeAdd, domain.RecordCategory, monthKey, category.CatID, category.CategoryName, nil, category)
+}
+
+// UpdateCategory records changes that only touch the expense of the category
+// as expense changes.
+func (r *JournaledRepository) UpdateCategory(monthKey string, category domain.Category) error {
+	before, found := r.findCategory(monthKey, category.CatID)
+	if err := r.Repository.UpdateCategory(monthKey, category); err != nil {
+		return err
+	}
+	if !found || before.CategoryName != category.CategoryName || before.GroupID != category.GroupID {
+		return r.record(domain.ChangeUpdate, domain.RecordCategory, monthKey, category.CatID, category.CategoryName, before, category)
+	}
+
+	beforeExpense, hadExpense := before.Expense[category.CatID]
+	afterExpense, hasExpense := category.Expense[category.CatID]
+	switch {
+	case !hadExpense && hasExpense:
+		return r.record(domain.ChangeAdd, domain.RecordExpense, monthKey, category.CatID, category.CategoryName, nil, afterExpense)
+	case hadExpense && !hasExpense:
+		return r.record(domain.ChangeDelete, domain.RecordExpense, monthKey, category.CatID, category.CategoryName, beforeExpense, nil)
+	case hadExpense && hasExpense:
+		return r.record(domain.ChangeUpdate, domain.RecordExpense, monthKey, category.CatID, category.CategoryName, beforeExpense, afterExpense)
+	}
+	return nil
+}
+
+func (r *JournaledRepository) DeleteCategory(monthKey string, categoryID string) error {
+	before, _ := r.findCategory(monthKey, categoryID)
+	if err := r.Repository.DeleteCategory(monthKey, categoryID); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeDelete, domain.RecordCategory, monthKey, categoryID, before.CategoryName, before, nil)
+}
+
+// CopyCategoriesFromMonth records the removal of the categories replaced
+// and the addition of every copied category.
+func (r *JournaledRepository) CopyCategoriesFromMonth(fromMonthKey, toMonthKey string) (int, error) {
+	return copyCategories(r, fromMonthKey, toMonthKey)
+}
+
+func (r *JournaledRepository) AddIncome(monthKey string, income domain.IncomeRecord) error {
+	if err := r.Repository.AddIncome(monthKey, income); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeAdd, domain.RecordIncome, monthKey, income.IncomeID, income.Description, nil, income)
+}
+
+func (r *JournaledRepository) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
+	before, _ := r.findIncome(monthKey, income.IncomeID)
+	if err := r.Repository.UpdateIncome(monthKey, income); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeUpdate, domain.RecordIncome, monthKey, income.IncomeID, income.Description, before, income)
+}
+
+func (r *JournaledRepository) DeleteIncome(monthKey string, incomeID string) error {
+	before, _ := r.findIncome(monthKey, incomeID)
+	if err := r.Repository.DeleteIncome(monthKey, incomeID); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeDelete, domain.RecordIncome, monthKey, incomeID, before.Description, before, nil)
+}
+
+func (r *JournaledRepository) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	before, found := r.findRate(monthKey, rate.Currency)
+	if err := r.Repository.SetRate(monthKey, rate); err != nil {
+		return err
+	}
+	if !found {
+		return r.record(domain.ChangeAdd, domain.RecordRate, monthKey, rate.Currency, rate.Currency, nil, rate)
+	}
+	return r.record(domain.ChangeUpdate, domain.RecordRate, monthKey, rate.Currency, rate.Currency, before, rate)
+}
+
+func (r *JournaledRepository) DeleteRate(monthKey string, currency string) error {
+	before, _ := r.findRate(monthKey, currency)
+	if err := r.Repository.DeleteRate(monthKey, currency); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeDelete, domain.RecordRate, monthKey, currency, currency, before, nil)
+}
+
+func (r *JournaledRepository) AddTemplate(template domain.RecurringTemplate) error {
+	if err := r.Repository.AddTemplate(template); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeAdd, domain.RecordTemplate, "", template.TemplateID, template.Name, nil, template)
+}
+
+func (r *JournaledRepository) UpdateTemplate(template domain.RecurringTemplate) error {
+	before, _ := r.findTemplate(template.TemplateID)
+	if err := r.Repository.UpdateTemplate(template); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeUpdate, domain.RecordTemplate, "", template.TemplateID, template.Name, before, template)
+}
+
+func (r *JournaledRepository) DeleteTemplate(templateID string) error {
+	before, _ := r.findTemplate(templateID)
+	if err := r.Repository.DeleteTemplate(templateID); err != nil {
+		return err
+	}
+	return r.record(domain.ChangeDelete, domain.RecordTemplate, "", templateID, before.Name, before, nil)
+}
+
+// record appends a change to the journal. Updates that leave the record as
+// it was are not recorded.
+func (r *JournaledRepository) record(action, record, monthKey, recordID, name string, before, after any) error {
+	change := domain.Change{
+		Action:   action,
+		Record:   record,
+		MonthKey: monthKey,
+		RecordID: recordID,
+		Name:     name,
+	}
+	var err error
+	if before != nil {
+		if change.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("failed to record change: %w", err)
+		}
+	}
+	if after != nil {
+		if change.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("failed to record change: %w", err)
+		}
+	}
+	if action == domain.ChangeUpdate && bytes.Equal(change.Before, change.After) {
+		return nil
+	}
+	if r.units > 0 {
+		r.pending = append(r.pending, change)
+		return nil
+	}
+
+	if err := r.journal.Append(change); err != nil {
+		return fmt.Errorf("change was saved but not recorded: %w", err)
+	}
+	return nil
+}
+
+// findCategory returns the category of a month with the given ID.
+func (r *JournaledRepository) findCategory(monthKey string, categoryID string) (domain.Category, bool) {
+	categories, _ := r.Repository.GetCategoriesForMonth(monthKey)
+	for _, category := range categories {
+		if category.CatID == categoryID {
+			return category, true
+		}
+	}
+	return domain.Category{}, false
+}
+
+// findIncome returns the income of a month with the given ID.
+func (r *JournaledRepository) findIncome(monthKey string, incomeID string) (domain.IncomeRecord, bool) {
+	incomes, _ := r.Repository.GetIncomesForMonth(monthKey)
+	for _, income := range incomes {
+		if income.IncomeID == incomeID {
+			return income, true
+		}
+	}
+	return domain.IncomeRecord{}, false
+}
+
+// findRate returns the exchange rate of a currency for a month.
+func (r *JournaledRepository) findRate(monthKey string, currency string) (domain.ExchangeRate, bool) {
+	rates, _ := r.Repository.GetRatesForMonth(monthKey)
+	for _, rate := range rates {
+		if rate.Currency == currency {
+			return rate, true
+		}
+	}
+	return domain.ExchangeRate{}, false
+}
+
+// findTemplate returns the recurring template with the given ID.
+func (r *JournaledRepository) findTemplate(templateID string) (domain.RecurringTemplate, bool) {
+	templates, _ := r.Repository.GetAllTemplates()
+	for _, template := range templates {
+		if template.TemplateID == templateID {
+			return template, true
+		}
+	}
+	return domain.RecurringTemplate{}, false
+}
@@ -0,0 +1,200 @@
+package data
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// encryptedFileFormat identifies encrypted data files.
+const encryptedFileFormat = "gocost-encrypted"
+
+// keyDerivation is the key derivation function of encrypted data files.
+const keyDerivation = "pbkdf2-sha256"
+
+// keyIterations is the PBKDF2 iteration count used for new passphrases.
+// Files record their own count, so it can be raised without breaking them.
+var keyIterations = 600_000
+
+// saltSize is the size in bytes of the random salt of a passphrase.
+const saltSize = 16
+
+var (
+	// ErrPassphraseRequired is returned when an encrypted data file is
+	// opened without a passphrase.
+	ErrPassphraseRequired = errors.New("data file is encrypted, a passphrase is required")
+
+	// ErrWrongPassphrase is returned when an encrypted data file cannot be
+	// decrypted with the given passphrase.
+	ErrWrongPassphrase = errors.New("wrong passphrase or damaged data file")
+)
+
+// encryptedFile is the content of an encrypted data file. Data is the plain
+// data file sealed with AES-256-GCM, under a key derived from the passphrase
+// and Salt.
+type encryptedFile struct {
+	Format     string `json:"format"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// fileCipher encrypts and decrypts data files with a passphrase. The key is
+// derived when first needed and kept for the salt of the file, so it is
+// derived once per session.
+type fileCipher struct {
+	passphrase string
+	iterations int
+	salt       []byte
+	aead       cipher.AEAD
+}
+
+// newFileCipher creates a fileCipher for passphrase.
+func newFileCipher(passphrase string) *fileCipher {
+	return &fileCipher{passphrase: passphrase}
+}
+
+// deriveKey derives the key of salt and iterations from the passphrase.
+func (c *fileCipher) deriveKey(salt []byte, iterations int) error {
+	key, err := pbkdf2.Key(sha256.New, c.passphrase, salt, iterations, 32)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	c.salt, c.iterations, c.aead = salt, iterations, aead
+	return nil
+}
+
+// seal encrypts the content of a plain data file on a single line. A new
+// random salt is used unless a file was already opened with the passphrase.
+func (c *fileCipher) seal(plain []byte) ([]byte, error) {
+	if c.aead == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		if err := c.deriveKey(salt, keyIterations); err != nil {
+			return nil, err
+		}
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return json.Marshal(encryptedFile{
+		Format:     encryptedFileFormat,
+		KDF:        keyDerivation,
+		Iterations: c.iterations,
+		Salt:       c.salt,
+		Nonce:      nonce,
+		Data:       c.aead.Seal(nil, nonce, plain, []byte(encryptedFileFormat)),
+	})
+}
+
+// open decrypts the content of an encrypted data file.
+func (c *fileCipher) open(content []byte) ([]byte, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to read encrypted data file: %w", err)
+	}
+	if file.KDF != keyDerivation {
+		return nil, fmt.Errorf("unsupported key derivation %q", file.KDF)
+	}
+	// Files written with another salt, e.g. after the passphrase was set
+	// again by another instance, need their own key
+	if c.aead == nil || !bytes.Equal(file.Salt, c.salt) || file.Iterations != c.iterations {
+		if err := c.deriveKey(file.Salt, file.Iterations); err != nil {
+			return nil, err
+		}
+	}
+	if len(file.Nonce) != c.aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plain, err := c.aead.Open(nil, file.Nonce, file.Data, []byte(encryptedFileFormat))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+// isEncrypted reports whether content is an encrypted data file.
+func isEncrypted(content []byte) bool {
+	var header struct {
+		Format string `json:"format"`
+	}
+	return json.Unmarshal(content, &header) == nil && header.Format == encryptedFileFormat
+}
+
+// IsEncryptedFile reports whether the data file at filePath is encrypted.
+// A missing file is not encrypted.
+func IsEncryptedFile(filePath string) (bool, error) {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return isEncrypted(content), nil
+}
+
+// NewEncryptedJsonRepository creates a JsonRepository for a data file
+// encrypted with passphrase. A plain file is read as it is and encrypted
+// the next time it is saved.
+func NewEncryptedJsonRepository(filePath string, defaultCurrency string, passphrase string) (*JsonRepository, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	return newJsonRepository(filePath, defaultCurrency, newFileCipher(passphrase))
+}
+
+// Encrypted reports whether the data file is written encrypted.
+func (r *JsonRepository) Encrypted() bool {
+	return r.cipher != nil
+}
+
+// SetPassphrase rewrites the data file and its journal encrypted with
+// passphrase, or in plain text when passphrase is empty.
+func (r *JsonRepository) SetPassphrase(passphrase string) error {
+	if err := r.checkExternalChange(); err != nil {
+		return err
+	}
+
+	previous := r.cipher
+	r.cipher = nil
+	if passphrase != "" {
+		r.cipher = newFileCipher(passphrase)
+	}
+	if err := r.write(); err != nil {
+		r.cipher = previous
+		return err
+	}
+	if r.journal != nil {
+		if err := r.journal.reseal(r.cipher); err != nil {
+			return fmt.Errorf("failed to rewrite journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetJournal encrypts the lines of journal like the data file.
+func (r *JsonRepository) SetJournal(journal *Journal) {
+	r.journal = journal
+	journal.cipher = r.cipher
+}
@@ -0,0 +1,83 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonRepository_Encryption(t *testing.T) {
+	// A low iteration count keeps the test fast, files record their own count
+	defer func(iterations int) { keyIterations = iterations }(keyIterations)
+	keyIterations = 1000
+
+	filePath := filepath.Join(t.TempDir(), "test_data.json")
+
+	repo, err := NewEncryptedJsonRepository(filePath, "USD", "secret")
+	require.NoError(t, err)
+	assert.True(t, repo.Encrypted())
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "Housing")
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	encrypted, err := IsEncryptedFile(filePath)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+
+	t.Run("Open", func(t *testing.T) {
+		_, err := NewJsonRepository(filePath, "USD")
+		assert.ErrorIs(t, err, ErrPassphraseRequired)
+
+		_, err = NewEncryptedJsonRepository(filePath, "USD", "wrong")
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+		opened, err := NewEncryptedJsonRepository(filePath, "USD", "secret")
+		require.NoError(t, err)
+		group, err := opened.GetGroupByID("g1")
+		require.NoError(t, err)
+		assert.Equal(t, "Housing", group.GroupName)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		other, err := NewEncryptedJsonRepository(filePath, "USD", "secret")
+		require.NoError(t, err)
+		require.NoError(t, other.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living"}))
+
+		reloaded, err := repo.Reload()
+		require.NoError(t, err)
+		assert.True(t, reloaded)
+		groups, err := repo.GetAllGroups()
+		require.NoError(t, err)
+		assert.Len(t, groups, 2)
+	})
+
+	t.Run("Change passphrase", func(t *testing.T) {
+		require.NoError(t, repo.SetPassphrase("changed"))
+
+		_, err := NewEncryptedJsonRepository(filePath, "USD", "secret")
+		assert.ErrorIs(t, err, ErrWrongPassphrase)
+		_, err = NewEncryptedJsonRepository(filePath, "USD", "changed")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Decrypt", func(t *testing.T) {
+		require.NoError(t, repo.SetPassphrase(""))
+		assert.False(t, repo.Encrypted())
+
+		plain, err := NewJsonRepository(filePath, "USD")
+		require.NoError(t, err)
+		groups, err := plain.GetAllGroups()
+		require.NoError(t, err)
+		assert.Len(t, groups, 2)
+	})
+}
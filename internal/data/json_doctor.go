@@ -0,0 +1,215 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// recoveredGroupName is the name of groups recreated for categories whose
+// group no longer exists.
+const recoveredGroupName = "Recovered group"
+
+// Issue is an inconsistency found in the data file. MonthKey is empty for
+// issues outside of the monthly data, and Repair is empty for issues that
+// must be fixed by hand.
+type Issue struct {
+	MonthKey    string
+	Description string
+	Repair      string
+}
+
+// Check returns the inconsistencies of the data file: groups stored under
+// another ID, missing lists, empty or duplicate IDs, categories of groups
+// that no longer exist and expenses kept under the ID of another category.
+func (r *JsonRepository) Check() ([]Issue, error) {
+	if err := r.loadExternalChange(); err != nil {
+		return nil, err
+	}
+	return diagnoseStore(r.store, false), nil
+}
+
+// Repair backs up the data file, repairs its inconsistencies and saves it.
+// It returns the repaired issues, including those that could not be
+// repaired, and the backup taken before repairing.
+func (r *JsonRepository) Repair() ([]Issue, Backup, error) {
+	if err := r.loadExternalChange(); err != nil {
+		return nil, Backup{}, err
+	}
+	issues := diagnoseStore(r.store, false)
+	if len(issues) == 0 {
+		return nil, Backup{}, nil
+	}
+	if r.backups == nil {
+		return nil, Backup{}, errors.New("repairing requires backups of the data file")
+	}
+
+	backup, err := r.backups.Create(r.filePath)
+	if err != nil {
+		return nil, Backup{}, err
+	}
+	r.backedUp = true
+
+	issues = diagnoseStore(r.store, true)
+	if err := r.write(); err != nil {
+		return nil, backup, err
+	}
+	return issues, backup, nil
+}
+
+// loadExternalChange reloads the store when the data file was changed by
+// another program, so that the file is checked as it is on disk.
+func (r *JsonRepository) loadExternalChange() error {
+	if err := r.checkExternalChange(); err != nil && !errors.Is(err, ErrExternalChange) {
+		return err
+	}
+	return nil
+}
+
+// diagnoseStore returns the inconsistencies of store, repairing them when
+// repair is true.
+func diagnoseStore(store *jsonStore, repair bool) []Issue {
+	var issues []Issue
+	report := func(monthKey, repairText, format string, args ...any) {
+		issues = append(issues, Issue{MonthKey: monthKey, Description: fmt.Sprintf(format, args...), Repair: repairText})
+	}
+
+	groupIDs := make([]string, 0, len(store.CategoryGroups))
+	maxOrder := 0
+	for id, group := range store.CategoryGroups {
+		groupIDs = append(groupIDs, id)
+		maxOrder = max(maxOrder, group.Order)
+	}
+	sort.Strings(groupIDs)
+
+	for _, id := range groupIDs {
+		group := store.CategoryGroups[id]
+		if group.GroupID != id {
+			report("", "use the ID it is stored under", "group %q is stored under ID %s but has ID %q", group.GroupName, id, group.GroupID)
+			if repair {
+				group.GroupID = id
+				store.CategoryGroups[id] = group
+			}
+		}
+	}
+
+	monthKeys := make([]string, 0, len(store.MonthlyData))
+	for monthKey := range store.MonthlyData {
+		monthKeys = append(monthKeys, monthKey)
+	}
+	sort.Strings(monthKeys)
+
+	for _, monthKey := range monthKeys {
+		record := store.MonthlyData[monthKey]
+		if _, err := domain.ParseMonth(monthKey); err != nil {
+			report(monthKey, "", "month key %q is not in YYYY-MM format", monthKey)
+		}
+
+		if record.Incomes == nil {
+			report(monthKey, "store an empty list", "incomes are null")
+			if repair {
+				record.Incomes = []domain.IncomeRecord{}
+			}
+		}
+		if record.Categories == nil {
+			report(monthKey, "store an empty list", "categories are null")
+			if repair {
+				record.Categories = []domain.Category{}
+			}
+		}
+
+		incomeIDs := make(map[string]bool, len(record.Incomes))
+		for i, income := range record.Incomes {
+			if income.IncomeID == "" || incomeIDs[income.IncomeID] {
+				report(monthKey, "give it a new ID", "income %q has %s", income.Description, describeID(income.IncomeID))
+				if repair {
+					record.Incomes[i].IncomeID = uuid.NewString()
+				}
+			}
+			incomeIDs[record.Incomes[i].IncomeID] = true
+		}
+
+		categoryIDs := make(map[string]bool, len(record.Categories))
+		for i, category := range record.Categories {
+			if category.CatID == "" || categoryIDs[category.CatID] {
+				report(monthKey, "give it a new ID", "category %q has %s", category.CategoryName, describeID(category.CatID))
+				if repair {
+					record.Categories[i].CatID = uuid.NewString()
+				}
+			}
+			categoryIDs[record.Categories[i].CatID] = true
+		}
+
+		for i := range record.Categories {
+			category := &record.Categories[i]
+			if _, exists := store.CategoryGroups[category.GroupID]; !exists {
+				report(monthKey, fmt.Sprintf("recreate the group as %q", recoveredGroupName),
+					"category %q references group %q, which does not exist", category.CategoryName, category.GroupID)
+				if repair {
+					if category.GroupID == "" {
+						category.GroupID = uuid.NewString()
+					}
+					maxOrder++
+					store.CategoryGroups[category.GroupID] = domain.CategoryGroup{
+						GroupID:   category.GroupID,
+						Order:     maxOrder,
+						GroupName: recoveredGroupName,
+					}
+				}
+			}
+
+			// The expense of a category is stored under its own ID
+			keys := make([]string, 0, len(category.Expense))
+			for key := range category.Expense {
+				if key != category.CatID {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			_, hasOwn := category.Expense[category.CatID]
+			for _, key := range keys {
+				if !hasOwn {
+					hasOwn = true
+					report(monthKey, "move it to the ID of the category", "expense of category %q is stored under ID %q", category.CategoryName, key)
+					if repair {
+						category.Expense[category.CatID] = category.Expense[key]
+						delete(category.Expense, key)
+					}
+					continue
+				}
+				report(monthKey, "remove it", "category %q has a second expense stored under ID %q", category.CategoryName, key)
+				if repair {
+					delete(category.Expense, key)
+				}
+			}
+		}
+
+		if repair {
+			store.MonthlyData[monthKey] = record
+		}
+	}
+
+	templateIDs := make(map[string]bool, len(store.Templates))
+	for i, template := range store.Templates {
+		if template.TemplateID == "" || templateIDs[template.TemplateID] {
+			report("", "give it a new ID", "recurring template %q has %s", template.Name, describeID(template.TemplateID))
+			if repair {
+				store.Templates[i].TemplateID = uuid.NewString()
+			}
+		}
+		templateIDs[store.Templates[i].TemplateID] = true
+	}
+
+	return issues
+}
+
+// describeID describes an ID that is empty or used by another record.
+func describeID(id string) string {
+	if id == "" {
+		return "no ID"
+	}
+	return fmt.Sprintf("the duplicate ID %q", id)
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonRepository_Doctor(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "test_data.json")
+	content := `{
+  "version": 3,
+  "CategoryGroups": {
+    "g1": {"groupId": "g1", "groupName": "Housing", "order": 1},
+    "g2": {"groupId": "old", "groupName": "Living", "order": 2}
+  },
+  "monthlyData": {
+    "2024-01": {
+      "incomes": null,
+      "categories": [
+        {"catId": "c1", "groupId": "g1", "categoryName": "Rent", "expense": {"c9": {"amount": "900", "budget": "900", "status": "Paid"}}},
+        {"catId": "c1", "groupId": "gone", "categoryName": "Food", "expense": {}}
+      ]
+    },
+    "2024-02": {
+      "incomes": [{"incomeId": "i1", "description": "Salary", "amount": "100"}, {"incomeId": "i1", "description": "Bonus", "amount": "50"}],
+      "categories": [
+        {"catId": "c2", "groupId": "g2", "categoryName": "Gym", "expense": {"c2": {"amount": "30"}, "c3": {"amount": "40"}}}
+      ]
+    }
+  },
+  "recurringTemplates": [{"templateId": "", "kind": "income", "name": "Salary", "amount": "100", "interval": 1, "startMonth": "2024-01"}]
+}`
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+
+	repo, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	repo.SetBackups(NewBackups(filepath.Join(tempDir, "backups"), DefaultBackupLimit))
+
+	issues, err := repo.Check()
+	require.NoError(t, err)
+	months := make([]string, len(issues))
+	for i, issue := range issues {
+		months[i] = issue.MonthKey
+	}
+	assert.Equal(t, []string{"", "2024-01", "2024-01", "2024-01", "2024-01", "2024-02", "2024-02", ""}, months)
+	assert.Contains(t, issues[0].Description, `group "Living" is stored under ID g2`)
+	assert.Equal(t, "incomes are null", issues[1].Description)
+	assert.Contains(t, issues[2].Description, `category "Food" has the duplicate ID "c1"`)
+	assert.Contains(t, issues[3].Description, `expense of category "Rent" is stored under ID "c9"`)
+	assert.Contains(t, issues[4].Description, `references group "gone"`)
+	assert.Contains(t, issues[5].Description, `income "Bonus" has the duplicate ID "i1"`)
+	assert.Contains(t, issues[6].Description, `second expense stored under ID "c3"`)
+	assert.Contains(t, issues[7].Description, `recurring template "Salary" has no ID`)
+
+	repaired, backup, err := repo.Repair()
+	require.NoError(t, err)
+	assert.Len(t, repaired, len(issues))
+	assert.FileExists(t, backup.Path)
+
+	reopened, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	issues, err = reopened.Check()
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	group, err := reopened.GetGroupByID("g2")
+	require.NoError(t, err)
+	assert.Equal(t, "g2", group.GroupID)
+	recovered, err := reopened.GetGroupByID("gone")
+	require.NoError(t, err)
+	assert.Equal(t, recoveredGroupName, recovered.GroupName)
+	assert.Equal(t, 3, recovered.Order)
+
+	categories, err := reopened.GetCategoriesForMonth("2024-01")
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.NotEqual(t, categories[0].CatID, categories[1].CatID)
+	assert.Equal(t, "900", categories[0].Expense["c1"].Amount.String())
+	incomes, err := reopened.GetIncomesForMonth("2024-01")
+	require.NoError(t, err)
+	assert.NotNil(t, incomes)
+
+	categories, err = reopened.GetCategoriesForMonth("2024-02")
+	require.NoError(t, err)
+	assert.Equal(t, "30", categories[0].Expense["c2"].Amount.String())
+	assert.Len(t, categories[0].Expense, 1)
+
+	repaired, _, err = reopened.Repair()
+	require.NoError(t, err)
+	assert.Empty(t, repaired)
+}
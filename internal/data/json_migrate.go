@@ -0,0 +1,284 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// ErrNewerDataFile is returned when a data file was written by a newer
+// version of gocost than the running one.
+var ErrNewerDataFile = errors.New("data file was written by a newer version of gocost")
+
+// jsonMigration upgrades a decoded data file by one version. Numbers are
+// decoded as json.Number so amounts are never rounded.
+type jsonMigration struct {
+	description string
+	migrate     func(doc map[string]any) error
+}
+
+// jsonMigrations holds the upgrade steps of the data file format, where
+// jsonMigrations[v] upgrades a file of version v to version v+1. The length
+// of the list must match jsonStoreVersion.
+var jsonMigrations = []jsonMigration{
+	{description: "store money amounts as exact decimal strings", migrate: migrateJsonAmounts},
+	{description: "use a single month key format", migrate: jsonMonthKeysMigration(legacyMonthKeyLayout)},
+	{description: "store month keys in the sortable YYYY-MM format", migrate: jsonMonthKeysMigration(domain.MonthLayout)},
+}
+
+// legacyMonthKeyLayout is the layout of the month keys written before
+// version 3, such as "January-2024".
+const legacyMonthKeyLayout = "January-2006"
+
+// jsonMonthKeyLayouts are the month key formats found in older data files.
+var jsonMonthKeyLayouts = []string{legacyMonthKeyLayout, domain.MonthLayout, "Jan-2006", "01-2006"}
+
+// jsonFileVersion returns the format version of a data file.
+func jsonFileVersion(fileData []byte) (int, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(fileData, &header); err != nil {
+		return 0, err
+	}
+	return header.Version, nil
+}
+
+// migrateJsonData upgrades the content of a data file from version to the
+// current version, one step at a time.
+func migrateJsonData(fileData []byte, version int) ([]byte, error) {
+	if version > jsonStoreVersion {
+		return nil, fmt.Errorf("%w: file version %d, supported up to %d", ErrNewerDataFile, version, jsonStoreVersion)
+	}
+	if version == jsonStoreVersion {
+		return fileData, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(fileData))
+	decoder.UseNumber()
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	for v := version; v < jsonStoreVersion; v++ {
+		step := jsonMigrations[v]
+		if err := step.migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate data file to version %d (%s): %w", v+1, step.description, err)
+		}
+		doc["version"] = v + 1
+	}
+	return json.Marshal(doc)
+}
+
+// preMigrationBackupPath returns where the content of a data file of the
+// given version is kept before it is migrated.
+func preMigrationBackupPath(filePath string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", filePath, version)
+}
+
+// keepPreMigrationCopy copies the data file before it is first rewritten in
+// a newer format. An existing copy is never replaced.
+func keepPreMigrationCopy(filePath string, version int) error {
+	backupPath := preMigrationBackupPath(filePath, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	}
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s for backup: %w", filePath, err)
+	}
+	if err := writeFileAtomic(backupPath, content, dataFileMode); err != nil {
+		return fmt.Errorf("failed to write pre-migration backup: %w", err)
+	}
+	return nil
+}
+
+// migrateJsonAmounts converts money amounts stored as JSON numbers into
+// decimal strings. Files without a version stored them as floating point numbers.
+func migrateJsonAmounts(doc map[string]any) error {
+	for _, month := range jsonObjects(doc["monthlyData"]) {
+		for _, income := range jsonList(month["incomes"]) {
+			numberToString(income, "amount")
+		}
+		for _, category := range jsonList(month["categories"]) {
+			for _, expense := range jsonObjects(category["expense"]) {
+				numberToString(expense, "budget")
+				numberToString(expense, "amount")
+				for _, entry := range jsonList(expense["entries"]) {
+					numberToString(entry, "amount")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonMonthKeysMigration returns a migration rewriting the month keys of
+// every known format in layout. Records of the same month stored under
+// different keys are merged, and keys that are not months are left as they are.
+func jsonMonthKeysMigration(layout string) func(doc map[string]any) error {
+	return func(doc map[string]any) error {
+		return migrateJsonMonthKeys(doc, layout)
+	}
+}
+
+// migrateJsonMonthKeys rewrites the month keys of doc in layout.
+func migrateJsonMonthKeys(doc map[string]any, layout string) error {
+	months, ok := doc["monthlyData"].(map[string]any)
+	if ok {
+		// Keys already in the current format are merged into first
+		keys := make([]string, 0, len(months))
+		for key := range months {
+			keys = append(keys, key)
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			iCurrent, jCurrent := isMonthKey(keys[i], layout), isMonthKey(keys[j], layout)
+			if iCurrent != jCurrent {
+				return iCurrent
+			}
+			return keys[i] < keys[j]
+		})
+
+		migrated := make(map[string]any, len(months))
+		for _, key := range keys {
+			record := months[key]
+			monthKey := normalizeMonthKey(key, layout)
+			if existing, ok := migrated[monthKey].(map[string]any); ok {
+				record = mergeMonthRecords(existing, record)
+			}
+			migrated[monthKey] = record
+		}
+		doc["monthlyData"] = migrated
+	}
+
+	for _, template := range jsonList(doc["recurringTemplates"]) {
+		for _, field := range []string{"startMonth", "endMonth"} {
+			key, ok := template[field].(string)
+			if !ok || key == "" {
+				continue
+			}
+			template[field] = normalizeMonthKey(key, layout)
+		}
+		if overrides, ok := template["overrides"].(map[string]any); ok {
+			migrated := make(map[string]any, len(overrides))
+			for key, override := range overrides {
+				migrated[normalizeMonthKey(key, layout)] = override
+			}
+			template["overrides"] = migrated
+		}
+	}
+	return nil
+}
+
+// normalizeMonthKey converts a month key in any known format to layout.
+// Unknown keys are returned unchanged.
+func normalizeMonthKey(key string, layout string) string {
+	for _, known := range jsonMonthKeyLayouts {
+		if t, err := time.Parse(known, strings.TrimSpace(key)); err == nil {
+			return t.Format(layout)
+		}
+	}
+	return key
+}
+
+// isMonthKey reports whether key is a month in layout.
+func isMonthKey(key string, layout string) bool {
+	_, err := time.Parse(layout, key)
+	return err == nil
+}
+
+// mergeMonthRecords appends the categories and incomes of other to record,
+// adds the rates of other for currencies record has no rate for and adds the
+// applied templates of other missing from record. Other fields keep the
+// values of record.
+func mergeMonthRecords(record map[string]any, other any) map[string]any {
+	otherRecord, ok := other.(map[string]any)
+	if !ok {
+		return record
+	}
+	for _, field := range []string{"categories", "incomes"} {
+		items, _ := record[field].([]any)
+		otherItems, _ := otherRecord[field].([]any)
+		if len(otherItems) > 0 {
+			record[field] = append(items, otherItems...)
+		}
+	}
+
+	rates, _ := record["rates"].([]any)
+	currencies := make(map[string]bool)
+	for _, rate := range jsonList(rates) {
+		currency, _ := rate["currency"].(string)
+		currencies[strings.ToUpper(currency)] = true
+	}
+	for _, rate := range jsonList(otherRecord["rates"]) {
+		currency, _ := rate["currency"].(string)
+		if !currencies[strings.ToUpper(currency)] {
+			currencies[strings.ToUpper(currency)] = true
+			rates = append(rates, rate)
+		}
+	}
+	if len(rates) > 0 {
+		record["rates"] = rates
+	}
+
+	templates, _ := record["appliedTemplates"].([]any)
+	otherTemplates, _ := otherRecord["appliedTemplates"].([]any)
+	for _, template := range otherTemplates {
+		if !slices.Contains(templates, template) {
+			templates = append(templates, template)
+		}
+	}
+	if len(templates) > 0 {
+		record["appliedTemplates"] = templates
+	}
+
+	for field, value := range otherRecord {
+		if _, ok := record[field]; !ok {
+			record[field] = value
+		}
+	}
+	return record
+}
+
+// jsonObjects returns the object values of a decoded JSON object.
+func jsonObjects(value any) []map[string]any {
+	object, _ := value.(map[string]any)
+	var objects []map[string]any
+	for _, item := range object {
+		if itemObject, ok := item.(map[string]any); ok {
+			objects = append(objects, itemObject)
+		}
+	}
+	return objects
+}
+
+// jsonList returns the objects of a decoded JSON array.
+func jsonList(value any) []map[string]any {
+	list, _ := value.([]any)
+	var objects []map[string]any
+	for _, item := range list {
+		if itemObject, ok := item.(map[string]any); ok {
+			objects = append(objects, itemObject)
+		}
+	}
+	return objects
+}
+
+// numberToString replaces the number at key in object with its exact text.
+func numberToString(object map[string]any, key string) {
+	if number, ok := object[key].(json.Number); ok {
+		object[key] = number.String()
+	}
+}
@@ -4,23 +4,32 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 
 	"github.com/madalinpopa/gocost/internal/domain"
 )
 
+// jsonStoreVersion is the version of the data file format written by this
+// version. Older files are upgraded with jsonMigrations when loaded.
+const jsonStoreVersion = 3
+
 // jsonStore represents the root data structure, specific to the JSON file.
 // It is an unexported implementation detail of the JsonRepository.
 type jsonStore struct {
+	Version         int                             `json:"version"`
 	DefaultCurrency string                          `json:"defaultCurrency"`
 	CategoryGroups  map[string]domain.CategoryGroup `json:"CategoryGroups"`
 	MonthlyData     map[string]domain.MonthlyRecord `json:"monthlyData"`
+	Templates       []domain.RecurringTemplate      `json:"recurringTemplates,omitempty"`
 }
 
 // newJsonStore creates a new instance of jsonStore.
 func newJsonStore() *jsonStore {
 	return &jsonStore{
+		Version:        jsonStoreVersion,
 		CategoryGroups: make(map[string]domain.CategoryGroup, 0),
 		MonthlyData:    make(map[string]domain.MonthlyRecord, 0),
 	}
@@ -31,12 +40,33 @@ func newJsonStore() *jsonStore {
 type JsonRepository struct {
 	filePath string
 	store    *jsonStore
+	state    fileState   // State of the file when it was last loaded or saved
+	reloaded bool        // Whether the store was reloaded since the last Reload
+	cipher   *fileCipher // Encrypts the file, nil when it is stored in plain text
+	journal  *Journal    // Journal encrypted together with the file, see SetJournal
+
+	backups  *Backups
+	backedUp bool // Whether the file has been backed up during this session
+
+	units   int  // Number of units of work running, see Atomically
+	pending bool // Whether the running unit of work changed the store
 }
 
 // NewJsonRepository creates and initializes a new JsonRepository.
-// It loads data from the specified file path.
+// It loads data from the specified file path. Encrypted files are opened
+// with NewEncryptedJsonRepository.
 func NewJsonRepository(filePath string, defaultCurrency string) (*JsonRepository, error) {
-	store, err := loadData(filePath, defaultCurrency)
+	return newJsonRepository(filePath, defaultCurrency, nil)
+}
+
+// newJsonRepository creates a JsonRepository reading and writing the data
+// file with fileCipher, or in plain text when it is nil.
+func newJsonRepository(filePath string, defaultCurrency string, fileCipher *fileCipher) (*JsonRepository, error) {
+	state, err := readFileState(filePath)
+	if err != nil {
+		return nil, err
+	}
+	store, err := loadData(filePath, defaultCurrency, fileCipher)
 	if err != nil {
 		return nil, err
 	}
@@ -44,12 +74,122 @@ func NewJsonRepository(filePath string, defaultCurrency string) (*JsonRepository
 	return &JsonRepository{
 		filePath: filePath,
 		store:    store,
+		state:    state,
+		cipher:   fileCipher,
 	}, nil
 }
 
+// SetBackups enables backups of the data file. The file is backed up once per
+// session, right before the first change is written.
+func (r *JsonRepository) SetBackups(backups *Backups) {
+	r.backups = backups
+}
+
+// Migrate rewrites a data file written by an older version in the current
+// format. The original file is kept next to it, see preMigrationBackupPath.
+// It reports whether the file was migrated.
+func (r *JsonRepository) Migrate() (bool, error) {
+	if r.store.Version >= jsonStoreVersion {
+		return false, nil
+	}
+	if err := r.save(); err != nil {
+		return false, fmt.Errorf("failed to migrate data file: %w", err)
+	}
+	return true, nil
+}
+
+// Atomically runs fn as a unit of work, see domain.UnitOfWork. The changes
+// of the unit are written to the file at once when it ends.
+func (r *JsonRepository) Atomically(fn func() error) error {
+	snapshot := r.store.clone()
+	r.units++
+	err := fn()
+	r.units--
+	if err != nil {
+		r.store = snapshot
+		return err
+	}
+	if r.units > 0 || !r.pending {
+		return nil
+	}
+
+	r.pending = false
+	if err := r.save(); err != nil {
+		// A store reloaded after an external change is kept
+		if !errors.Is(err, ErrExternalChange) {
+			r.store = snapshot
+		}
+		return err
+	}
+	return nil
+}
+
 // save is a helper to persist the current state of r.store to the JSON file.
+// Changes made to the file by another program are never overwritten, see
+// ErrExternalChange. Within a unit of work, the store is only written when
+// the unit ends.
 func (r *JsonRepository) save() error {
-	return saveData(r.filePath, r.store)
+	if r.units > 0 {
+		r.pending = true
+		return nil
+	}
+	if err := r.checkExternalChange(); err != nil {
+		return err
+	}
+	return r.write()
+}
+
+// checkExternalChange reloads the store and returns ErrExternalChange when
+// the data file was changed by another program.
+func (r *JsonRepository) checkExternalChange() error {
+	changed, err := r.Changed()
+	if err != nil {
+		return fmt.Errorf("failed to check data file: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.reload(); err != nil {
+		return err
+	}
+	return ErrExternalChange
+}
+
+// write writes r.store to the data file, backing the file up first.
+func (r *JsonRepository) write() error {
+	if r.backups != nil && !r.backedUp {
+		if _, err := r.backups.Create(r.filePath); err != nil {
+			return err
+		}
+		r.backedUp = true
+	}
+	if r.store.Version < jsonStoreVersion {
+		if err := keepPreMigrationCopy(r.filePath, r.store.Version); err != nil {
+			return err
+		}
+	}
+	r.store.Version = jsonStoreVersion
+	if err := saveData(r.filePath, r.store, r.cipher); err != nil {
+		return err
+	}
+	state, err := readFileState(r.filePath)
+	if err != nil {
+		return err
+	}
+	r.state = state
+	return nil
+}
+
+// clone returns a copy of the store sharing no records with it.
+func (s *jsonStore) clone() *jsonStore {
+	clone := *s
+	clone.CategoryGroups = maps.Clone(s.CategoryGroups)
+	clone.MonthlyData = make(map[string]domain.MonthlyRecord, len(s.MonthlyData))
+	for monthKey, record := range s.MonthlyData {
+		clone.MonthlyData[monthKey] = cloneMonthlyRecord(record)
+	}
+	clone.Templates = cloneTemplates(s.Templates)
+	return &clone
 }
 
 // FilePath returns the path to the JSON file store.
@@ -71,14 +211,14 @@ func (r *JsonRepository) GetAllGroups() ([]domain.CategoryGroup, error) {
 func (r *JsonRepository) GetGroupByID(groupID string) (domain.CategoryGroup, error) {
 	group, ok := r.store.CategoryGroups[groupID]
 	if !ok {
-		return domain.CategoryGroup{}, errors.New("group not found")
+		return domain.CategoryGroup{}, &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
 	}
 	return group, nil
 }
 
 func (r *JsonRepository) AddGroup(group domain.CategoryGroup) error {
 	if _, exists := r.store.CategoryGroups[group.GroupID]; exists {
-		return errors.New("group with this ID already exists")
+		return &domain.AlreadyExistsError{Record: domain.RecordGroup, ID: group.GroupID}
 	}
 	r.store.CategoryGroups[group.GroupID] = group
 	return r.save()
@@ -86,7 +226,7 @@ func (r *JsonRepository) AddGroup(group domain.CategoryGroup) error {
 
 func (r *JsonRepository) UpdateGroup(group domain.CategoryGroup) error {
 	if _, exists := r.store.CategoryGroups[group.GroupID]; !exists {
-		return errors.New("group not found")
+		return &domain.NotFoundError{Record: domain.RecordGroup, ID: group.GroupID}
 	}
 	r.store.CategoryGroups[group.GroupID] = group
 	return r.save()
@@ -97,12 +237,12 @@ func (r *JsonRepository) DeleteGroup(groupID string) error {
 		for _, category := range monthRecord.Categories {
 			if category.GroupID == groupID {
 				group, _ := r.GetGroupByID(groupID)
-				return fmt.Errorf("cannot delete group '%s': group is still being used by existing categories", group.GroupName)
+				return &domain.InUseError{Record: domain.RecordGroup, ID: groupID, Name: group.GroupName, UsedBy: domain.RecordCategory}
 			}
 		}
 	}
 	if _, exists := r.store.CategoryGroups[groupID]; !exists {
-		return errors.New("group not found")
+		return &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
 	}
 	delete(r.store.CategoryGroups, groupID)
 	return r.save()
@@ -125,7 +265,7 @@ func (r *JsonRepository) AddIncome(monthKey string, income domain.IncomeRecord)
 	}
 	for _, existingIncome := range monthRecord.Incomes {
 		if existingIncome.IncomeID == income.IncomeID {
-			return fmt.Errorf("income record with ID %s already exists", income.IncomeID)
+			return &domain.AlreadyExistsError{Record: domain.RecordIncome, ID: income.IncomeID}
 		}
 	}
 	monthRecord.Incomes = append(monthRecord.Incomes, income)
@@ -136,7 +276,7 @@ func (r *JsonRepository) AddIncome(monthKey string, income domain.IncomeRecord)
 func (r *JsonRepository) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
 	monthRecord, ok := r.store.MonthlyData[monthKey]
 	if !ok {
-		return fmt.Errorf("no data found for month %s", monthKey)
+		return &domain.NotFoundError{MonthKey: monthKey}
 	}
 	found := false
 	for i, existingIncome := range monthRecord.Incomes {
@@ -147,7 +287,7 @@ func (r *JsonRepository) UpdateIncome(monthKey string, income domain.IncomeRecor
 		}
 	}
 	if !found {
-		return fmt.Errorf("income record with ID %s not found for update", income.IncomeID)
+		return &domain.NotFoundError{Record: domain.RecordIncome, ID: income.IncomeID, MonthKey: monthKey}
 	}
 	r.store.MonthlyData[monthKey] = monthRecord
 	return r.save()
@@ -156,7 +296,7 @@ func (r *JsonRepository) UpdateIncome(monthKey string, income domain.IncomeRecor
 func (r *JsonRepository) DeleteIncome(monthKey string, incomeID string) error {
 	monthRecord, ok := r.store.MonthlyData[monthKey]
 	if !ok {
-		return fmt.Errorf("no data found for month %s", monthKey)
+		return &domain.NotFoundError{MonthKey: monthKey}
 	}
 	found := false
 	var updatedIncomes []domain.IncomeRecord
@@ -168,16 +308,58 @@ func (r *JsonRepository) DeleteIncome(monthKey string, incomeID string) error {
 		}
 	}
 	if !found {
-		return fmt.Errorf("income record with ID %s not found for deletion", incomeID)
+		return &domain.NotFoundError{Record: domain.RecordIncome, ID: incomeID, MonthKey: monthKey}
 	}
 	monthRecord.Incomes = updatedIncomes
 	r.store.MonthlyData[monthKey] = monthRecord
 	return r.save()
 }
 
+func (r *JsonRepository) GetRatesForMonth(monthKey string) ([]domain.ExchangeRate, error) {
+	if record, ok := r.store.MonthlyData[monthKey]; ok && record.Rates != nil {
+		return record.Rates, nil
+	}
+	return []domain.ExchangeRate{}, nil
+}
+
+func (r *JsonRepository) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	monthRecord, ok := r.store.MonthlyData[monthKey]
+	if !ok {
+		monthRecord = domain.MonthlyRecord{
+			Incomes:    make([]domain.IncomeRecord, 0),
+			Categories: make([]domain.Category, 0),
+		}
+	}
+	for i, existingRate := range monthRecord.Rates {
+		if existingRate.Currency == rate.Currency {
+			monthRecord.Rates[i] = rate
+			r.store.MonthlyData[monthKey] = monthRecord
+			return r.save()
+		}
+	}
+	monthRecord.Rates = append(monthRecord.Rates, rate)
+	r.store.MonthlyData[monthKey] = monthRecord
+	return r.save()
+}
+
+func (r *JsonRepository) DeleteRate(monthKey string, currency string) error {
+	monthRecord, ok := r.store.MonthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	for i, rate := range monthRecord.Rates {
+		if rate.Currency == currency {
+			monthRecord.Rates = append(monthRecord.Rates[:i], monthRecord.Rates[i+1:]...)
+			r.store.MonthlyData[monthKey] = monthRecord
+			return r.save()
+		}
+	}
+	return &domain.NotFoundError{Record: domain.RecordRate, ID: currency, MonthKey: monthKey}
+}
+
 func (r *JsonRepository) GetCategoriesForMonth(monthKey string) ([]domain.Category, error) {
 	if record, ok := r.store.MonthlyData[monthKey]; ok {
-		return record.Categories, nil
+		return cloneCategories(record.Categories), nil
 	}
 	return []domain.Category{}, nil
 }
@@ -190,7 +372,7 @@ func (r *JsonRepository) AddCategory(monthKey string, category domain.Category)
 			Categories: make([]domain.Category, 0),
 		}
 	}
-	monthRecord.Categories = append(monthRecord.Categories, category)
+	monthRecord.Categories = append(monthRecord.Categories, category.Clone())
 	r.store.MonthlyData[monthKey] = monthRecord
 	return r.save()
 }
@@ -198,18 +380,18 @@ func (r *JsonRepository) AddCategory(monthKey string, category domain.Category)
 func (r *JsonRepository) UpdateCategory(monthKey string, category domain.Category) error {
 	monthRecord, ok := r.store.MonthlyData[monthKey]
 	if !ok {
-		return fmt.Errorf("no data found for month %s", monthKey)
+		return &domain.NotFoundError{MonthKey: monthKey}
 	}
 	found := false
 	for i, existingCategory := range monthRecord.Categories {
 		if existingCategory.CatID == category.CatID {
-			monthRecord.Categories[i] = category
+			monthRecord.Categories[i] = category.Clone()
 			found = true
 			break
 		}
 	}
 	if !found {
-		return fmt.Errorf("category with ID %s not found for update", category.CatID)
+		return &domain.NotFoundError{Record: domain.RecordCategory, ID: category.CatID, MonthKey: monthKey}
 	}
 	r.store.MonthlyData[monthKey] = monthRecord
 	return r.save()
@@ -218,7 +400,7 @@ func (r *JsonRepository) UpdateCategory(monthKey string, category domain.Categor
 func (r *JsonRepository) DeleteCategory(monthKey string, categoryID string) error {
 	monthRecord, ok := r.store.MonthlyData[monthKey]
 	if !ok {
-		return fmt.Errorf("no data found for month %s", monthKey)
+		return &domain.NotFoundError{MonthKey: monthKey}
 	}
 	found := false
 	var updatedCategories []domain.Category
@@ -230,7 +412,7 @@ func (r *JsonRepository) DeleteCategory(monthKey string, categoryID string) erro
 		}
 	}
 	if !found {
-		return fmt.Errorf("category with ID %s not found for deletion", categoryID)
+		return &domain.NotFoundError{Record: domain.RecordCategory, ID: categoryID, MonthKey: monthKey}
 	}
 	monthRecord.Categories = updatedCategories
 	r.store.MonthlyData[monthKey] = monthRecord
@@ -238,36 +420,148 @@ func (r *JsonRepository) DeleteCategory(monthKey string, categoryID string) erro
 }
 
 func (r *JsonRepository) CopyCategoriesFromMonth(fromMonthKey, toMonthKey string) (int, error) {
-	prevRecord, exists := r.store.MonthlyData[fromMonthKey]
-	if !exists || len(prevRecord.Categories) == 0 {
-		return 0, fmt.Errorf("no categories found in %s to copy from", fromMonthKey)
-	}
-	var newCategories []domain.Category
-	for _, category := range prevRecord.Categories {
-		newCategory := domain.Category{
-			CatID:        category.CatID,
-			GroupID:      category.GroupID,
-			CategoryName: category.CategoryName,
-			Expense:      make(map[string]domain.ExpenseRecord),
+	return copyCategories(r, fromMonthKey, toMonthKey)
+}
+
+func (r *JsonRepository) GetAllTemplates() ([]domain.RecurringTemplate, error) {
+	templates := make([]domain.RecurringTemplate, len(r.store.Templates))
+	copy(templates, r.store.Templates)
+	return templates, nil
+}
+
+func (r *JsonRepository) AddTemplate(template domain.RecurringTemplate) error {
+	for _, existing := range r.store.Templates {
+		if existing.TemplateID == template.TemplateID {
+			return &domain.AlreadyExistsError{Record: domain.RecordTemplate, ID: template.TemplateID}
+		}
+	}
+	r.store.Templates = append(r.store.Templates, template)
+	return r.save()
+}
+
+func (r *JsonRepository) UpdateTemplate(template domain.RecurringTemplate) error {
+	for i, existing := range r.store.Templates {
+		if existing.TemplateID == template.TemplateID {
+			r.store.Templates[i] = template
+			return r.save()
 		}
-		newCategories = append(newCategories, newCategory)
 	}
-	currentRecord := domain.MonthlyRecord{
-		Incomes:    []domain.IncomeRecord{},
-		Categories: newCategories,
+	return &domain.NotFoundError{Record: domain.RecordTemplate, ID: template.TemplateID}
+}
+
+func (r *JsonRepository) DeleteTemplate(templateID string) error {
+	for i, existing := range r.store.Templates {
+		if existing.TemplateID == templateID {
+			r.store.Templates = append(r.store.Templates[:i], r.store.Templates[i+1:]...)
+			return r.save()
+		}
 	}
-	if existingRecord, exists := r.store.MonthlyData[toMonthKey]; exists {
-		currentRecord.Incomes = existingRecord.Incomes
+	return &domain.NotFoundError{Record: domain.RecordTemplate, ID: templateID}
+}
+
+func (r *JsonRepository) GetAppliedTemplates(monthKey string) ([]string, error) {
+	if record, ok := r.store.MonthlyData[monthKey]; ok && record.AppliedTemplates != nil {
+		return record.AppliedTemplates, nil
+	}
+	return []string{}, nil
+}
+
+func (r *JsonRepository) MarkTemplateApplied(monthKey string, templateID string) error {
+	monthRecord, ok := r.store.MonthlyData[monthKey]
+	if !ok {
+		monthRecord = domain.MonthlyRecord{
+			Incomes:    make([]domain.IncomeRecord, 0),
+			Categories: make([]domain.Category, 0),
+		}
+	}
+	for _, applied := range monthRecord.AppliedTemplates {
+		if applied == templateID {
+			return nil
+		}
+	}
+	monthRecord.AppliedTemplates = append(monthRecord.AppliedTemplates, templateID)
+	r.store.MonthlyData[monthKey] = monthRecord
+	return r.save()
+}
+
+func (r *JsonRepository) GetMonths() ([]domain.Month, error) {
+	var months []domain.Month
+	for monthKey, record := range r.store.MonthlyData {
+		month, err := domain.ParseMonth(monthKey)
+		if err != nil || !hasMonthData(record) {
+			continue
+		}
+		months = append(months, month)
 	}
-	r.store.MonthlyData[toMonthKey] = currentRecord
-	err := r.save()
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Before(months[j])
+	})
+	return months, nil
+}
+
+func (r *JsonRepository) GetMonthRange(from, to domain.Month) ([]domain.MonthData, error) {
+	months, err := r.GetMonths()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	var result []domain.MonthData
+	for _, month := range months {
+		if month.Before(from) || month.After(to) {
+			continue
+		}
+		record := r.store.MonthlyData[month.String()]
+		result = append(result, domain.MonthData{
+			Month: month,
+			MonthlyRecord: domain.MonthlyRecord{
+				Incomes:          append([]domain.IncomeRecord{}, record.Incomes...),
+				Categories:       cloneCategories(record.Categories),
+				Rates:            append([]domain.ExchangeRate{}, record.Rates...),
+				AppliedTemplates: append([]string{}, record.AppliedTemplates...),
+			},
+		})
+	}
+	return result, nil
+}
+
+// cloneCategories returns copies of categories, so callers changing their
+// expenses do not change the store.
+func cloneCategories(categories []domain.Category) []domain.Category {
+	clones := make([]domain.Category, len(categories))
+	for i, category := range categories {
+		clones[i] = category.Clone()
+	}
+	return clones
+}
+
+// cloneMonthlyRecord returns a copy of record sharing no records with it.
+// Missing lists stay missing.
+func cloneMonthlyRecord(record domain.MonthlyRecord) domain.MonthlyRecord {
+	clone := record
+	clone.Incomes = slices.Clone(record.Incomes)
+	if record.Categories != nil {
+		clone.Categories = cloneCategories(record.Categories)
+	}
+	clone.Rates = slices.Clone(record.Rates)
+	clone.AppliedTemplates = slices.Clone(record.AppliedTemplates)
+	return clone
+}
+
+// cloneTemplates returns copies of templates and of their overrides.
+func cloneTemplates(templates []domain.RecurringTemplate) []domain.RecurringTemplate {
+	clones := slices.Clone(templates)
+	for i := range clones {
+		clones[i].Overrides = maps.Clone(clones[i].Overrides)
 	}
-	return len(newCategories), nil
+	return clones
 }
 
-func loadData(filePath string, currency string) (*jsonStore, error) {
+// hasMonthData reports whether a month holds any incomes, categories or rates.
+func hasMonthData(record domain.MonthlyRecord) bool {
+	return len(record.Incomes) > 0 || len(record.Categories) > 0 || len(record.Rates) > 0
+}
+
+func loadData(filePath string, currency string, fileCipher *fileCipher) (*jsonStore, error) {
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -278,11 +572,31 @@ func loadData(filePath string, currency string) (*jsonStore, error) {
 	if len(fileData) == 0 {
 		return newJsonStore(), nil
 	}
+	if isEncrypted(fileData) {
+		if fileCipher == nil {
+			return nil, ErrPassphraseRequired
+		}
+		if fileData, err = fileCipher.open(fileData); err != nil {
+			return nil, err
+		}
+	}
+
+	version, err := jsonFileVersion(fileData)
+	if err != nil {
+		return nil, err
+	}
+	fileData, err = migrateJsonData(fileData, version)
+	if err != nil {
+		return nil, err
+	}
+
 	var store jsonStore
 	err = json.Unmarshal(fileData, &store)
 	if err != nil {
 		return nil, err
 	}
+	// The version of the file is kept until it is saved in the current format
+	store.Version = version
 	if store.CategoryGroups == nil {
 		store.CategoryGroups = make(map[string]domain.CategoryGroup, 0)
 	}
@@ -293,12 +607,17 @@ func loadData(filePath string, currency string) (*jsonStore, error) {
 	return &store, nil
 }
 
-func saveData(filePath string, store *jsonStore) error {
+func saveData(filePath string, store *jsonStore, fileCipher *fileCipher) error {
 	jsonData, err := json.MarshalIndent(store, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	if fileCipher != nil {
+		if jsonData, err = fileCipher.seal(jsonData); err != nil {
+			return fmt.Errorf("failed to encrypt data: %w", err)
+		}
+	}
+	if err := writeFileAtomic(filePath, jsonData, dataFileMode); err != nil {
 		return fmt.Errorf("failed to save data: %w", err)
 	}
 	return nil
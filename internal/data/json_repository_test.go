@@ -1,11 +1,14 @@
 package data
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,7 +61,7 @@ func TestJsonRepository_GroupOperations(t *testing.T) {
 	t.Run("Delete Group", func(t *testing.T) {
 		// Test delete failure when in use
 		cat := domain.Category{CatID: "c1", GroupID: "g2", CategoryName: "Test Cat"}
-		err := repo.AddCategory("May-2024", cat)
+		err := repo.AddCategory("2024-05", cat)
 		require.NoError(t, err)
 
 		err = repo.DeleteGroup("g2")
@@ -80,9 +83,9 @@ func TestJsonRepository_GroupOperations(t *testing.T) {
 
 func TestJsonRepository_IncomeOperations(t *testing.T) {
 	repo := setupTestRepo(t)
-	monthKey := "June-2024"
-	income1 := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: 5000}
-	income2 := domain.IncomeRecord{IncomeID: "i2", Description: "Freelance", Amount: 1000}
+	monthKey := "2024-06"
+	income1 := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5000)}
+	income2 := domain.IncomeRecord{IncomeID: "i2", Description: "Freelance", Amount: decimal.NewFromInt(1000)}
 
 	t.Run("Add and Get Income", func(t *testing.T) {
 		err := repo.AddIncome(monthKey, income1)
@@ -96,13 +99,13 @@ func TestJsonRepository_IncomeOperations(t *testing.T) {
 	})
 
 	t.Run("Update Income", func(t *testing.T) {
-		updatedIncome := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: 5500}
+		updatedIncome := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5500)}
 		err := repo.UpdateIncome(monthKey, updatedIncome)
 		require.NoError(t, err)
 
 		incomes, err := repo.GetIncomesForMonth(monthKey)
 		require.NoError(t, err)
-		assert.Equal(t, 5500.0, incomes[0].Amount)
+		assert.Equal(t, "5500", incomes[0].Amount.String())
 	})
 
 	t.Run("Delete Income", func(t *testing.T) {
@@ -118,7 +121,7 @@ func TestJsonRepository_IncomeOperations(t *testing.T) {
 
 func TestJsonRepository_CategoryOperations(t *testing.T) {
 	repo := setupTestRepo(t)
-	monthKey := "July-2024"
+	monthKey := "2024-07"
 	cat1 := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}
 
 	t.Run("Add and Get Category", func(t *testing.T) {
@@ -153,9 +156,9 @@ func TestJsonRepository_CategoryOperations(t *testing.T) {
 
 func TestJsonRepository_CopyFromMonth(t *testing.T) {
 	repo := setupTestRepo(t)
-	fromMonth := "August-2024"
-	toMonth := "September-2024"
-	cat1 := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Utilities", Expense: map[string]domain.ExpenseRecord{"c1": {Amount: 100}}}
+	fromMonth := "2024-08"
+	toMonth := "2024-09"
+	cat1 := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Utilities", Expense: map[string]domain.ExpenseRecord{"c1": {Amount: decimal.NewFromInt(100)}}}
 	cat2 := domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Groceries"}
 
 	err := repo.AddCategory(fromMonth, cat1)
@@ -199,3 +202,264 @@ func TestJsonRepository_Persistence(t *testing.T) {
 	require.NoError(t, err)
 	assert.Greater(t, fileInfo.Size(), int64(0))
 }
+
+func TestJsonRepository_MigrateLegacyAmounts(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "legacy_data.json")
+	legacy := `{
+  "defaultCurrency": "USD",
+  "CategoryGroups": {},
+  "monthlyData": {
+    "May-2024": {
+      "incomes": [{"incomeId": "i1", "description": "Salary", "amount": 0.1}],
+      "categories": [
+        {"catId": "c1", "groupId": "g1", "categoryName": "Rent", "expense": {"c1": {"budget": 0.3, "amount": 0.2, "status": "Paid", "notes": ""}}}
+      ]
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(filePath, []byte(legacy), 0644))
+
+	backups := NewBackups(filepath.Join(tempDir, "backups"), DefaultBackupLimit)
+	repo, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	repo.SetBackups(backups)
+
+	migrated, err := repo.Migrate()
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	// The legacy file is kept as a backup
+	list, err := backups.List(filePath)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	original, err := os.ReadFile(preMigrationBackupPath(filePath, 0))
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(original))
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf(`"version": %d`, jsonStoreVersion))
+	assert.Contains(t, string(content), `"amount": "0.1"`)
+
+	repo, err = NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	migrated, err = repo.Migrate()
+	require.NoError(t, err)
+	assert.False(t, migrated)
+
+	incomes, err := repo.GetIncomesForMonth("2024-05")
+	require.NoError(t, err)
+	cats, err := repo.GetCategoriesForMonth("2024-05")
+	require.NoError(t, err)
+	require.Len(t, cats, 1)
+
+	// Amounts add up exactly
+	total := incomes[0].Amount.Add(cats[0].Expense["c1"].Amount)
+	assert.True(t, total.Equal(cats[0].Expense["c1"].Budget))
+}
+
+func TestJsonRepository_MigrateMonthKeys(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test_data.json")
+	content := `{
+  "version": 1,
+  "CategoryGroups": {},
+  "monthlyData": {
+    "2024-01": {
+      "incomes": [{"incomeId": "i1", "description": "Salary", "amount": "100"}],
+      "categories": [{"catId": "c1", "groupId": "g1", "categoryName": "Rent"}],
+      "rates": [{"currency": "EUR", "rate": "1.1"}],
+      "appliedTemplates": ["t1"]
+    },
+    "January-2024": {
+      "incomes": [{"incomeId": "i2", "description": "Bonus", "amount": "50"}],
+      "categories": null,
+      "rates": [{"currency": "EUR", "rate": "1.2"}, {"currency": "GBP", "rate": "1.3"}],
+      "appliedTemplates": ["t1", "t2"]
+    },
+    "2024-02": {"incomes": null, "categories": []}
+  },
+  "recurringTemplates": [
+    {"templateId": "t1", "kind": "income", "name": "Salary", "amount": "100", "interval": 1, "startMonth": "January-2024", "overrides": {"February-2024": {"skip": true}}}
+  ]
+}`
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	repo, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+
+	incomes, err := repo.GetIncomesForMonth("2024-01")
+	require.NoError(t, err)
+	assert.Len(t, incomes, 2)
+	categories, err := repo.GetCategoriesForMonth("2024-01")
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+	// Rates and applied templates of both keys are kept, with one rate per currency
+	rates, err := repo.GetRatesForMonth("2024-01")
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.ElementsMatch(t, []string{"EUR", "GBP"}, []string{rates[0].Currency, rates[1].Currency})
+	applied, err := repo.GetAppliedTemplates("2024-01")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"t1", "t2"}, applied)
+
+	templates, err := repo.GetAllTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "2024-01", templates[0].StartMonth)
+	assert.True(t, templates[0].Overrides["2024-02"].Skip)
+
+	migrated, err := repo.Migrate()
+	require.NoError(t, err)
+	assert.True(t, migrated)
+	_, err = os.Stat(preMigrationBackupPath(filePath, 1))
+	assert.NoError(t, err)
+
+	saved, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), `"2024-01"`)
+	assert.NotContains(t, string(saved), `"January-2024"`)
+}
+
+func TestJsonRepository_RefusesNewerVersion(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test_data.json")
+	content := fmt.Sprintf(`{"version": %d, "CategoryGroups": {}, "monthlyData": {}}`, jsonStoreVersion+1)
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	_, err := NewJsonRepository(filePath, "USD")
+	assert.ErrorIs(t, err, ErrNewerDataFile)
+
+	// The file is left untouched
+	unchanged, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(unchanged))
+}
+
+func TestJsonMigrations_MatchVersion(t *testing.T) {
+	assert.Len(t, jsonMigrations, jsonStoreVersion)
+}
+
+func TestJsonRepository_MonthOperations(t *testing.T) {
+	repo := setupTestRepo(t)
+	require.NoError(t, repo.AddIncome("2024-11", domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(100)}))
+	require.NoError(t, repo.AddCategory("2025-01", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+	require.NoError(t, repo.SetRate("2024-03", domain.ExchangeRate{Currency: "EUR", Rate: decimal.NewFromInt(1)}))
+	require.NoError(t, repo.AddCategory("2024-12", domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Gifts"}))
+	require.NoError(t, repo.DeleteCategory("2024-12", "c2"))
+
+	months, err := repo.GetMonths()
+	require.NoError(t, err)
+	var keys []string
+	for _, month := range months {
+		keys = append(keys, month.String())
+	}
+	assert.Equal(t, []string{"2024-03", "2024-11", "2025-01"}, keys)
+
+	data, err := repo.GetMonthRange(domain.NewMonth(2024, time.April), domain.NewMonth(2025, time.January))
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, "2024-11", data[0].Month.String())
+	require.Len(t, data[0].Incomes, 1)
+	assert.Equal(t, "Salary", data[0].Incomes[0].Description)
+	require.Len(t, data[1].Categories, 1)
+	assert.Equal(t, "Rent", data[1].Categories[0].CategoryName)
+}
+
+func TestJsonRepository_RateOperations(t *testing.T) {
+	repo := setupTestRepo(t)
+	monthKey := "2024-06"
+
+	err := repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.08")})
+	require.NoError(t, err)
+	err = repo.SetRate(monthKey, domain.ExchangeRate{Currency: "GBP", Rate: decimal.RequireFromString("1.27")})
+	require.NoError(t, err)
+
+	// Setting an existing currency replaces its rate
+	err = repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.1")})
+	require.NoError(t, err)
+
+	rates, err := repo.GetRatesForMonth(monthKey)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "EUR", rates[0].Currency)
+	assert.Equal(t, "1.1", rates[0].Rate.String())
+
+	require.NoError(t, repo.DeleteRate(monthKey, "GBP"))
+	rates, err = repo.GetRatesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Len(t, rates, 1)
+
+	assert.Error(t, repo.DeleteRate(monthKey, "GBP"))
+}
+
+func TestJsonRepository_TemplateOperations(t *testing.T) {
+	repo := setupTestRepo(t)
+	amount := decimal.NewFromInt(1300)
+	template := domain.RecurringTemplate{
+		TemplateID: "t1",
+		Kind:       domain.TemplateExpense,
+		Name:       "Rent",
+		Amount:     decimal.NewFromInt(1200),
+		Budget:     decimal.NewFromInt(1200),
+		Interval:   1,
+		StartMonth: "2024-01",
+	}
+
+	require.NoError(t, repo.AddTemplate(template))
+	assert.Error(t, repo.AddTemplate(template))
+
+	template.EndMonth = "2024-12"
+	template.Overrides = map[string]domain.TemplateOverride{
+		"2024-03": {Amount: &amount},
+		"2024-04": {Skip: true},
+	}
+	require.NoError(t, repo.UpdateTemplate(template))
+
+	templates, err := repo.GetAllTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "2024-12", templates[0].EndMonth)
+	require.NotNil(t, templates[0].Overrides["2024-03"].Amount)
+	assert.Equal(t, "1300", templates[0].Overrides["2024-03"].Amount.String())
+	assert.Nil(t, templates[0].Overrides["2024-03"].Budget)
+	assert.True(t, templates[0].Overrides["2024-04"].Skip)
+
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	applied, err := repo.GetAppliedTemplates("2024-03")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, applied)
+
+	require.NoError(t, repo.DeleteTemplate("t1"))
+	assert.Error(t, repo.DeleteTemplate("t1"))
+	templates, err = repo.GetAllTemplates()
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestJsonRepository_AtomicallyWritesOnce(t *testing.T) {
+	repo := setupTestRepo(t)
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing", Order: 1}))
+	for _, id := range []string{"c1", "c2", "c3"} {
+		require.NoError(t, repo.AddCategory("2024-06", domain.Category{CatID: id, GroupID: "g1", CategoryName: id}))
+	}
+
+	stored := func() []domain.Category {
+		reopened, err := NewJsonRepository(repo.FilePath(), "USD")
+		require.NoError(t, err)
+		categories, err := reopened.GetCategoriesForMonth("2024-07")
+		require.NoError(t, err)
+		return categories
+	}
+
+	err := repo.Atomically(func() error {
+		count, err := repo.CopyCategoriesFromMonth("2024-06", "2024-07")
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.Empty(t, stored(), "the file is not written before the unit of work ends")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, stored(), 3)
+
+}
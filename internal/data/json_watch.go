@@ -0,0 +1,162 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ErrExternalChange is returned when the data file was changed by another
+// program since it was loaded. The change is not written and the repository
+// is reloaded, so it can be made again on top of the latest data.
+var ErrExternalChange = errors.New("data file was changed outside gocost, reloaded it without saving this change")
+
+// fileState identifies the content of the data file as last read or written.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	hash    []byte
+}
+
+// readFileState returns the state of the file at filePath.
+func readFileState(filePath string) (fileState, error) {
+	info, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fileState{}, nil
+	}
+	if err != nil {
+		return fileState{}, err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fileState{}, err
+	}
+	hash := sha256.Sum256(content)
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size(), hash: hash[:]}, nil
+}
+
+// changedSince reports whether the file at filePath differs from state. The
+// content is only hashed when the modification time or size changed.
+func changedSince(filePath string, state fileState) (bool, error) {
+	info, err := os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state.exists, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if state.exists && info.ModTime().Equal(state.modTime) && info.Size() == state.size {
+		return false, nil
+	}
+	current, err := readFileState(filePath)
+	if err != nil {
+		return false, err
+	}
+	return !current.exists || !bytes.Equal(current.hash, state.hash), nil
+}
+
+// Changed reports whether the data file was changed by another program since
+// it was last loaded or saved.
+func (r *JsonRepository) Changed() (bool, error) {
+	return changedSince(r.filePath, r.state)
+}
+
+// Reload loads the data file again when it was changed by another program.
+// It reports whether the store was reloaded since the last call, including
+// reloads done by a save that found the file changed.
+func (r *JsonRepository) Reload() (bool, error) {
+	changed, err := r.Changed()
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		if err := r.reload(); err != nil {
+			return false, err
+		}
+	}
+	reloaded := r.reloaded
+	r.reloaded = false
+	return reloaded, nil
+}
+
+// reload replaces the store with the content of the data file.
+func (r *JsonRepository) reload() error {
+	state, err := readFileState(r.filePath)
+	if err != nil {
+		return err
+	}
+	store, err := loadData(r.filePath, r.store.DefaultCurrency, r.cipher)
+	if err != nil {
+		return fmt.Errorf("failed to reload data file: %w", err)
+	}
+	r.store = store
+	r.state = state
+	r.reloaded = true
+	return nil
+}
+
+// FileWatcher notifies about changes of a single file. It watches the
+// directory of the file, so files replaced by a rename are still followed.
+type FileWatcher struct {
+	watcher *fsnotify.Watcher
+	changes chan struct{}
+}
+
+// NewFileWatcher starts watching the file at filePath.
+func NewFileWatcher(filePath string) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(filePath)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filePath, err)
+	}
+
+	w := &FileWatcher{watcher: watcher, changes: make(chan struct{}, 1)}
+	go w.run(filepath.Clean(filePath))
+	return w, nil
+}
+
+// run forwards the events of the watched file until the watcher is closed.
+// Events arriving before the previous one was received are coalesced.
+func (w *FileWatcher) run(filePath string) {
+	defer close(w.changes)
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filePath || event.Op == fsnotify.Chmod {
+				continue
+			}
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Changes returns the channel receiving a value whenever the file changes.
+// It is closed when the watcher is closed.
+func (w *FileWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching the file.
+func (w *FileWatcher) Close() error {
+	return w.watcher.Close()
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonRepository_ExternalChanges(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test_data.json")
+
+	first, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+	second, err := NewJsonRepository(filePath, "USD")
+	require.NoError(t, err)
+
+	require.NoError(t, first.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+
+	t.Run("Save refuses to overwrite", func(t *testing.T) {
+		err := second.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living"})
+		assert.ErrorIs(t, err, ErrExternalChange)
+
+		// The change made by the first repository is kept and loaded
+		groups, err := second.GetAllGroups()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "Housing", groups[0].GroupName)
+
+		// The reload done by the save is reported once
+		reloaded, err := second.Reload()
+		require.NoError(t, err)
+		assert.True(t, reloaded)
+		reloaded, err = second.Reload()
+		require.NoError(t, err)
+		assert.False(t, reloaded)
+
+		require.NoError(t, second.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living"}))
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		changed, err := first.Changed()
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		reloaded, err := first.Reload()
+		require.NoError(t, err)
+		assert.True(t, reloaded)
+
+		groups, err := first.GetAllGroups()
+		require.NoError(t, err)
+		assert.Len(t, groups, 2)
+
+		changed, err = first.Changed()
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("Touching the file is not a change", func(t *testing.T) {
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(filePath, later, later))
+
+		changed, err := first.Changed()
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "test_data.json")
+
+	watcher, err := NewFileWatcher(filePath)
+	require.NoError(t, err)
+
+	require.NoError(t, writeFileAtomic(filePath, []byte("{}"), 0644))
+
+	select {
+	case <-watcher.Changes():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change of the watched file")
+	}
+
+	// The channel is closed with the watcher
+	require.NoError(t, watcher.Close())
+	for range watcher.Changes() {
+	}
+}
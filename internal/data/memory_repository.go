@@ -0,0 +1,337 @@
+package data
+
+import (
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// MemoryRepository is a concrete implementation of the repository interfaces
+// that keeps its data in memory only. Its data is lost when the program exits,
+// which makes it suited to tests and to exploring the interface in demo mode.
+type MemoryRepository struct {
+	groups      map[string]domain.CategoryGroup
+	monthlyData map[string]domain.MonthlyRecord
+	templates   []domain.RecurringTemplate
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		groups:      make(map[string]domain.CategoryGroup),
+		monthlyData: make(map[string]domain.MonthlyRecord),
+	}
+}
+
+// FilePath returns an empty path, as no file backs the repository.
+func (r *MemoryRepository) FilePath() string {
+	return ""
+}
+
+// Atomically runs fn as a unit of work, see domain.UnitOfWork.
+func (r *MemoryRepository) Atomically(fn func() error) error {
+	groups := maps.Clone(r.groups)
+	monthlyData := make(map[string]domain.MonthlyRecord, len(r.monthlyData))
+	for monthKey, record := range r.monthlyData {
+		monthlyData[monthKey] = cloneMonthlyRecord(record)
+	}
+	templates := cloneTemplates(r.templates)
+
+	if err := fn(); err != nil {
+		r.groups, r.monthlyData, r.templates = groups, monthlyData, templates
+		return err
+	}
+	return nil
+}
+
+// month returns the record of monthKey, an empty one when the month holds no data.
+func (r *MemoryRepository) month(monthKey string) domain.MonthlyRecord {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		record = domain.MonthlyRecord{
+			Incomes:    make([]domain.IncomeRecord, 0),
+			Categories: make([]domain.Category, 0),
+		}
+	}
+	return record
+}
+
+func (r *MemoryRepository) GetAllGroups() ([]domain.CategoryGroup, error) {
+	var groups []domain.CategoryGroup
+	for _, group := range r.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Order < groups[j].Order
+	})
+	return groups, nil
+}
+
+func (r *MemoryRepository) GetGroupByID(groupID string) (domain.CategoryGroup, error) {
+	group, ok := r.groups[groupID]
+	if !ok {
+		return domain.CategoryGroup{}, &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
+	}
+	return group, nil
+}
+
+func (r *MemoryRepository) AddGroup(group domain.CategoryGroup) error {
+	if _, exists := r.groups[group.GroupID]; exists {
+		return &domain.AlreadyExistsError{Record: domain.RecordGroup, ID: group.GroupID}
+	}
+	r.groups[group.GroupID] = group
+	return nil
+}
+
+func (r *MemoryRepository) UpdateGroup(group domain.CategoryGroup) error {
+	if _, exists := r.groups[group.GroupID]; !exists {
+		return &domain.NotFoundError{Record: domain.RecordGroup, ID: group.GroupID}
+	}
+	r.groups[group.GroupID] = group
+	return nil
+}
+
+func (r *MemoryRepository) DeleteGroup(groupID string) error {
+	group, exists := r.groups[groupID]
+	if !exists {
+		return &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
+	}
+	for _, record := range r.monthlyData {
+		for _, category := range record.Categories {
+			if category.GroupID == groupID {
+				return &domain.InUseError{Record: domain.RecordGroup, ID: groupID, Name: group.GroupName, UsedBy: domain.RecordCategory}
+			}
+		}
+	}
+	delete(r.groups, groupID)
+	return nil
+}
+
+func (r *MemoryRepository) GetIncomesForMonth(monthKey string) ([]domain.IncomeRecord, error) {
+	return slices.Clone(r.month(monthKey).Incomes), nil
+}
+
+func (r *MemoryRepository) AddIncome(monthKey string, income domain.IncomeRecord) error {
+	record := r.month(monthKey)
+	for _, existing := range record.Incomes {
+		if existing.IncomeID == income.IncomeID {
+			return &domain.AlreadyExistsError{Record: domain.RecordIncome, ID: income.IncomeID}
+		}
+	}
+	record.Incomes = append(record.Incomes, income)
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	i := slices.IndexFunc(record.Incomes, func(existing domain.IncomeRecord) bool {
+		return existing.IncomeID == income.IncomeID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordIncome, ID: income.IncomeID, MonthKey: monthKey}
+	}
+	record.Incomes[i] = income
+	return nil
+}
+
+func (r *MemoryRepository) DeleteIncome(monthKey string, incomeID string) error {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	i := slices.IndexFunc(record.Incomes, func(existing domain.IncomeRecord) bool {
+		return existing.IncomeID == incomeID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordIncome, ID: incomeID, MonthKey: monthKey}
+	}
+	record.Incomes = slices.Delete(record.Incomes, i, i+1)
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) GetRatesForMonth(monthKey string) ([]domain.ExchangeRate, error) {
+	rates := slices.Clone(r.month(monthKey).Rates)
+	if rates == nil {
+		rates = []domain.ExchangeRate{}
+	}
+	return rates, nil
+}
+
+func (r *MemoryRepository) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	record := r.month(monthKey)
+	i := slices.IndexFunc(record.Rates, func(existing domain.ExchangeRate) bool {
+		return existing.Currency == rate.Currency
+	})
+	if i < 0 {
+		record.Rates = append(record.Rates, rate)
+	} else {
+		record.Rates[i] = rate
+	}
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) DeleteRate(monthKey string, currency string) error {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	i := slices.IndexFunc(record.Rates, func(existing domain.ExchangeRate) bool {
+		return existing.Currency == currency
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordRate, ID: currency, MonthKey: monthKey}
+	}
+	record.Rates = slices.Delete(record.Rates, i, i+1)
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) GetCategoriesForMonth(monthKey string) ([]domain.Category, error) {
+	return cloneCategories(r.month(monthKey).Categories), nil
+}
+
+func (r *MemoryRepository) AddCategory(monthKey string, category domain.Category) error {
+	record := r.month(monthKey)
+	record.Categories = append(record.Categories, category.Clone())
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) UpdateCategory(monthKey string, category domain.Category) error {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	i := slices.IndexFunc(record.Categories, func(existing domain.Category) bool {
+		return existing.CatID == category.CatID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordCategory, ID: category.CatID, MonthKey: monthKey}
+	}
+	record.Categories[i] = category.Clone()
+	return nil
+}
+
+func (r *MemoryRepository) DeleteCategory(monthKey string, categoryID string) error {
+	record, ok := r.monthlyData[monthKey]
+	if !ok {
+		return &domain.NotFoundError{MonthKey: monthKey}
+	}
+	i := slices.IndexFunc(record.Categories, func(existing domain.Category) bool {
+		return existing.CatID == categoryID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordCategory, ID: categoryID, MonthKey: monthKey}
+	}
+	record.Categories = slices.Delete(record.Categories, i, i+1)
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) CopyCategoriesFromMonth(fromMonthKey, toMonthKey string) (int, error) {
+	return copyCategories(r, fromMonthKey, toMonthKey)
+}
+
+func (r *MemoryRepository) GetAllTemplates() ([]domain.RecurringTemplate, error) {
+	templates := make([]domain.RecurringTemplate, len(r.templates))
+	copy(templates, r.templates)
+	return templates, nil
+}
+
+func (r *MemoryRepository) AddTemplate(template domain.RecurringTemplate) error {
+	for _, existing := range r.templates {
+		if existing.TemplateID == template.TemplateID {
+			return &domain.AlreadyExistsError{Record: domain.RecordTemplate, ID: template.TemplateID}
+		}
+	}
+	r.templates = append(r.templates, template)
+	return nil
+}
+
+func (r *MemoryRepository) UpdateTemplate(template domain.RecurringTemplate) error {
+	i := slices.IndexFunc(r.templates, func(existing domain.RecurringTemplate) bool {
+		return existing.TemplateID == template.TemplateID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordTemplate, ID: template.TemplateID}
+	}
+	r.templates[i] = template
+	return nil
+}
+
+func (r *MemoryRepository) DeleteTemplate(templateID string) error {
+	i := slices.IndexFunc(r.templates, func(existing domain.RecurringTemplate) bool {
+		return existing.TemplateID == templateID
+	})
+	if i < 0 {
+		return &domain.NotFoundError{Record: domain.RecordTemplate, ID: templateID}
+	}
+	r.templates = slices.Delete(r.templates, i, i+1)
+	return nil
+}
+
+func (r *MemoryRepository) GetAppliedTemplates(monthKey string) ([]string, error) {
+	applied := slices.Clone(r.month(monthKey).AppliedTemplates)
+	if applied == nil {
+		applied = []string{}
+	}
+	return applied, nil
+}
+
+func (r *MemoryRepository) MarkTemplateApplied(monthKey string, templateID string) error {
+	record := r.month(monthKey)
+	if slices.Contains(record.AppliedTemplates, templateID) {
+		return nil
+	}
+	record.AppliedTemplates = append(record.AppliedTemplates, templateID)
+	r.monthlyData[monthKey] = record
+	return nil
+}
+
+func (r *MemoryRepository) GetMonths() ([]domain.Month, error) {
+	var months []domain.Month
+	for monthKey, record := range r.monthlyData {
+		month, err := domain.ParseMonth(monthKey)
+		if err != nil || !hasMonthData(record) {
+			continue
+		}
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Before(months[j])
+	})
+	return months, nil
+}
+
+func (r *MemoryRepository) GetMonthRange(from, to domain.Month) ([]domain.MonthData, error) {
+	months, err := r.GetMonths()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.MonthData
+	for _, month := range months {
+		if month.Before(from) || month.After(to) {
+			continue
+		}
+		record := r.monthlyData[month.String()]
+		result = append(result, domain.MonthData{
+			Month: month,
+			MonthlyRecord: domain.MonthlyRecord{
+				Incomes:          append([]domain.IncomeRecord{}, record.Incomes...),
+				Categories:       cloneCategories(record.Categories),
+				Rates:            append([]domain.ExchangeRate{}, record.Rates...),
+				AppliedTemplates: append([]string{}, record.AppliedTemplates...),
+			},
+		})
+	}
+	return result, nil
+}
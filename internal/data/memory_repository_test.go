@@ -0,0 +1,175 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_GroupOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Group 2", Order: 2}))
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Group 1", Order: 1}))
+	assert.Error(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1"}))
+
+	groups, err := repo.GetAllGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Group 1", groups[0].GroupName)
+
+	require.NoError(t, repo.UpdateGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Group 1 Updated", Order: 1}))
+	group, err := repo.GetGroupByID("g1")
+	require.NoError(t, err)
+	assert.Equal(t, "Group 1 Updated", group.GroupName)
+
+	require.NoError(t, repo.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+	assert.ErrorContains(t, repo.DeleteGroup("g1"), "still being used")
+	require.NoError(t, repo.DeleteGroup("g2"))
+	_, err = repo.GetGroupByID("g2")
+	assert.Error(t, err)
+}
+
+func TestMemoryRepository_IncomeOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	monthKey := "2024-06"
+
+	incomes, err := repo.GetIncomesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.NotNil(t, incomes)
+	assert.Empty(t, incomes)
+
+	require.NoError(t, repo.AddIncome(monthKey, domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5000)}))
+	require.NoError(t, repo.AddIncome(monthKey, domain.IncomeRecord{IncomeID: "i2", Description: "Freelance", Amount: decimal.NewFromInt(1000)}))
+	assert.Error(t, repo.AddIncome(monthKey, domain.IncomeRecord{IncomeID: "i1"}))
+
+	require.NoError(t, repo.UpdateIncome(monthKey, domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5500)}))
+	require.NoError(t, repo.DeleteIncome(monthKey, "i2"))
+	assert.Error(t, repo.DeleteIncome(monthKey, "i2"))
+
+	incomes, err = repo.GetIncomesForMonth(monthKey)
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	assert.Equal(t, "5500", incomes[0].Amount.String())
+}
+
+func TestMemoryRepository_CategoryOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	monthKey := "2024-07"
+	rent := domain.Category{
+		CatID:        "c1",
+		GroupID:      "g1",
+		CategoryName: "Rent",
+		Expense:      map[string]domain.ExpenseRecord{"c1": {Amount: decimal.NewFromInt(900)}},
+	}
+	require.NoError(t, repo.AddCategory(monthKey, rent))
+
+	// Changing a category read from the repository does not change the repository
+	categories, err := repo.GetCategoriesForMonth(monthKey)
+	require.NoError(t, err)
+	categories[0].Expense["c1"] = domain.ExpenseRecord{Amount: decimal.NewFromInt(1)}
+	categories, err = repo.GetCategoriesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Equal(t, "900", categories[0].Expense["c1"].Amount.String())
+
+	rent.CategoryName = "Mortgage"
+	require.NoError(t, repo.UpdateCategory(monthKey, rent))
+	assert.Error(t, repo.UpdateCategory(monthKey, domain.Category{CatID: "c9"}))
+
+	count, err := repo.CopyCategoriesFromMonth(monthKey, "2024-08")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	copied, err := repo.GetCategoriesForMonth("2024-08")
+	require.NoError(t, err)
+	require.Len(t, copied, 1)
+	assert.Equal(t, "Mortgage", copied[0].CategoryName)
+	assert.Empty(t, copied[0].Expense)
+
+	require.NoError(t, repo.DeleteCategory(monthKey, "c1"))
+	categories, err = repo.GetCategoriesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+	_, err = repo.CopyCategoriesFromMonth(monthKey, "2024-09")
+	assert.Error(t, err)
+}
+
+func TestMemoryRepository_RateOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	monthKey := "2024-06"
+
+	require.NoError(t, repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.08")}))
+	require.NoError(t, repo.SetRate(monthKey, domain.ExchangeRate{Currency: "GBP", Rate: decimal.RequireFromString("1.27")}))
+	require.NoError(t, repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.1")}))
+
+	rates, err := repo.GetRatesForMonth(monthKey)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "1.1", rates[0].Rate.String())
+
+	require.NoError(t, repo.DeleteRate(monthKey, "GBP"))
+	assert.Error(t, repo.DeleteRate(monthKey, "GBP"))
+}
+
+func TestMemoryRepository_TemplateOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	template := domain.RecurringTemplate{TemplateID: "t1", Kind: domain.TemplateExpense, Name: "Rent", Interval: 1, StartMonth: "2024-01"}
+
+	require.NoError(t, repo.AddTemplate(template))
+	assert.Error(t, repo.AddTemplate(template))
+	template.EndMonth = "2024-12"
+	require.NoError(t, repo.UpdateTemplate(template))
+
+	templates, err := repo.GetAllTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "2024-12", templates[0].EndMonth)
+
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	applied, err := repo.GetAppliedTemplates("2024-03")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, applied)
+
+	require.NoError(t, repo.DeleteTemplate("t1"))
+	assert.Error(t, repo.DeleteTemplate("t1"))
+}
+
+func TestMemoryRepository_MonthOperations(t *testing.T) {
+	repo := NewMemoryRepository()
+	require.NoError(t, repo.AddIncome("2024-11", domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(100)}))
+	require.NoError(t, repo.AddCategory("2025-01", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+	require.NoError(t, repo.SetRate("2024-03", domain.ExchangeRate{Currency: "EUR", Rate: decimal.NewFromInt(1)}))
+	require.NoError(t, repo.MarkTemplateApplied("2024-12", "t1"))
+
+	months, err := repo.GetMonths()
+	require.NoError(t, err)
+	var keys []string
+	for _, month := range months {
+		keys = append(keys, month.String())
+	}
+	assert.Equal(t, []string{"2024-03", "2024-11", "2025-01"}, keys)
+
+	data, err := repo.GetMonthRange(domain.NewMonth(2024, time.April), domain.NewMonth(2025, time.January))
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, "2024-11", data[0].Month.String())
+	assert.Equal(t, "Rent", data[1].Categories[0].CategoryName)
+}
+
+func TestMemoryJournal(t *testing.T) {
+	repo := NewMemoryRepository()
+	journal := NewMemoryJournal()
+	journaled := NewJournaledRepository(repo, journal)
+
+	require.NoError(t, journaled.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing"}))
+	require.NoError(t, journaled.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i1", Description: "Salary"}))
+
+	changes, err := journaled.GetChanges(domain.ChangeFilter{})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "Salary", changes[0].Name)
+	assert.Empty(t, journal.Path())
+}
@@ -0,0 +1,65 @@
+package data
+
+import "github.com/madalinpopa/gocost/internal/domain"
+
+// Repository is implemented by every storage backend and satisfies
+// all the domain repository interfaces.
+type Repository interface {
+	domain.CategoryRepository
+	domain.GroupRepository
+	domain.IncomeRepository
+	domain.RateRepository
+	domain.RecurringRepository
+	domain.MonthRepository
+	domain.UnitOfWork
+
+	// FilePath returns the path of the file backing the repository.
+	FilePath() string
+}
+
+var (
+	_ Repository = (*JsonRepository)(nil)
+	_ Repository = (*SqliteRepository)(nil)
+	_ Repository = (*MemoryRepository)(nil)
+)
+
+// copyCategories replaces the categories of toMonthKey with those of
+// fromMonthKey, without their expenses, in one unit of work of repo. It
+// returns the number of categories copied.
+func copyCategories(repo Repository, fromMonthKey, toMonthKey string) (int, error) {
+	categories, err := repo.GetCategoriesForMonth(fromMonthKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(categories) == 0 {
+		return 0, &domain.NotFoundError{Record: domain.RecordCategory, MonthKey: fromMonthKey}
+	}
+
+	err = repo.Atomically(func() error {
+		replaced, err := repo.GetCategoriesForMonth(toMonthKey)
+		if err != nil {
+			return err
+		}
+		for _, category := range replaced {
+			if err := repo.DeleteCategory(toMonthKey, category.CatID); err != nil {
+				return err
+			}
+		}
+		for _, category := range categories {
+			copied := domain.Category{
+				CatID:        category.CatID,
+				GroupID:      category.GroupID,
+				CategoryName: category.CategoryName,
+				Expense:      make(map[string]domain.ExpenseRecord),
+			}
+			if err := repo.AddCategory(toMonthKey, copied); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(categories), nil
+}
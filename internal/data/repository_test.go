@@ -0,0 +1,109 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositories_TypedErrors(t *testing.T) {
+	repos := map[string]func(t *testing.T) Repository{
+		"json":   func(t *testing.T) Repository { return setupTestRepo(t) },
+		"sqlite": func(t *testing.T) Repository { return setupTestSqliteRepo(t) },
+		"memory": func(t *testing.T) Repository { return NewMemoryRepository() },
+	}
+
+	for name, setup := range repos {
+		t.Run(name, func(t *testing.T) {
+			repo := setup(t)
+			require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing", Order: 1}))
+			require.NoError(t, repo.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+			require.NoError(t, repo.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i1", Description: "Salary"}))
+
+			_, err := repo.GetGroupByID("g9")
+			var notFound *domain.NotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.Equal(t, domain.RecordGroup, notFound.Record)
+			assert.Equal(t, "g9", notFound.ID)
+
+			err = repo.UpdateCategory("2024-06", domain.Category{CatID: "c9"})
+			require.ErrorAs(t, err, &notFound)
+			assert.Equal(t, domain.NotFoundError{Record: domain.RecordCategory, ID: "c9", MonthKey: "2024-06"}, *notFound)
+			assert.ErrorIs(t, repo.DeleteIncome("2024-06", "i9"), domain.ErrNotFound)
+			assert.ErrorIs(t, repo.DeleteIncome("2023-01", "i1"), domain.ErrNotFound)
+			assert.ErrorIs(t, repo.DeleteRate("2024-06", "EUR"), domain.ErrNotFound)
+			assert.ErrorIs(t, repo.DeleteTemplate("t9"), domain.ErrNotFound)
+			_, err = repo.CopyCategoriesFromMonth("2023-01", "2023-02")
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			assert.ErrorIs(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1"}), domain.ErrAlreadyExists)
+			assert.ErrorIs(t, repo.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i1"}), domain.ErrAlreadyExists)
+
+			err = repo.DeleteGroup("g1")
+			var inUse *domain.InUseError
+			require.ErrorAs(t, err, &inUse)
+			assert.Equal(t, "Housing", inUse.Name)
+			assert.Equal(t, domain.RecordCategory, inUse.UsedBy)
+			assert.False(t, errors.Is(err, domain.ErrNotFound))
+		})
+	}
+}
+
+func TestRepositories_Atomically(t *testing.T) {
+	repos := map[string]func(t *testing.T) Repository{
+		"json":   func(t *testing.T) Repository { return setupTestRepo(t) },
+		"sqlite": func(t *testing.T) Repository { return setupTestSqliteRepo(t) },
+		"memory": func(t *testing.T) Repository { return NewMemoryRepository() },
+	}
+	errFailed := errors.New("failed")
+
+	for name, setup := range repos {
+		t.Run(name, func(t *testing.T) {
+			repo := setup(t)
+			require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing", Order: 1}))
+
+			err := repo.Atomically(func() error {
+				require.NoError(t, repo.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+				require.NoError(t, repo.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i1", Description: "Salary"}))
+				// Changes are visible within the unit
+				categories, err := repo.GetCategoriesForMonth("2024-06")
+				require.NoError(t, err)
+				assert.Len(t, categories, 1)
+				return nil
+			})
+			require.NoError(t, err)
+
+			err = repo.Atomically(func() error {
+				require.NoError(t, repo.DeleteCategory("2024-06", "c1"))
+				require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living", Order: 2}))
+				return errFailed
+			})
+			require.ErrorIs(t, err, errFailed)
+
+			categories, err := repo.GetCategoriesForMonth("2024-06")
+			require.NoError(t, err)
+			assert.Len(t, categories, 1, "a failed unit of work is rolled back")
+			_, err = repo.GetGroupByID("g2")
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			err = repo.Atomically(func() error {
+				require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living", Order: 2}))
+				err := repo.Atomically(func() error {
+					require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g3", GroupName: "Leisure", Order: 3}))
+					return repo.AddGroup(domain.CategoryGroup{GroupID: "g1"})
+				})
+				assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+				return nil
+			})
+			require.NoError(t, err)
+
+			groups, err := repo.GetAllGroups()
+			require.NoError(t, err)
+			require.Len(t, groups, 2, "only the failed nested unit of work is rolled back")
+			assert.Equal(t, "g2", groups[1].GroupID)
+		})
+	}
+}
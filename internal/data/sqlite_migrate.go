@@ -0,0 +1,247 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// sqliteMoneyTables lists the tables holding money amounts together with the
+// columns copied when they are rebuilt with exact decimal text columns.
+var sqliteMoneyTables = []struct {
+	name    string
+	columns []string
+	money   []string
+}{
+	{
+		name:    "expenses",
+		columns: []string{"month_key", "cat_id", "expense_key", "status", "notes"},
+		money:   []string{"budget", "amount"},
+	},
+	{
+		name:    "expense_entries",
+		columns: []string{"month_key", "cat_id", "expense_key", "entry_id", "entry_date", "description", "position"},
+		money:   []string{"amount"},
+	},
+	{
+		name:    "incomes",
+		columns: []string{"month_key", "income_id", "description", "position"},
+		money:   []string{"amount"},
+	},
+}
+
+// sqliteCurrencyTables lists the tables that gained a currency column in version 2.
+var sqliteCurrencyTables = []string{"expenses", "incomes"}
+
+// migrateSqlite creates the schema and upgrades databases written by older
+// versions. Tables storing money as REAL are rebuilt with TEXT columns so
+// that amounts are kept exactly, and missing currency columns and tables are
+// added. Databases written by newer versions are refused.
+func migrateSqlite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > sqliteSchemaVersion {
+		return fmt.Errorf("%w: database version %d, supported up to %d", ErrNewerDataFile, version, sqliteSchemaVersion)
+	}
+	if version == sqliteSchemaVersion {
+		_, err := db.Exec(sqliteSchema)
+		return err
+	}
+	if version < 1 {
+		if err := migrateSqliteMoney(db); err != nil {
+			return err
+		}
+	}
+	if version < 2 {
+		if err := migrateSqliteCurrencies(db); err != nil {
+			return err
+		}
+	}
+	if version < 3 {
+		// Version 3 only adds tables, which the schema creates when missing.
+		if _, err := db.Exec(sqliteSchema); err != nil {
+			return err
+		}
+	}
+	if version < 4 {
+		if err := migrateSqliteMonthKeys(db); err != nil {
+			return err
+		}
+	}
+	_, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, sqliteSchemaVersion))
+	return err
+}
+
+// sqliteMonthKeyColumns lists the columns holding month keys.
+var sqliteMonthKeyColumns = []struct {
+	table  string
+	column string
+}{
+	{"months", "month_key"},
+	{"categories", "month_key"},
+	{"expenses", "month_key"},
+	{"expense_entries", "month_key"},
+	{"incomes", "month_key"},
+	{"exchange_rates", "month_key"},
+	{"applied_templates", "month_key"},
+	{"recurring_overrides", "month_key"},
+	{"recurring_templates", "start_month"},
+	{"recurring_templates", "end_month"},
+}
+
+// migrateSqliteMonthKeys rewrites the month keys written before version 4,
+// such as "January-2024", in the sortable YYYY-MM format.
+func migrateSqliteMonthKeys(db *sql.DB) error {
+	// Keys are rewritten table by table, so foreign keys are checked only
+	// once every table has been updated.
+	if _, err := db.Exec(`PRAGMA foreign_keys = OFF`); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = db.Exec(`PRAGMA foreign_keys = ON`)
+	}()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, c := range sqliteMonthKeyColumns {
+		keys, err := sqliteDistinctValues(tx, c.table, c.column)
+		if err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", c.table, err)
+		}
+		for _, key := range keys {
+			t, err := time.Parse(legacyMonthKeyLayout, key)
+			if err != nil {
+				continue
+			}
+			query := fmt.Sprintf(`UPDATE %s SET %s = ? WHERE %s = ?`, c.table, c.column, c.column)
+			if _, err := tx.Exec(query, t.Format(domain.MonthLayout), key); err != nil {
+				return fmt.Errorf("failed to migrate table %s: %w", c.table, err)
+			}
+		}
+	}
+
+	rows, err := tx.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	violations := rows.Next()
+	_ = rows.Close()
+	if violations {
+		return errors.New("failed to migrate month keys: foreign key check failed")
+	}
+	return tx.Commit()
+}
+
+// sqliteDistinctValues returns the distinct values of a text column.
+func sqliteDistinctValues(tx *sql.Tx, table, column string) ([]string, error) {
+	rows, err := tx.Query(fmt.Sprintf(`SELECT DISTINCT %s FROM %s`, column, table))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// migrateSqliteCurrencies adds the currency column to tables created before version 2.
+func migrateSqliteCurrencies(db *sql.DB) error {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	for _, table := range sqliteCurrencyTables {
+		var exists bool
+		if err := db.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = 'currency')`, table,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN currency TEXT NOT NULL DEFAULT ''`, table)); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// migrateSqliteMoney rebuilds the tables that store money as REAL with TEXT columns.
+func migrateSqliteMoney(db *sql.DB) error {
+
+	var legacy []int
+	for i, table := range sqliteMoneyTables {
+		var columnType sql.NullString
+		err := db.QueryRow(
+			`SELECT type FROM pragma_table_info(?) WHERE name = 'amount'`, table.name,
+		).Scan(&columnType)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if strings.EqualFold(columnType.String, "REAL") {
+			legacy = append(legacy, i)
+		}
+	}
+
+	// Renaming must not rewrite the foreign keys of the other tables, and the
+	// rows are moved table by table, so both checks are relaxed meanwhile.
+	if _, err := db.Exec(`PRAGMA foreign_keys = OFF; PRAGMA legacy_alter_table = ON`); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = db.Exec(`PRAGMA legacy_alter_table = OFF; PRAGMA foreign_keys = ON`)
+	}()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, i := range legacy {
+		table := sqliteMoneyTables[i]
+		if _, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s RENAME TO %s_legacy`, table.name, table.name)); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table.name, err)
+		}
+	}
+
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	for _, i := range legacy {
+		table := sqliteMoneyTables[i]
+		columns := append(append([]string{}, table.columns...), table.money...)
+		selected := append([]string{}, table.columns...)
+		for _, column := range table.money {
+			selected = append(selected, fmt.Sprintf("CAST(%s AS TEXT)", column))
+		}
+		query := fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s_legacy`,
+			table.name, strings.Join(columns, ", "), strings.Join(selected, ", "), table.name)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table.name, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`DROP TABLE %s_legacy`, table.name)); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,896 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaVersion is stored in the user_version pragma of the database.
+// Version 1 stores money amounts as exact decimal text instead of REAL and
+// version 2 adds the record currencies and the exchange rates. Version 3
+// adds the recurring templates and version 4 stores month keys as YYYY-MM.
+const sqliteSchemaVersion = 4
+
+// sqliteSchema creates the tables used by the SqliteRepository.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS settings (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS category_groups (
+	group_id   TEXT PRIMARY KEY,
+	group_name TEXT NOT NULL,
+	sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS months (
+	month_key TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+	month_key     TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	cat_id        TEXT NOT NULL,
+	group_id      TEXT NOT NULL,
+	category_name TEXT NOT NULL,
+	position      INTEGER NOT NULL,
+	PRIMARY KEY (month_key, cat_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_group ON categories(group_id);
+
+CREATE TABLE IF NOT EXISTS expenses (
+	month_key   TEXT NOT NULL,
+	cat_id      TEXT NOT NULL,
+	expense_key TEXT NOT NULL,
+	budget      TEXT NOT NULL DEFAULT '0',
+	amount      TEXT NOT NULL DEFAULT '0',
+	currency    TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT '',
+	notes       TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (month_key, cat_id, expense_key),
+	FOREIGN KEY (month_key, cat_id) REFERENCES categories(month_key, cat_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS expense_entries (
+	month_key   TEXT NOT NULL,
+	cat_id      TEXT NOT NULL,
+	expense_key TEXT NOT NULL,
+	entry_id    TEXT NOT NULL,
+	entry_date  TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	amount      TEXT NOT NULL DEFAULT '0',
+	position    INTEGER NOT NULL,
+	PRIMARY KEY (month_key, cat_id, expense_key, entry_id),
+	FOREIGN KEY (month_key, cat_id, expense_key) REFERENCES expenses(month_key, cat_id, expense_key) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS incomes (
+	month_key   TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	income_id   TEXT NOT NULL,
+	description TEXT NOT NULL,
+	amount      TEXT NOT NULL DEFAULT '0',
+	currency    TEXT NOT NULL DEFAULT '',
+	position    INTEGER NOT NULL,
+	PRIMARY KEY (month_key, income_id)
+);
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+	month_key TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	currency  TEXT NOT NULL,
+	rate      TEXT NOT NULL,
+	position  INTEGER NOT NULL,
+	PRIMARY KEY (month_key, currency)
+);
+
+CREATE TABLE IF NOT EXISTS recurring_templates (
+	template_id TEXT PRIMARY KEY,
+	kind        TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	budget      TEXT NOT NULL DEFAULT '0',
+	amount      TEXT NOT NULL DEFAULT '0',
+	currency    TEXT NOT NULL DEFAULT '',
+	interval    INTEGER NOT NULL DEFAULT 1,
+	start_month TEXT NOT NULL,
+	end_month   TEXT NOT NULL DEFAULT '',
+	position    INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recurring_overrides (
+	template_id TEXT NOT NULL REFERENCES recurring_templates(template_id) ON DELETE CASCADE,
+	month_key   TEXT NOT NULL,
+	skip        INTEGER NOT NULL DEFAULT 0,
+	amount      TEXT,
+	budget      TEXT,
+	PRIMARY KEY (template_id, month_key)
+);
+
+CREATE TABLE IF NOT EXISTS applied_templates (
+	month_key   TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	template_id TEXT NOT NULL,
+	PRIMARY KEY (month_key, template_id)
+);
+`
+
+// SqliteRepository is a concrete implementation of the repository interfaces
+// that uses a SQLite database for storage.
+type SqliteRepository struct {
+	filePath string
+	db       *sql.DB
+
+	tx         *sql.Tx // Transaction of the running unit of work, see Atomically
+	savepoints int     // Number of savepoints open in tx
+}
+
+// querier runs statements on the database or within a transaction.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// NewSqliteRepository opens, and creates if needed, the SQLite database at filePath.
+func NewSqliteRepository(filePath string, defaultCurrency string) (*SqliteRepository, error) {
+	db, err := sql.Open("sqlite", filePath+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// SQLite allows a single writer; one connection keeps writes serialized.
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSqlite(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create database schema: %w", err)
+	}
+
+	if _, err := db.Exec(
+		`INSERT INTO settings (key, value) VALUES ('defaultCurrency', ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`,
+		defaultCurrency,
+	); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	return &SqliteRepository{
+		filePath: filePath,
+		db:       db,
+	}, nil
+}
+
+// FilePath returns the path to the SQLite database file.
+func (r *SqliteRepository) FilePath() string {
+	return r.filePath
+}
+
+// Close closes the underlying database.
+func (r *SqliteRepository) Close() error {
+	return r.db.Close()
+}
+
+// conn returns the transaction of the running unit of work, or the database
+// outside of one. The single connection of the database is held by the
+// transaction, so queries of the unit must run on it.
+func (r *SqliteRepository) conn() querier {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// Atomically runs fn as a unit of work, see domain.UnitOfWork. The changes
+// of the unit are made in one transaction, and those of the units started
+// within it in savepoints.
+func (r *SqliteRepository) Atomically(fn func() error) error {
+	if r.tx != nil {
+		return r.withSavepoint(func(*sql.Tx) error { return fn() })
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	r.tx = tx
+	err = fn()
+	r.tx = nil
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save data: %w", err)
+	}
+	return nil
+}
+
+// withSavepoint runs fn inside a savepoint of the transaction of the running
+// unit of work, so that an error only rolls back the changes of fn.
+func (r *SqliteRepository) withSavepoint(fn func(tx *sql.Tx) error) error {
+	r.savepoints++
+	defer func() { r.savepoints-- }()
+	name := fmt.Sprintf("unit%d", r.savepoints)
+
+	if _, err := r.tx.Exec("SAVEPOINT " + name); err != nil {
+		return fmt.Errorf("failed to begin savepoint: %w", err)
+	}
+	if err := fn(r.tx); err != nil {
+		_, _ = r.tx.Exec("ROLLBACK TO " + name)
+		_, _ = r.tx.Exec("RELEASE " + name)
+		return err
+	}
+	if _, err := r.tx.Exec("RELEASE " + name); err != nil {
+		return fmt.Errorf("failed to save data: %w", err)
+	}
+	return nil
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back
+// on error. Within a unit of work, fn runs inside a savepoint of its transaction.
+func (r *SqliteRepository) withTx(fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return r.withSavepoint(fn)
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save data: %w", err)
+	}
+	return nil
+}
+
+// monthExists reports whether any data has been recorded for monthKey.
+func monthExists(tx *sql.Tx, monthKey string) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM months WHERE month_key = ?)`, monthKey).Scan(&exists)
+	return exists, err
+}
+
+// ensureMonth records monthKey so that data can be attached to it.
+func ensureMonth(tx *sql.Tx, monthKey string) error {
+	_, err := tx.Exec(`INSERT OR IGNORE INTO months (month_key) VALUES (?)`, monthKey)
+	return err
+}
+
+// insertCategory appends a category and its expenses at the end of the month's categories.
+func insertCategory(tx *sql.Tx, monthKey string, category domain.Category) error {
+	_, err := tx.Exec(
+		`INSERT INTO categories (month_key, cat_id, group_id, category_name, position)
+		 VALUES (?, ?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM categories WHERE month_key = ?))`,
+		monthKey, category.CatID, category.GroupID, category.CategoryName, monthKey,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add category %s: %w", category.CatID, err)
+	}
+	return insertExpenses(tx, monthKey, category)
+}
+
+// insertExpenses stores the expense records of a category.
+func insertExpenses(tx *sql.Tx, monthKey string, category domain.Category) error {
+	for key, expense := range category.Expense {
+		_, err := tx.Exec(
+			`INSERT INTO expenses (month_key, cat_id, expense_key, budget, amount, currency, status, notes)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			monthKey, category.CatID, key, expense.Budget, expense.Amount, expense.Currency, expense.Status, expense.Notes,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save expense for category %s: %w", category.CatID, err)
+		}
+		for i, entry := range expense.Entries {
+			_, err := tx.Exec(
+				`INSERT INTO expense_entries (month_key, cat_id, expense_key, entry_id, entry_date, description, amount, position)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				monthKey, category.CatID, key, entry.EntryID, entry.Date.Format(time.RFC3339), entry.Description, entry.Amount, i,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to save expense entry for category %s: %w", category.CatID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *SqliteRepository) GetAllGroups() ([]domain.CategoryGroup, error) {
+	rows, err := r.conn().Query(`SELECT group_id, group_name, sort_order FROM category_groups ORDER BY sort_order`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var groups []domain.CategoryGroup
+	for rows.Next() {
+		var group domain.CategoryGroup
+		if err := rows.Scan(&group.GroupID, &group.GroupName, &group.Order); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+func (r *SqliteRepository) GetGroupByID(groupID string) (domain.CategoryGroup, error) {
+	var group domain.CategoryGroup
+	err := r.conn().QueryRow(
+		`SELECT group_id, group_name, sort_order FROM category_groups WHERE group_id = ?`, groupID,
+	).Scan(&group.GroupID, &group.GroupName, &group.Order)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.CategoryGroup{}, &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
+	}
+	if err != nil {
+		return domain.CategoryGroup{}, err
+	}
+	return group, nil
+}
+
+func (r *SqliteRepository) AddGroup(group domain.CategoryGroup) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM category_groups WHERE group_id = ?)`, group.GroupID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return &domain.AlreadyExistsError{Record: domain.RecordGroup, ID: group.GroupID}
+		}
+		_, err := tx.Exec(
+			`INSERT INTO category_groups (group_id, group_name, sort_order) VALUES (?, ?, ?)`,
+			group.GroupID, group.GroupName, group.Order,
+		)
+		return err
+	})
+}
+
+func (r *SqliteRepository) UpdateGroup(group domain.CategoryGroup) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`UPDATE category_groups SET group_name = ?, sort_order = ? WHERE group_id = ?`,
+			group.GroupName, group.Order, group.GroupID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordGroup, ID: group.GroupID}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) DeleteGroup(groupID string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		var inUse bool
+		if err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM categories WHERE group_id = ?)`, groupID,
+		).Scan(&inUse); err != nil {
+			return err
+		}
+		if inUse {
+			var groupName string
+			_ = tx.QueryRow(`SELECT group_name FROM category_groups WHERE group_id = ?`, groupID).Scan(&groupName)
+			return &domain.InUseError{Record: domain.RecordGroup, ID: groupID, Name: groupName, UsedBy: domain.RecordCategory}
+		}
+		res, err := tx.Exec(`DELETE FROM category_groups WHERE group_id = ?`, groupID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordGroup, ID: groupID}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) GetIncomesForMonth(monthKey string) ([]domain.IncomeRecord, error) {
+	rows, err := r.conn().Query(
+		`SELECT income_id, description, amount, currency FROM incomes WHERE month_key = ? ORDER BY position`, monthKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	incomes := []domain.IncomeRecord{}
+	for rows.Next() {
+		var income domain.IncomeRecord
+		if err := rows.Scan(&income.IncomeID, &income.Description, &income.Amount, &income.Currency); err != nil {
+			return nil, err
+		}
+		incomes = append(incomes, income)
+	}
+	return incomes, rows.Err()
+}
+
+func (r *SqliteRepository) AddIncome(monthKey string, income domain.IncomeRecord) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		if err := ensureMonth(tx, monthKey); err != nil {
+			return err
+		}
+		var exists bool
+		if err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM incomes WHERE month_key = ? AND income_id = ?)`, monthKey, income.IncomeID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return &domain.AlreadyExistsError{Record: domain.RecordIncome, ID: income.IncomeID}
+		}
+		_, err := tx.Exec(
+			`INSERT INTO incomes (month_key, income_id, description, amount, currency, position)
+			 VALUES (?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM incomes WHERE month_key = ?))`,
+			monthKey, income.IncomeID, income.Description, income.Amount, income.Currency, monthKey,
+		)
+		return err
+	})
+}
+
+func (r *SqliteRepository) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		exists, err := monthExists(tx, monthKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &domain.NotFoundError{MonthKey: monthKey}
+		}
+		res, err := tx.Exec(
+			`UPDATE incomes SET description = ?, amount = ?, currency = ? WHERE month_key = ? AND income_id = ?`,
+			income.Description, income.Amount, income.Currency, monthKey, income.IncomeID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordIncome, ID: income.IncomeID, MonthKey: monthKey}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) DeleteIncome(monthKey string, incomeID string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		exists, err := monthExists(tx, monthKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &domain.NotFoundError{MonthKey: monthKey}
+		}
+		res, err := tx.Exec(`DELETE FROM incomes WHERE month_key = ? AND income_id = ?`, monthKey, incomeID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordIncome, ID: incomeID, MonthKey: monthKey}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) GetRatesForMonth(monthKey string) ([]domain.ExchangeRate, error) {
+	rows, err := r.conn().Query(
+		`SELECT currency, rate FROM exchange_rates WHERE month_key = ? ORDER BY position`, monthKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	rates := []domain.ExchangeRate{}
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *SqliteRepository) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		if err := ensureMonth(tx, monthKey); err != nil {
+			return err
+		}
+		_, err := tx.Exec(
+			`INSERT INTO exchange_rates (month_key, currency, rate, position)
+			 VALUES (?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM exchange_rates WHERE month_key = ?))
+			 ON CONFLICT(month_key, currency) DO UPDATE SET rate = excluded.rate`,
+			monthKey, rate.Currency, rate.Rate, monthKey,
+		)
+		return err
+	})
+}
+
+func (r *SqliteRepository) DeleteRate(monthKey string, currency string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		exists, err := monthExists(tx, monthKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &domain.NotFoundError{MonthKey: monthKey}
+		}
+		res, err := tx.Exec(`DELETE FROM exchange_rates WHERE month_key = ? AND currency = ?`, monthKey, currency)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordRate, ID: currency, MonthKey: monthKey}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) GetCategoriesForMonth(monthKey string) ([]domain.Category, error) {
+	rows, err := r.conn().Query(
+		`SELECT cat_id, group_id, category_name FROM categories WHERE month_key = ? ORDER BY position`, monthKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	categories := []domain.Category{}
+	index := make(map[string]int)
+	for rows.Next() {
+		category := domain.Category{Expense: make(map[string]domain.ExpenseRecord)}
+		if err := rows.Scan(&category.CatID, &category.GroupID, &category.CategoryName); err != nil {
+			return nil, err
+		}
+		index[category.CatID] = len(categories)
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	expenseRows, err := r.conn().Query(
+		`SELECT cat_id, expense_key, budget, amount, currency, status, notes FROM expenses WHERE month_key = ?`, monthKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = expenseRows.Close() }()
+
+	for expenseRows.Next() {
+		var catID, key string
+		var expense domain.ExpenseRecord
+		if err := expenseRows.Scan(&catID, &key, &expense.Budget, &expense.Amount, &expense.Currency, &expense.Status, &expense.Notes); err != nil {
+			return nil, err
+		}
+		if i, ok := index[catID]; ok {
+			categories[i].Expense[key] = expense
+		}
+	}
+	if err := expenseRows.Err(); err != nil {
+		return nil, err
+	}
+
+	entryRows, err := r.conn().Query(
+		`SELECT cat_id, expense_key, entry_id, entry_date, description, amount FROM expense_entries
+		 WHERE month_key = ? ORDER BY position`, monthKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = entryRows.Close() }()
+
+	for entryRows.Next() {
+		var catID, key, date string
+		var entry domain.ExpenseEntry
+		if err := entryRows.Scan(&catID, &key, &entry.EntryID, &date, &entry.Description, &entry.Amount); err != nil {
+			return nil, err
+		}
+		if entry.Date, err = time.Parse(time.RFC3339, date); err != nil {
+			return nil, fmt.Errorf("invalid date for expense entry %s: %w", entry.EntryID, err)
+		}
+		i, ok := index[catID]
+		if !ok {
+			continue
+		}
+		if expense, ok := categories[i].Expense[key]; ok {
+			expense.Entries = append(expense.Entries, entry)
+			categories[i].Expense[key] = expense
+		}
+	}
+	return categories, entryRows.Err()
+}
+
+func (r *SqliteRepository) AddCategory(monthKey string, category domain.Category) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		if err := ensureMonth(tx, monthKey); err != nil {
+			return err
+		}
+		return insertCategory(tx, monthKey, category)
+	})
+}
+
+func (r *SqliteRepository) UpdateCategory(monthKey string, category domain.Category) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		exists, err := monthExists(tx, monthKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &domain.NotFoundError{MonthKey: monthKey}
+		}
+		res, err := tx.Exec(
+			`UPDATE categories SET group_id = ?, category_name = ? WHERE month_key = ? AND cat_id = ?`,
+			category.GroupID, category.CategoryName, monthKey, category.CatID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordCategory, ID: category.CatID, MonthKey: monthKey}
+		}
+		if _, err := tx.Exec(
+			`DELETE FROM expenses WHERE month_key = ? AND cat_id = ?`, monthKey, category.CatID,
+		); err != nil {
+			return err
+		}
+		return insertExpenses(tx, monthKey, category)
+	})
+}
+
+func (r *SqliteRepository) DeleteCategory(monthKey string, categoryID string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		exists, err := monthExists(tx, monthKey)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return &domain.NotFoundError{MonthKey: monthKey}
+		}
+		res, err := tx.Exec(`DELETE FROM categories WHERE month_key = ? AND cat_id = ?`, monthKey, categoryID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordCategory, ID: categoryID, MonthKey: monthKey}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) CopyCategoriesFromMonth(fromMonthKey, toMonthKey string) (int, error) {
+	return copyCategories(r, fromMonthKey, toMonthKey)
+}
+
+func (r *SqliteRepository) GetAllTemplates() ([]domain.RecurringTemplate, error) {
+	rows, err := r.conn().Query(
+		`SELECT template_id, kind, name, budget, amount, currency, interval, start_month, end_month
+		 FROM recurring_templates ORDER BY position`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	templates := []domain.RecurringTemplate{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var t domain.RecurringTemplate
+		if err := rows.Scan(
+			&t.TemplateID, &t.Kind, &t.Name, &t.Budget, &t.Amount, &t.Currency, &t.Interval, &t.StartMonth, &t.EndMonth,
+		); err != nil {
+			return nil, err
+		}
+		index[t.TemplateID] = len(templates)
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	overrideRows, err := r.conn().Query(`SELECT template_id, month_key, skip, amount, budget FROM recurring_overrides`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = overrideRows.Close() }()
+
+	for overrideRows.Next() {
+		var templateID, monthKey string
+		var override domain.TemplateOverride
+		var amount, budget decimal.NullDecimal
+		if err := overrideRows.Scan(&templateID, &monthKey, &override.Skip, &amount, &budget); err != nil {
+			return nil, err
+		}
+		if amount.Valid {
+			override.Amount = &amount.Decimal
+		}
+		if budget.Valid {
+			override.Budget = &budget.Decimal
+		}
+		i, ok := index[templateID]
+		if !ok {
+			continue
+		}
+		if templates[i].Overrides == nil {
+			templates[i].Overrides = make(map[string]domain.TemplateOverride)
+		}
+		templates[i].Overrides[monthKey] = override
+	}
+	return templates, overrideRows.Err()
+}
+
+func (r *SqliteRepository) AddTemplate(template domain.RecurringTemplate) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM recurring_templates WHERE template_id = ?)`, template.TemplateID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return &domain.AlreadyExistsError{Record: domain.RecordTemplate, ID: template.TemplateID}
+		}
+		_, err := tx.Exec(
+			`INSERT INTO recurring_templates
+			 (template_id, kind, name, budget, amount, currency, interval, start_month, end_month, position)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM recurring_templates))`,
+			template.TemplateID, template.Kind, template.Name, template.Budget, template.Amount, template.Currency,
+			template.Interval, template.StartMonth, template.EndMonth,
+		)
+		if err != nil {
+			return err
+		}
+		return insertTemplateOverrides(tx, template)
+	})
+}
+
+func (r *SqliteRepository) UpdateTemplate(template domain.RecurringTemplate) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`UPDATE recurring_templates
+			 SET kind = ?, name = ?, budget = ?, amount = ?, currency = ?, interval = ?, start_month = ?, end_month = ?
+			 WHERE template_id = ?`,
+			template.Kind, template.Name, template.Budget, template.Amount, template.Currency,
+			template.Interval, template.StartMonth, template.EndMonth, template.TemplateID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordTemplate, ID: template.TemplateID}
+		}
+		if _, err := tx.Exec(`DELETE FROM recurring_overrides WHERE template_id = ?`, template.TemplateID); err != nil {
+			return err
+		}
+		return insertTemplateOverrides(tx, template)
+	})
+}
+
+func (r *SqliteRepository) DeleteTemplate(templateID string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM recurring_templates WHERE template_id = ?`, templateID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return &domain.NotFoundError{Record: domain.RecordTemplate, ID: templateID}
+		}
+		return nil
+	})
+}
+
+func (r *SqliteRepository) GetAppliedTemplates(monthKey string) ([]string, error) {
+	rows, err := r.conn().Query(`SELECT template_id FROM applied_templates WHERE month_key = ?`, monthKey)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := []string{}
+	for rows.Next() {
+		var templateID string
+		if err := rows.Scan(&templateID); err != nil {
+			return nil, err
+		}
+		applied = append(applied, templateID)
+	}
+	return applied, rows.Err()
+}
+
+func (r *SqliteRepository) MarkTemplateApplied(monthKey string, templateID string) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		if err := ensureMonth(tx, monthKey); err != nil {
+			return err
+		}
+		_, err := tx.Exec(
+			`INSERT OR IGNORE INTO applied_templates (month_key, template_id) VALUES (?, ?)`, monthKey, templateID,
+		)
+		return err
+	})
+}
+
+// insertTemplateOverrides stores the overridden occurrences of a recurring template.
+func insertTemplateOverrides(tx *sql.Tx, template domain.RecurringTemplate) error {
+	for monthKey, override := range template.Overrides {
+		var amount, budget decimal.NullDecimal
+		if override.Amount != nil {
+			amount = decimal.NewNullDecimal(*override.Amount)
+		}
+		if override.Budget != nil {
+			budget = decimal.NewNullDecimal(*override.Budget)
+		}
+		_, err := tx.Exec(
+			`INSERT INTO recurring_overrides (template_id, month_key, skip, amount, budget) VALUES (?, ?, ?, ?, ?)`,
+			template.TemplateID, monthKey, override.Skip, amount, budget,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save override of template %s: %w", template.TemplateID, err)
+		}
+	}
+	return nil
+}
+
+func (r *SqliteRepository) GetMonths() ([]domain.Month, error) {
+	rows, err := r.conn().Query(
+		`SELECT month_key FROM months m
+		 WHERE EXISTS (SELECT 1 FROM categories c WHERE c.month_key = m.month_key)
+		    OR EXISTS (SELECT 1 FROM incomes i WHERE i.month_key = m.month_key)
+		    OR EXISTS (SELECT 1 FROM exchange_rates e WHERE e.month_key = m.month_key)
+		 ORDER BY month_key`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var months []domain.Month
+	for rows.Next() {
+		var monthKey string
+		if err := rows.Scan(&monthKey); err != nil {
+			return nil, err
+		}
+		month, err := domain.ParseMonth(monthKey)
+		if err != nil {
+			continue
+		}
+		months = append(months, month)
+	}
+	return months, rows.Err()
+}
+
+func (r *SqliteRepository) GetMonthRange(from, to domain.Month) ([]domain.MonthData, error) {
+	months, err := r.GetMonths()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.MonthData
+	for _, month := range months {
+		if month.Before(from) || month.After(to) {
+			continue
+		}
+		monthKey := month.String()
+		data := domain.MonthData{Month: month}
+		if data.Incomes, err = r.GetIncomesForMonth(monthKey); err != nil {
+			return nil, err
+		}
+		if data.Categories, err = r.GetCategoriesForMonth(monthKey); err != nil {
+			return nil, err
+		}
+		if data.Rates, err = r.GetRatesForMonth(monthKey); err != nil {
+			return nil, err
+		}
+		if data.AppliedTemplates, err = r.GetAppliedTemplates(monthKey); err != nil {
+			return nil, err
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
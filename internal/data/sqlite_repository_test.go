@@ -0,0 +1,470 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestSqliteRepo is a helper function to create a new repository in a temporary directory for testing.
+func setupTestSqliteRepo(t *testing.T) *SqliteRepository {
+	t.Helper()
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "test_data.db")
+
+	repo, err := NewSqliteRepository(filePath, "USD")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo
+}
+
+func TestSqliteRepository_GroupOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	group1 := domain.CategoryGroup{GroupID: "g1", GroupName: "Group 1", Order: 1}
+	group2 := domain.CategoryGroup{GroupID: "g2", GroupName: "Group 2", Order: 2}
+
+	t.Run("Add and Get Group", func(t *testing.T) {
+		// Add
+		err := repo.AddGroup(group1)
+		require.NoError(t, err)
+		err = repo.AddGroup(group2)
+		require.NoError(t, err)
+
+		// GetGroupByID
+		retrieved, err := repo.GetGroupByID("g1")
+		require.NoError(t, err)
+		assert.Equal(t, group1, retrieved)
+
+		// GetAllGroups
+		all, err := repo.GetAllGroups()
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+		assert.Equal(t, "Group 1", all[0].GroupName) // Check order
+	})
+
+	t.Run("Update Group", func(t *testing.T) {
+		updatedGroup := domain.CategoryGroup{GroupID: "g1", GroupName: "Group 1 Updated", Order: 1}
+		err := repo.UpdateGroup(updatedGroup)
+		require.NoError(t, err)
+
+		retrieved, err := repo.GetGroupByID("g1")
+		require.NoError(t, err)
+		assert.Equal(t, "Group 1 Updated", retrieved.GroupName)
+	})
+
+	t.Run("Delete Group", func(t *testing.T) {
+		// Test delete failure when in use
+		cat := domain.Category{CatID: "c1", GroupID: "g2", CategoryName: "Test Cat"}
+		err := repo.AddCategory("2024-05", cat)
+		require.NoError(t, err)
+
+		err = repo.DeleteGroup("g2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "group is still being used")
+
+		// Test successful delete
+		err = repo.DeleteGroup("g1")
+		require.NoError(t, err)
+
+		_, err = repo.GetGroupByID("g1")
+		assert.Error(t, err)
+
+		all, err := repo.GetAllGroups()
+		require.NoError(t, err)
+		assert.Len(t, all, 1)
+	})
+}
+
+func TestSqliteRepository_IncomeOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	monthKey := "2024-06"
+	income1 := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5000)}
+	income2 := domain.IncomeRecord{IncomeID: "i2", Description: "Freelance", Amount: decimal.NewFromInt(1000)}
+
+	t.Run("Add and Get Income", func(t *testing.T) {
+		err := repo.AddIncome(monthKey, income1)
+		require.NoError(t, err)
+		err = repo.AddIncome(monthKey, income2)
+		require.NoError(t, err)
+
+		incomes, err := repo.GetIncomesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Len(t, incomes, 2)
+	})
+
+	t.Run("Update Income", func(t *testing.T) {
+		updatedIncome := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5500)}
+		err := repo.UpdateIncome(monthKey, updatedIncome)
+		require.NoError(t, err)
+
+		incomes, err := repo.GetIncomesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Equal(t, "5500", incomes[0].Amount.String())
+	})
+
+	t.Run("Delete Income", func(t *testing.T) {
+		err := repo.DeleteIncome(monthKey, "i2")
+		require.NoError(t, err)
+
+		incomes, err := repo.GetIncomesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Len(t, incomes, 1)
+		assert.Equal(t, "i1", incomes[0].IncomeID)
+	})
+}
+
+func TestSqliteRepository_CategoryOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	monthKey := "2024-07"
+	cat1 := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}
+
+	t.Run("Add and Get Category", func(t *testing.T) {
+		err := repo.AddCategory(monthKey, cat1)
+		require.NoError(t, err)
+
+		cats, err := repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Len(t, cats, 1)
+		assert.Equal(t, "Rent", cats[0].CategoryName)
+	})
+
+	t.Run("Update Category", func(t *testing.T) {
+		updatedCat := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Mortgage"}
+		err := repo.UpdateCategory(monthKey, updatedCat)
+		require.NoError(t, err)
+
+		cats, err := repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Equal(t, "Mortgage", cats[0].CategoryName)
+	})
+
+	t.Run("Update Category Expense", func(t *testing.T) {
+		expense := domain.ExpenseRecord{Budget: decimal.NewFromInt(1200), Amount: decimal.RequireFromString("1150.5"), Status: "Paid", Notes: "Paid early"}
+		updatedCat := domain.Category{
+			CatID:        "c1",
+			GroupID:      "g1",
+			CategoryName: "Mortgage",
+			Expense:      map[string]domain.ExpenseRecord{"c1": expense},
+		}
+		err := repo.UpdateCategory(monthKey, updatedCat)
+		require.NoError(t, err)
+
+		cats, err := repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Equal(t, expense, cats[0].Expense["c1"])
+	})
+
+	t.Run("Update Category Expense Entries", func(t *testing.T) {
+		expense := domain.ExpenseRecord{
+			Budget: decimal.NewFromInt(60),
+			Amount: decimal.RequireFromString("55.25"),
+			Status: "Not Paid",
+			Entries: []domain.ExpenseEntry{
+				{EntryID: "e1", Date: time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC), Description: "Market", Amount: decimal.NewFromInt(40)},
+				{EntryID: "e2", Date: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), Description: "Bakery", Amount: decimal.RequireFromString("15.25")},
+			},
+		}
+		updatedCat := domain.Category{
+			CatID:        "c1",
+			GroupID:      "g1",
+			CategoryName: "Mortgage",
+			Expense:      map[string]domain.ExpenseRecord{"c1": expense},
+		}
+		err := repo.UpdateCategory(monthKey, updatedCat)
+		require.NoError(t, err)
+
+		cats, err := repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Equal(t, expense, cats[0].Expense["c1"])
+
+		// Removing the entries removes their rows as well
+		expense.Entries = nil
+		updatedCat.Expense["c1"] = expense
+		require.NoError(t, repo.UpdateCategory(monthKey, updatedCat))
+
+		cats, err = repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Empty(t, cats[0].Expense["c1"].Entries)
+	})
+
+	t.Run("Update Unknown Month", func(t *testing.T) {
+		err := repo.UpdateCategory("January-1999", cat1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no data found for month")
+	})
+
+	t.Run("Delete Category", func(t *testing.T) {
+		err := repo.DeleteCategory(monthKey, "c1")
+		require.NoError(t, err)
+
+		cats, err := repo.GetCategoriesForMonth(monthKey)
+		require.NoError(t, err)
+		assert.Empty(t, cats)
+	})
+}
+
+func TestSqliteRepository_CopyFromMonth(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	fromMonth := "2024-08"
+	toMonth := "2024-09"
+	cat1 := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Utilities", Expense: map[string]domain.ExpenseRecord{"c1": {Amount: decimal.NewFromInt(100)}}}
+	cat2 := domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Groceries"}
+
+	err := repo.AddCategory(fromMonth, cat1)
+	require.NoError(t, err)
+	err = repo.AddCategory(fromMonth, cat2)
+	require.NoError(t, err)
+
+	count, err := repo.CopyCategoriesFromMonth(fromMonth, toMonth)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	newCats, err := repo.GetCategoriesForMonth(toMonth)
+	require.NoError(t, err)
+	assert.Len(t, newCats, 2)
+	// Verify that expenses are reset
+	assert.Empty(t, newCats[0].Expense)
+}
+
+func TestSqliteRepository_Persistence(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "persistent_data.db")
+
+	// Create and modify repo1
+	repo1, err := NewSqliteRepository(filePath, "EUR")
+	require.NoError(t, err)
+	err = repo1.AddGroup(domain.CategoryGroup{GroupID: "p1", GroupName: "Persistent Group"})
+	require.NoError(t, err)
+	require.NoError(t, repo1.Close())
+
+	// Create repo2 from the same file
+	repo2, err := NewSqliteRepository(filePath, "EUR")
+	require.NoError(t, err)
+	defer func() { _ = repo2.Close() }()
+
+	// Check if repo2 loaded the data saved by repo1
+	groups, err := repo2.GetAllGroups()
+	require.NoError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "Persistent Group", groups[0].GroupName)
+
+	// Verify the file was actually created and has content
+	fileInfo, err := os.Stat(filePath)
+	require.NoError(t, err)
+	assert.Greater(t, fileInfo.Size(), int64(0))
+}
+
+func TestSqliteRepository_MigrateLegacyAmounts(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "legacy_data.db")
+
+	// Create a database with the original REAL amount columns
+	db, err := sql.Open("sqlite", filePath)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+CREATE TABLE months (month_key TEXT PRIMARY KEY);
+CREATE TABLE categories (
+	month_key TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	cat_id TEXT NOT NULL, group_id TEXT NOT NULL, category_name TEXT NOT NULL, position INTEGER NOT NULL,
+	PRIMARY KEY (month_key, cat_id)
+);
+CREATE TABLE expenses (
+	month_key TEXT NOT NULL, cat_id TEXT NOT NULL, expense_key TEXT NOT NULL,
+	budget REAL NOT NULL DEFAULT 0, amount REAL NOT NULL DEFAULT 0,
+	status TEXT NOT NULL DEFAULT '', notes TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (month_key, cat_id, expense_key),
+	FOREIGN KEY (month_key, cat_id) REFERENCES categories(month_key, cat_id) ON DELETE CASCADE
+);
+CREATE TABLE incomes (
+	month_key TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	income_id TEXT NOT NULL, description TEXT NOT NULL, amount REAL NOT NULL DEFAULT 0, position INTEGER NOT NULL,
+	PRIMARY KEY (month_key, income_id)
+);
+INSERT INTO months VALUES ('May-2024');
+INSERT INTO categories VALUES ('May-2024', 'c1', 'g1', 'Rent', 1);
+INSERT INTO expenses VALUES ('May-2024', 'c1', 'c1', 0.3, 0.2, 'Paid', '');
+INSERT INTO incomes VALUES ('May-2024', 'i1', 'Salary', 0.1, 1);
+`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	repo, err := NewSqliteRepository(filePath, "USD")
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	incomes, err := repo.GetIncomesForMonth("2024-05")
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	cats, err := repo.GetCategoriesForMonth("2024-05")
+	require.NoError(t, err)
+	require.Len(t, cats, 1)
+
+	expense := cats[0].Expense["c1"]
+	assert.Equal(t, "0.2", expense.Amount.String())
+	assert.True(t, incomes[0].Amount.Add(expense.Amount).Equal(expense.Budget))
+
+	// Cascading deletes still reach the rebuilt expenses table
+	require.NoError(t, repo.DeleteCategory("2024-05", "c1"))
+	var count int
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*) FROM expenses`).Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestSqliteRepository_MonthOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	require.NoError(t, repo.AddIncome("2024-11", domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(100)}))
+	require.NoError(t, repo.AddCategory("2025-01", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}))
+	require.NoError(t, repo.SetRate("2024-03", domain.ExchangeRate{Currency: "EUR", Rate: decimal.NewFromInt(1)}))
+	require.NoError(t, repo.AddCategory("2024-12", domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Gifts"}))
+	require.NoError(t, repo.DeleteCategory("2024-12", "c2"))
+
+	months, err := repo.GetMonths()
+	require.NoError(t, err)
+	var keys []string
+	for _, month := range months {
+		keys = append(keys, month.String())
+	}
+	assert.Equal(t, []string{"2024-03", "2024-11", "2025-01"}, keys)
+
+	data, err := repo.GetMonthRange(domain.NewMonth(2024, time.April), domain.NewMonth(2025, time.January))
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, "2024-11", data[0].Month.String())
+	require.Len(t, data[0].Incomes, 1)
+	assert.Equal(t, "Salary", data[0].Incomes[0].Description)
+	require.Len(t, data[1].Categories, 1)
+	assert.Equal(t, "Rent", data[1].Categories[0].CategoryName)
+}
+
+func TestSqliteRepository_RateOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	monthKey := "2024-06"
+
+	err := repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.08")})
+	require.NoError(t, err)
+	err = repo.SetRate(monthKey, domain.ExchangeRate{Currency: "GBP", Rate: decimal.RequireFromString("1.27")})
+	require.NoError(t, err)
+
+	// Setting an existing currency replaces its rate
+	err = repo.SetRate(monthKey, domain.ExchangeRate{Currency: "EUR", Rate: decimal.RequireFromString("1.1")})
+	require.NoError(t, err)
+
+	rates, err := repo.GetRatesForMonth(monthKey)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "EUR", rates[0].Currency)
+	assert.Equal(t, "1.1", rates[0].Rate.String())
+
+	require.NoError(t, repo.DeleteRate(monthKey, "GBP"))
+	rates, err = repo.GetRatesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Len(t, rates, 1)
+
+	assert.Error(t, repo.DeleteRate(monthKey, "GBP"))
+
+	// Record currencies are stored with the records
+	income := domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(100), Currency: "EUR"}
+	require.NoError(t, repo.AddIncome(monthKey, income))
+	incomes, err := repo.GetIncomesForMonth(monthKey)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", incomes[0].Currency)
+}
+
+func TestSqliteRepository_MigrateCurrencies(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "v1_data.db")
+
+	// Create a version 1 database without currency columns
+	db, err := sql.Open("sqlite", filePath)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+CREATE TABLE months (month_key TEXT PRIMARY KEY);
+CREATE TABLE incomes (
+	month_key TEXT NOT NULL REFERENCES months(month_key) ON DELETE CASCADE,
+	income_id TEXT NOT NULL, description TEXT NOT NULL, amount TEXT NOT NULL DEFAULT '0', position INTEGER NOT NULL,
+	PRIMARY KEY (month_key, income_id)
+);
+INSERT INTO months VALUES ('May-2024');
+INSERT INTO incomes VALUES ('May-2024', 'i1', 'Salary', '1500.50', 1);
+PRAGMA user_version = 1;
+`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	repo, err := NewSqliteRepository(filePath, "USD")
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	incomes, err := repo.GetIncomesForMonth("2024-05")
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	assert.Equal(t, "1500.5", incomes[0].Amount.String())
+	assert.Empty(t, incomes[0].Currency)
+
+	var version int
+	require.NoError(t, repo.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	assert.Equal(t, sqliteSchemaVersion, version)
+}
+
+func TestSqliteRepository_RefusesNewerVersion(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "newer_data.db")
+	db, err := sql.Open("sqlite", filePath)
+	require.NoError(t, err)
+	_, err = db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, sqliteSchemaVersion+1))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = NewSqliteRepository(filePath, "USD")
+	assert.ErrorIs(t, err, ErrNewerDataFile)
+}
+
+func TestSqliteRepository_TemplateOperations(t *testing.T) {
+	repo := setupTestSqliteRepo(t)
+	amount := decimal.NewFromInt(1300)
+	template := domain.RecurringTemplate{
+		TemplateID: "t1",
+		Kind:       domain.TemplateExpense,
+		Name:       "Rent",
+		Amount:     decimal.NewFromInt(1200),
+		Budget:     decimal.NewFromInt(1200),
+		Interval:   1,
+		StartMonth: "2024-01",
+	}
+
+	require.NoError(t, repo.AddTemplate(template))
+	assert.Error(t, repo.AddTemplate(template))
+
+	template.EndMonth = "2024-12"
+	template.Overrides = map[string]domain.TemplateOverride{
+		"2024-03": {Amount: &amount},
+		"2024-04": {Skip: true},
+	}
+	require.NoError(t, repo.UpdateTemplate(template))
+
+	templates, err := repo.GetAllTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "2024-12", templates[0].EndMonth)
+	require.NotNil(t, templates[0].Overrides["2024-03"].Amount)
+	assert.Equal(t, "1300", templates[0].Overrides["2024-03"].Amount.String())
+	assert.Nil(t, templates[0].Overrides["2024-03"].Budget)
+	assert.True(t, templates[0].Overrides["2024-04"].Skip)
+
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	require.NoError(t, repo.MarkTemplateApplied("2024-03", "t1"))
+	applied, err := repo.GetAppliedTemplates("2024-03")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, applied)
+
+	require.NoError(t, repo.DeleteTemplate("t1"))
+	assert.Error(t, repo.DeleteTemplate("t1"))
+	templates, err = repo.GetAllTemplates()
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
@@ -0,0 +1,191 @@
+// Package demo seeds a repository with realistic sample data, so that every
+// view of the interface can be explored without touching real data.
+package demo
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Years is the number of years of data seeded, ending with the current month.
+const Years = 3
+
+// Yearly growth of prices and of the salary.
+const (
+	priceGrowth  = 0.03
+	salaryGrowth = 0.04
+)
+
+// category describes a seeded category. Amounts vary around Budget by up to
+// Variation, and are spent in the months of Peaks at twice the budget.
+type category struct {
+	Group     string
+	Name      string
+	Budget    float64
+	Variation float64
+	Peaks     []time.Month
+	Note      string
+	Entries   []string // Descriptions of the entries the amount is split into
+}
+
+var seedGroups = []string{"Housing", "Living", "Leisure", "Savings"}
+
+var seedCategories = []category{
+	{Group: "Housing", Name: "Rent", Budget: 1200},
+	{Group: "Housing", Name: "Utilities", Budget: 180, Variation: 0.25, Peaks: []time.Month{time.January, time.February}, Note: "Heating season"},
+	{Group: "Housing", Name: "Internet", Budget: 50},
+	{Group: "Living", Name: "Groceries", Budget: 550, Variation: 0.2, Entries: []string{"Supermarket", "Farmers market", "Supermarket", "Bakery"}},
+	{Group: "Living", Name: "Transport", Budget: 120, Variation: 0.3},
+	{Group: "Living", Name: "Phone", Budget: 35},
+	{Group: "Leisure", Name: "Dining out", Budget: 200, Variation: 0.5, Peaks: []time.Month{time.December}, Note: "Holiday dinners"},
+	{Group: "Leisure", Name: "Subscriptions", Budget: 30},
+	{Group: "Leisure", Name: "Travel", Budget: 150, Variation: 0.5, Peaks: []time.Month{time.July, time.August}, Note: "Summer holiday"},
+	{Group: "Savings", Name: "Emergency fund", Budget: 300},
+	{Group: "Savings", Name: "Investments", Budget: 400},
+}
+
+// Seed fills repo with Years of groups, categories, expenses, incomes,
+// exchange rates and recurring templates ending with through. currency is
+// the default currency; some incomes are paid in another one. Amounts only
+// depend on through, so every demo of a month shows the same figures.
+func Seed(repo data.Repository, through domain.Month, currency string) error {
+	rng := rand.New(rand.NewPCG(uint64(through.Year), uint64(through.Month)))
+	foreign := "EUR"
+	if domain.NormalizeCurrency(currency) == foreign {
+		foreign = "USD"
+	}
+
+	groupIDs := make(map[string]string, len(seedGroups))
+	for i, name := range seedGroups {
+		group := domain.CategoryGroup{GroupID: uuid.NewString(), Order: i + 1, GroupName: name}
+		if err := repo.AddGroup(group); err != nil {
+			return fmt.Errorf("failed to seed group %s: %w", name, err)
+		}
+		groupIDs[name] = group.GroupID
+	}
+	// Categories keep their ID from month to month, as when copied
+	categoryIDs := make([]string, len(seedCategories))
+	for i := range seedCategories {
+		categoryIDs[i] = uuid.NewString()
+	}
+
+	first := through.AddMonths(1 - Years*12)
+	salaryID, rentID := uuid.NewString(), uuid.NewString()
+	var salary, rent decimal.Decimal
+
+	for month := first; !month.After(through); month = month.Next() {
+		monthKey := month.String()
+		years := float64(first.MonthsUntil(month)) / 12
+		prices := math.Pow(1+priceGrowth, math.Floor(years))
+		current := month == through
+
+		for i, c := range seedCategories {
+			expense := seedExpense(rng, month, c, prices, current)
+			if c.Name == "Rent" {
+				rent = expense.Budget
+			}
+			seeded := domain.Category{
+				CatID:        categoryIDs[i],
+				GroupID:      groupIDs[c.Group],
+				CategoryName: c.Name,
+				Expense:      map[string]domain.ExpenseRecord{categoryIDs[i]: expense},
+			}
+			if err := repo.AddCategory(monthKey, seeded); err != nil {
+				return fmt.Errorf("failed to seed category %s of %s: %w", c.Name, monthKey, err)
+			}
+		}
+
+		salary = money(4200 * math.Pow(1+salaryGrowth, math.Floor(years)))
+		incomes := []domain.IncomeRecord{{IncomeID: uuid.NewString(), Description: "Salary", Amount: salary}}
+		if month.Month == time.December {
+			incomes = append(incomes, domain.IncomeRecord{IncomeID: uuid.NewString(), Description: "Year-end bonus", Amount: salary.Div(decimal.NewFromInt(2)).Round(0)})
+		}
+		if first.MonthsUntil(month)%3 == 1 {
+			incomes = append(incomes, domain.IncomeRecord{
+				IncomeID:    uuid.NewString(),
+				Description: "Freelance project",
+				Amount:      money(600 + 300*rng.Float64()),
+				Currency:    foreign,
+			})
+			rate := domain.ExchangeRate{Currency: foreign, Rate: decimal.NewFromFloat(1.05 + 0.1*rng.Float64()).Round(4)}
+			if err := repo.SetRate(monthKey, rate); err != nil {
+				return fmt.Errorf("failed to seed exchange rate of %s: %w", monthKey, err)
+			}
+		}
+		for _, income := range incomes {
+			if err := repo.AddIncome(monthKey, income); err != nil {
+				return fmt.Errorf("failed to seed income %s of %s: %w", income.Description, monthKey, err)
+			}
+		}
+
+		for _, templateID := range []string{salaryID, rentID} {
+			if err := repo.MarkTemplateApplied(monthKey, templateID); err != nil {
+				return err
+			}
+		}
+	}
+
+	templates := []domain.RecurringTemplate{
+		{TemplateID: salaryID, Kind: domain.TemplateIncome, Name: "Salary", Amount: salary, Interval: 1, StartMonth: first.String()},
+		{TemplateID: rentID, Kind: domain.TemplateExpense, Name: "Rent", Budget: rent, Amount: rent, Interval: 1, StartMonth: first.String()},
+	}
+	for _, template := range templates {
+		if err := repo.AddTemplate(template); err != nil {
+			return fmt.Errorf("failed to seed recurring template %s: %w", template.Name, err)
+		}
+	}
+	return nil
+}
+
+// seedExpense returns the expense of c in month. Expenses of past months are
+// paid, while in the current month only fixed expenses are paid and those
+// split into entries are partly spent.
+func seedExpense(rng *rand.Rand, month domain.Month, c category, prices float64, current bool) domain.ExpenseRecord {
+	budget := c.Budget * prices
+	amount := budget * (1 + c.Variation*(2*rng.Float64()-1))
+	expense := domain.ExpenseRecord{Budget: money(budget), Status: "Paid"}
+	for _, peak := range c.Peaks {
+		if month.Month == peak {
+			amount = 2 * budget
+			expense.Notes = c.Note
+		}
+	}
+
+	entries := c.Entries
+	if current && c.Variation > 0 {
+		expense.Status = "Not Paid"
+		amount /= 2
+		entries = entries[:len(entries)/2]
+	}
+	expense.Amount = money(amount)
+
+	if len(entries) > 0 {
+		remaining := expense.Amount
+		for i, description := range entries {
+			part := expense.Amount.Div(decimal.NewFromInt(int64(len(entries)))).Round(2)
+			if i == len(entries)-1 {
+				part = remaining
+			}
+			remaining = remaining.Sub(part)
+			expense.Entries = append(expense.Entries, domain.ExpenseEntry{
+				EntryID:     uuid.NewString(),
+				Date:        time.Date(month.Year, month.Month, 3+7*i, 0, 0, 0, 0, time.Local),
+				Description: description,
+				Amount:      part,
+			})
+		}
+	}
+	return expense
+}
+
+// money rounds amount to cents.
+func money(amount float64) decimal.Decimal {
+	return decimal.NewFromFloat(amount).Round(2)
+}
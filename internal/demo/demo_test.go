@@ -0,0 +1,86 @@
+package demo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	repo := data.NewMemoryRepository()
+	through := domain.NewMonth(2025, time.June)
+	require.NoError(t, Seed(repo, through, "USD"))
+
+	months, err := repo.GetMonths()
+	require.NoError(t, err)
+	require.Len(t, months, Years*12)
+	assert.Equal(t, "2022-07", months[0].String())
+	assert.Equal(t, through, months[len(months)-1])
+
+	groups, err := repo.GetAllGroups()
+	require.NoError(t, err)
+	assert.Len(t, groups, len(seedGroups))
+
+	t.Run("Past months are paid", func(t *testing.T) {
+		categories, err := repo.GetCategoriesForMonth("2024-12")
+		require.NoError(t, err)
+		require.Len(t, categories, len(seedCategories))
+		for _, category := range categories {
+			expense, ok := category.Expense[category.CatID]
+			require.True(t, ok, category.CategoryName)
+			assert.Equal(t, "Paid", expense.Status)
+			assert.True(t, expense.Amount.IsPositive())
+			if expense.HasEntries() {
+				assert.True(t, expense.Amount.Equal(expense.EntriesTotal()), category.CategoryName)
+			}
+		}
+	})
+
+	t.Run("Current month is in progress", func(t *testing.T) {
+		categories, err := repo.GetCategoriesForMonth(through.String())
+		require.NoError(t, err)
+		statuses := map[string]int{}
+		for _, category := range categories {
+			statuses[category.Expense[category.CatID].Status]++
+		}
+		assert.Positive(t, statuses["Paid"])
+		assert.Positive(t, statuses["Not Paid"])
+	})
+
+	t.Run("Foreign incomes have rates", func(t *testing.T) {
+		for _, month := range months {
+			incomes, err := repo.GetIncomesForMonth(month.String())
+			require.NoError(t, err)
+			rates, err := repo.GetRatesForMonth(month.String())
+			require.NoError(t, err)
+			converter := domain.NewConverter("USD", rates)
+			for _, income := range incomes {
+				_, ok := converter.Convert(income.Amount, income.Currency, "USD")
+				assert.True(t, ok, "%s of %s", income.Description, month)
+			}
+		}
+	})
+
+	t.Run("Templates are applied to seeded months only", func(t *testing.T) {
+		recurring := service.NewRecurringService(repo, repo, repo)
+		count, err := recurring.Materialize(through.String())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		next := through.Next().String()
+		count, err = recurring.Materialize(next)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// The rent is filled in once the categories are copied
+		_, err = repo.CopyCategoriesFromMonth(through.String(), next)
+		require.NoError(t, err)
+		count, err = recurring.Materialize(next)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
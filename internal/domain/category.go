@@ -8,6 +8,22 @@ type Category struct {
 	Expense      map[string]ExpenseRecord `json:"expense"`
 }
 
+// Clone returns a copy of the category that shares no expenses with it.
+func (c Category) Clone() Category {
+	if c.Expense == nil {
+		return c
+	}
+	expenses := make(map[string]ExpenseRecord, len(c.Expense))
+	for id, expense := range c.Expense {
+		if expense.Entries != nil {
+			expense.Entries = append([]ExpenseEntry{}, expense.Entries...)
+		}
+		expenses[id] = expense
+	}
+	c.Expense = expenses
+	return c
+}
+
 // CategoryRepository defines the interface for interacting with category data.
 type CategoryRepository interface {
 	GetCategoriesForMonth(monthKey string) ([]Category, error)
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate is the value of one unit of Currency expressed in the default currency.
+type ExchangeRate struct {
+	Currency string          `json:"currency"`
+	Rate     decimal.Decimal `json:"rate"`
+}
+
+// RateRepository defines the interface for interacting with the exchange rates of a month.
+type RateRepository interface {
+	GetRatesForMonth(monthKey string) ([]ExchangeRate, error)
+	SetRate(monthKey string, rate ExchangeRate) error
+	DeleteRate(monthKey string, currency string) error
+}
+
+// NormalizeCurrency returns the canonical form of a currency code.
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// Converter converts amounts between currencies using the exchange rates of a month.
+type Converter struct {
+	base  string
+	rates map[string]decimal.Decimal
+}
+
+// NewConverter creates a Converter for rates expressed in the base currency.
+func NewConverter(base string, rates []ExchangeRate) Converter {
+	c := Converter{
+		base:  NormalizeCurrency(base),
+		rates: make(map[string]decimal.Decimal, len(rates)),
+	}
+	for _, rate := range rates {
+		c.rates[NormalizeCurrency(rate.Currency)] = rate.Rate
+	}
+	return c
+}
+
+// Base returns the currency the rates are expressed in.
+func (c Converter) Base() string {
+	return c.base
+}
+
+// Currency returns the currency of a record, which is the base currency when unset.
+func (c Converter) Currency(currency string) string {
+	if currency = NormalizeCurrency(currency); currency == "" {
+		return c.base
+	}
+	return currency
+}
+
+// Convert converts amount from one currency into another. It reports false
+// when the rate of either currency is unknown.
+func (c Converter) Convert(amount decimal.Decimal, from, to string) (decimal.Decimal, bool) {
+	from, to = c.Currency(from), c.Currency(to)
+	if from == to {
+		return amount, true
+	}
+
+	fromRate, ok := c.rate(from)
+	if !ok {
+		return decimal.Zero, false
+	}
+	toRate, ok := c.rate(to)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return amount.Mul(fromRate).Div(toRate), true
+}
+
+// rate returns the rate of currency, the base currency having a rate of one.
+func (c Converter) rate(currency string) (decimal.Decimal, bool) {
+	if currency == c.base {
+		return decimal.NewFromInt(1), true
+	}
+	rate, ok := c.rates[currency]
+	if !ok || !rate.IsPositive() {
+		return decimal.Zero, false
+	}
+	return rate, true
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of errors returned by every repository and service. They are
+// matched with errors.Is, while the typed errors below carry the details.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrInUse         = errors.New("in use")
+	ErrInvalid       = errors.New("invalid")
+)
+
+// recordNames are the names of the kinds of records in error messages.
+var recordNames = map[string]string{
+	RecordRate:     "exchange rate",
+	RecordTemplate: "recurring template",
+}
+
+// RecordName returns the name of a kind of record, such as RecordGroup, as
+// shown to users.
+func RecordName(record string) string {
+	if name, ok := recordNames[record]; ok {
+		return name
+	}
+	return record
+}
+
+// PluralRecordName returns the name of several records of a kind, as shown to users.
+func PluralRecordName(record string) string {
+	if record == RecordCategory {
+		return "categories"
+	}
+	return RecordName(record) + "s"
+}
+
+// NotFoundError is returned when a record does not exist. MonthKey is set for
+// the records of a month. ID is empty when the month holds no record of the
+// kind at all, and Record too when it holds no data at all.
+type NotFoundError struct {
+	Record   string
+	ID       string
+	MonthKey string
+}
+
+func (e *NotFoundError) Error() string {
+	switch {
+	case e.Record == "":
+		return fmt.Sprintf("no data found for month %s", e.MonthKey)
+	case e.ID == "":
+		return fmt.Sprintf("no %s found in %s", PluralRecordName(e.Record), e.MonthKey)
+	case e.MonthKey != "":
+		return fmt.Sprintf("%s %s not found in %s", RecordName(e.Record), e.ID, e.MonthKey)
+	default:
+		return fmt.Sprintf("%s %s not found", RecordName(e.Record), e.ID)
+	}
+}
+
+// Is reports whether target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AlreadyExistsError is returned when a record is added with the ID of an
+// existing one.
+type AlreadyExistsError struct {
+	Record string
+	ID     string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s with ID %s already exists", RecordName(e.Record), e.ID)
+}
+
+// Is reports whether target is ErrAlreadyExists.
+func (e *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
+
+// InUseError is returned when the record named Name cannot be deleted
+// because records of the kind UsedBy still refer to it.
+type InUseError struct {
+	Record string
+	ID     string
+	Name   string
+	UsedBy string
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("cannot delete %s '%s': %s is still being used by existing %s",
+		RecordName(e.Record), e.Name, RecordName(e.Record), PluralRecordName(e.UsedBy))
+}
+
+// Is reports whether target is ErrInUse.
+func (e *InUseError) Is(target error) bool {
+	return target == ErrInUse
+}
+
+// ValidationError is returned when the value of Field is not acceptable.
+// Message describes the problem in a sentence that names the field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrInvalid.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Fields of records named by validation errors.
+const (
+	FieldID          = "id"
+	FieldKind        = "kind"
+	FieldName        = "name"
+	FieldGroup       = "group"
+	FieldDescription = "description"
+	FieldAmount      = "amount"
+	FieldBudget      = "budget"
+	FieldCurrency    = "currency"
+	FieldNotes       = "notes"
+	FieldEntries     = "entries"
+	FieldRate        = "rate"
+	FieldInterval    = "interval"
+	FieldStartMonth  = "startMonth"
+	FieldEndMonth    = "endMonth"
+)
+
+// ValidationErrors is returned when the values of several fields are not
+// acceptable, with one error per field.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the error of every field, so that errors.As finds the first.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether target is ErrInvalid.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// FieldErrors returns the messages of the validation errors found in err by
+// field, keeping the first message of each field. It returns nil when err
+// holds no validation error.
+func FieldErrors(err error) map[string]string {
+	var fields map[string]string
+	add := func(invalid *ValidationError) {
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		if _, exists := fields[invalid.Field]; !exists {
+			fields[invalid.Field] = invalid.Message
+		}
+	}
+
+	var invalidFields ValidationErrors
+	var invalid *ValidationError
+	switch {
+	case errors.As(err, &invalidFields):
+		for _, e := range invalidFields {
+			add(e)
+		}
+	case errors.As(err, &invalid):
+		add(invalid)
+	}
+	return fields
+}
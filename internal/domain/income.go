@@ -1,10 +1,13 @@
 package domain
 
+import "github.com/shopspring/decimal"
+
 // IncomeRecord represents an income record.
 type IncomeRecord struct {
-	IncomeID    string  `json:"incomeId"`
-	Description string  `json:"description"`
-	Amount      float64 `json:"amount"`
+	IncomeID    string          `json:"incomeId"`
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency,omitempty"` // Empty for the default currency
 }
 
 // IncomeRepository defines the interface for interacting with income data.
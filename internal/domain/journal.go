@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of records found in the change journal.
+const (
+	RecordGroup    = "group"
+	RecordCategory = "category"
+	RecordExpense  = "expense"
+	RecordIncome   = "income"
+	RecordRate     = "rate"
+	RecordTemplate = "template"
+)
+
+// Actions of the changes in the change journal.
+const (
+	ChangeAdd    = "add"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// Change is an entry of the change journal. Before and After hold the record
+// as JSON; Before is empty for additions and After for deletions. Expense
+// changes belong to the category identified by RecordID and Name.
+type Change struct {
+	Time     time.Time       `json:"time"`
+	User     string          `json:"user,omitempty"`
+	Action   string          `json:"action"`
+	Record   string          `json:"record"`
+	MonthKey string          `json:"month,omitempty"`
+	RecordID string          `json:"id"`
+	Name     string          `json:"name"`
+	Before   json.RawMessage `json:"before,omitempty"`
+	After    json.RawMessage `json:"after,omitempty"`
+}
+
+// Summary describes the fields changed by an update, such as
+// "amount: 85 → 90", or the fields of an added or deleted record. Nested
+// objects are left out and lists are described by their length.
+func (c Change) Summary() string {
+	before, after := changeFields(c.Before), changeFields(c.After)
+	if c.Action != ChangeUpdate {
+		fields := after
+		if c.Action == ChangeDelete {
+			fields = before
+		}
+		var parts []string
+		for _, name := range sortedFieldNames(fields) {
+			if value := fields[name]; value != "" && value != c.RecordID && value != c.Name {
+				parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	names := sortedFieldNames(before)
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		if before[name] != after[name] {
+			parts = append(parts, fmt.Sprintf("%s: %s → %s", name, quoteEmpty(before[name]), quoteEmpty(after[name])))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changeFields returns the top-level fields of a record stored in a change.
+func changeFields(record json.RawMessage) map[string]string {
+	fields := make(map[string]string)
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	decoder.UseNumber()
+	var values map[string]any
+	if len(record) == 0 || decoder.Decode(&values) != nil {
+		return fields
+	}
+	for name, value := range values {
+		switch v := value.(type) {
+		case map[string]any:
+			continue
+		case []any:
+			fields[name] = fmt.Sprintf("%d items", len(v))
+		case nil:
+			fields[name] = ""
+		default:
+			fields[name] = fmt.Sprint(v)
+		}
+	}
+	return fields
+}
+
+// sortedFieldNames returns the names of fields in alphabetical order.
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quoteEmpty returns value, or "" quoted when it is empty.
+func quoteEmpty(value string) string {
+	if value == "" {
+		return `""`
+	}
+	return value
+}
+
+// ChangeFilter selects entries of the change journal. Empty fields match
+// every entry.
+type ChangeFilter struct {
+	MonthKey string
+	// Category matches the ID or the name of a category, for changes of
+	// categories and their expenses.
+	Category string
+	// Limit is the maximum number of entries returned, zero for all.
+	Limit int
+}
+
+// Matches reports whether change is selected by the filter.
+func (f ChangeFilter) Matches(change Change) bool {
+	if f.MonthKey != "" && change.MonthKey != f.MonthKey {
+		return false
+	}
+	if f.Category == "" {
+		return true
+	}
+	if change.Record != RecordCategory && change.Record != RecordExpense {
+		return false
+	}
+	return change.RecordID == f.Category || strings.EqualFold(change.Name, strings.TrimSpace(f.Category))
+}
+
+// JournalRepository defines the interface for reading the change journal.
+type JournalRepository interface {
+	// GetChanges returns the entries selected by filter, newest first.
+	GetChanges(filter ChangeFilter) ([]Change, error)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MonthLayout is the layout of the month keys used by the repositories.
+// Keys in this ISO format sort chronologically as plain strings.
+const MonthLayout = "2006-01"
+
+// Month identifies a calendar month. Its String form is the key the data of
+// the month is stored under.
+type Month struct {
+	Year  int
+	Month time.Month
+}
+
+// NewMonth creates a Month, normalizing months outside January to December.
+func NewMonth(year int, month time.Month) Month {
+	return MonthOf(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC))
+}
+
+// MonthOf returns the month t falls in.
+func MonthOf(t time.Time) Month {
+	return Month{Year: t.Year(), Month: t.Month()}
+}
+
+// ParseMonth parses a month key in the "YYYY-MM" format.
+func ParseMonth(value string) (Month, error) {
+	t, err := time.Parse(MonthLayout, strings.TrimSpace(value))
+	if err != nil {
+		return Month{}, fmt.Errorf("invalid month %q, expected YYYY-MM", value)
+	}
+	return MonthOf(t), nil
+}
+
+// String returns the month key in the "YYYY-MM" format.
+func (m Month) String() string {
+	return fmt.Sprintf("%04d-%02d", m.Year, int(m.Month))
+}
+
+// Time returns the first day of the month in UTC.
+func (m Month) Time() time.Time {
+	return time.Date(m.Year, m.Month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// AddMonths returns the month n months after m, or before it when n is negative.
+func (m Month) AddMonths(n int) Month {
+	return NewMonth(m.Year, m.Month+time.Month(n))
+}
+
+// Next returns the month after m.
+func (m Month) Next() Month {
+	return m.AddMonths(1)
+}
+
+// Previous returns the month before m.
+func (m Month) Previous() Month {
+	return m.AddMonths(-1)
+}
+
+// Compare returns -1, 0 or +1 depending on whether m is before, the same as
+// or after other.
+func (m Month) Compare(other Month) int {
+	a, b := m.index(), other.index()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether m is before other.
+func (m Month) Before(other Month) bool {
+	return m.index() < other.index()
+}
+
+// After reports whether m is after other.
+func (m Month) After(other Month) bool {
+	return m.index() > other.index()
+}
+
+// MonthsUntil returns the number of months from m to other, negative when
+// other is before m.
+func (m Month) MonthsUntil(other Month) int {
+	return other.index() - m.index()
+}
+
+// index returns the number of months between year zero and m.
+func (m Month) index() int {
+	return m.Year*12 + int(m.Month) - 1
+}
@@ -1,15 +1,72 @@
 package domain
 
-// ExpenseRecord represents an expense record.
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExpenseEntry represents a single dated transaction that contributes to an expense.
+type ExpenseEntry struct {
+	EntryID     string          `json:"entryId"`
+	Date        time.Time       `json:"date"`
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// ExpenseRecord represents an expense record. When it holds entries, Amount
+// is the sum of their amounts. Budget, Amount and entries share Currency,
+// which is empty for the default currency.
 type ExpenseRecord struct {
-	Budget float64 `json:"budget"`
-	Amount float64 `json:"amount"`
-	Status string  `json:"status"`
-	Notes  string  `json:"notes"`
+	Budget   decimal.Decimal `json:"budget"`
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency,omitempty"`
+	Status   string          `json:"status"`
+	Notes    string          `json:"notes"`
+	Entries  []ExpenseEntry  `json:"entries,omitempty"`
+}
+
+// HasEntries reports whether the expense is made up of individual entries.
+func (e ExpenseRecord) HasEntries() bool {
+	return len(e.Entries) > 0
+}
+
+// EntriesTotal returns the sum of the amounts of all entries.
+func (e ExpenseRecord) EntriesTotal() decimal.Decimal {
+	var total decimal.Decimal
+	for _, entry := range e.Entries {
+		total = total.Add(entry.Amount)
+	}
+	return total
+}
+
+// SyncAmount sets Amount to the sum of the entries, if there are any.
+func (e *ExpenseRecord) SyncAmount() {
+	if e.HasEntries() {
+		e.Amount = e.EntriesTotal()
+	}
 }
 
-// MonthlyRecord holds one or more income and expense records.
+// MonthlyRecord holds one or more income and expense records, together with
+// the IDs of the recurring templates already applied to the month.
 type MonthlyRecord struct {
-	Incomes    []IncomeRecord `json:"incomes"`
-	Categories []Category     `json:"categories"`
+	Incomes          []IncomeRecord `json:"incomes"`
+	Categories       []Category     `json:"categories"`
+	Rates            []ExchangeRate `json:"rates,omitempty"`
+	AppliedTemplates []string       `json:"appliedTemplates,omitempty"`
+}
+
+// MonthData is the record of a month together with the month it belongs to.
+type MonthData struct {
+	Month Month
+	MonthlyRecord
+}
+
+// MonthRepository defines the interface for querying the months that hold data.
+type MonthRepository interface {
+	// GetMonths returns the months holding any data, oldest first.
+	GetMonths() ([]Month, error)
+	// GetMonthRange returns the months from from to to, both included, that
+	// hold any data, oldest first.
+	GetMonthRange(from, to Month) ([]MonthData, error)
 }
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Kinds of recurring templates.
+const (
+	TemplateExpense = "expense"
+	TemplateIncome  = "income"
+)
+
+// TemplateOverride changes a single occurrence of a recurring template.
+// Nil amounts keep the values of the template.
+type TemplateOverride struct {
+	Skip   bool             `json:"skip,omitempty"`
+	Amount *decimal.Decimal `json:"amount,omitempty"`
+	Budget *decimal.Decimal `json:"budget,omitempty"`
+}
+
+// RecurringTemplate describes an expense or income that repeats every
+// Interval months between StartMonth and EndMonth. Expense templates fill in
+// the expense of the category named Name; income templates add an income
+// described by Name. Months are month keys and an empty EndMonth never ends.
+type RecurringTemplate struct {
+	TemplateID string                      `json:"templateId"`
+	Kind       string                      `json:"kind"`
+	Name       string                      `json:"name"`
+	Budget     decimal.Decimal             `json:"budget"`
+	Amount     decimal.Decimal             `json:"amount"`
+	Currency   string                      `json:"currency,omitempty"`
+	Interval   int                         `json:"interval"`
+	StartMonth string                      `json:"startMonth"`
+	EndMonth   string                      `json:"endMonth,omitempty"`
+	Overrides  map[string]TemplateOverride `json:"overrides,omitempty"`
+}
+
+// OccursIn reports whether the template has an occurrence in monthKey that
+// has not been skipped.
+func (t RecurringTemplate) OccursIn(monthKey string) bool {
+	month, err := monthIndex(monthKey)
+	if err != nil {
+		return false
+	}
+	start, err := monthIndex(t.StartMonth)
+	if err != nil || month < start {
+		return false
+	}
+	if t.EndMonth != "" {
+		end, err := monthIndex(t.EndMonth)
+		if err != nil || month > end {
+			return false
+		}
+	}
+	interval := max(t.Interval, 1)
+	if (month-start)%interval != 0 {
+		return false
+	}
+	return !t.Overrides[monthKey].Skip
+}
+
+// ValuesFor returns the amount and budget of the occurrence in monthKey.
+func (t RecurringTemplate) ValuesFor(monthKey string) (amount, budget decimal.Decimal) {
+	amount, budget = t.Amount, t.Budget
+	override := t.Overrides[monthKey]
+	if override.Amount != nil {
+		amount = *override.Amount
+	}
+	if override.Budget != nil {
+		budget = *override.Budget
+	}
+	return amount, budget
+}
+
+// ValidateMonthKey returns an error when monthKey is not a valid month key.
+func ValidateMonthKey(monthKey string) error {
+	_, err := ParseMonth(monthKey)
+	return err
+}
+
+// CompareMonthKeys returns -1, 0 or +1 depending on whether a is before,
+// the same as or after b. Invalid keys sort first.
+func CompareMonthKeys(a, b string) int {
+	am, _ := ParseMonth(a)
+	bm, _ := ParseMonth(b)
+	return am.Compare(bm)
+}
+
+// monthIndex returns the number of months between year zero and monthKey.
+func monthIndex(monthKey string) (int, error) {
+	month, err := ParseMonth(monthKey)
+	if err != nil {
+		return 0, err
+	}
+	return month.index(), nil
+}
+
+// RecurringRepository defines the interface for interacting with recurring
+// templates and with the months they have been applied to.
+type RecurringRepository interface {
+	GetAllTemplates() ([]RecurringTemplate, error)
+	AddTemplate(template RecurringTemplate) error
+	UpdateTemplate(template RecurringTemplate) error
+	DeleteTemplate(templateID string) error
+	GetAppliedTemplates(monthKey string) ([]string, error)
+	MarkTemplateApplied(monthKey string, templateID string) error
+}
@@ -0,0 +1,12 @@
+package domain
+
+// UnitOfWork is implemented by repositories able to save several changes
+// together.
+type UnitOfWork interface {
+	// Atomically runs fn. The changes made through the repository while fn
+	// runs are saved together when it returns nil, and all discarded when it
+	// returns an error. A unit of work started within fn is part of the
+	// running one: its changes are saved with it, but discarded alone when
+	// it fails.
+	Atomically(fn func() error) error
+}
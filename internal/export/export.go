@@ -0,0 +1,190 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Export formats.
+const (
+	FormatCSV       = "csv"
+	FormatJSON      = "json"
+	FormatMarkdown  = "markdown"
+	FormatLedger    = "ledger"
+	FormatBeancount = "beancount"
+)
+
+// writers maps every format to the function rendering a report in it.
+var writers = map[string]func(w io.Writer, report Report) error{
+	FormatCSV:       writeCSV,
+	FormatJSON:      writeJSON,
+	FormatMarkdown:  writeMarkdown,
+	FormatLedger:    writeLedger,
+	FormatBeancount: writeBeancount,
+}
+
+// extensions maps every format to the extension of its files.
+var extensions = map[string]string{
+	FormatCSV:       "csv",
+	FormatJSON:      "json",
+	FormatMarkdown:  "md",
+	FormatLedger:    "ledger",
+	FormatBeancount: "beancount",
+}
+
+// Formats returns the supported formats in alphabetical order.
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Extension returns the file extension used for format.
+func Extension(format string) string {
+	return extensions[format]
+}
+
+// Write renders report to w in the given format.
+func Write(w io.Writer, report Report, format string) error {
+	write, ok := writers[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unknown export format %q, available: %s", format, strings.Join(Formats(), ", "))
+	}
+	return write(w, report)
+}
+
+// writeJSON renders the report as indented JSON.
+func writeJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// writeCSV renders one row per income and category expense.
+func writeCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"month", "type", "group", "name", "budget", "amount", "currency", "status", "notes"})
+	for _, month := range report.Months {
+		for _, income := range month.Incomes {
+			_ = writer.Write([]string{
+				month.Month, "income", "", income.Description, "", income.Amount.StringFixed(2), income.Currency, "", "",
+			})
+		}
+		for _, group := range month.Groups {
+			for _, category := range group.Categories {
+				_ = writer.Write([]string{
+					month.Month, "expense", group.Name, category.Name, category.Budget.StringFixed(2),
+					category.Amount.StringFixed(2), category.Currency, category.Status, category.Notes,
+				})
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown renders a human readable report with budget vs actual tables.
+func writeMarkdown(w io.Writer, report Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# gocost report: %s\n", reportTitle(report))
+
+	for _, month := range report.Months {
+		fmt.Fprintf(&b, "\n## %s\n", monthTitle(month.Month))
+
+		b.WriteString("\n### Income\n\n")
+		if len(month.Incomes) == 0 {
+			b.WriteString("No income entries.\n")
+		} else {
+			b.WriteString("| Description | Amount |\n|---|---:|\n")
+			for _, income := range month.Incomes {
+				fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(income.Description), money(income.Amount, income.Currency))
+			}
+		}
+
+		b.WriteString("\n### Expenses\n")
+		if len(month.Groups) == 0 {
+			b.WriteString("\nNo categories.\n")
+		}
+		for _, group := range month.Groups {
+			fmt.Fprintf(&b, "\n#### %s\n\n", markdownCell(group.Name))
+			b.WriteString("| Category | Budget | Actual | Difference | Status | Notes |\n|---|---:|---:|---:|---|---|\n")
+			for _, category := range group.Categories {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+					markdownCell(category.Name),
+					money(category.Budget, category.Currency),
+					money(category.Amount, category.Currency),
+					money(category.Budget.Sub(category.Amount), category.Currency),
+					category.Status,
+					markdownCell(category.Notes),
+				)
+			}
+			fmt.Fprintf(&b, "| **Total** | **%s** | **%s** | **%s** | | |\n",
+				money(group.Budget, report.Currency),
+				money(group.Spent, report.Currency),
+				money(group.Budget.Sub(group.Spent), report.Currency),
+			)
+		}
+
+		b.WriteString("\n### Summary\n\n")
+		writeMarkdownTotals(&b, month.Totals, report.Currency)
+		if len(month.MissingRates) > 0 {
+			fmt.Fprintf(&b, "\n_Missing exchange rates for %s, left out of the totals._\n", strings.Join(month.MissingRates, ", "))
+		}
+	}
+
+	if len(report.Months) > 1 {
+		b.WriteString("\n## Total\n\n")
+		writeMarkdownTotals(&b, report.Totals, report.Currency)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownTotals renders totals as a two column table.
+func writeMarkdownTotals(b *strings.Builder, totals Totals, currency string) {
+	b.WriteString("| | Amount |\n|---|---:|\n")
+	fmt.Fprintf(b, "| Income | %s |\n", money(totals.Income, currency))
+	fmt.Fprintf(b, "| Budget | %s |\n", money(totals.Budget, currency))
+	fmt.Fprintf(b, "| Spent | %s |\n", money(totals.Spent, currency))
+	fmt.Fprintf(b, "| Balance | %s |\n", money(totals.Balance, currency))
+}
+
+// reportTitle returns the month, or the range of months, of report.
+func reportTitle(report Report) string {
+	if report.From == report.To {
+		return monthTitle(report.From)
+	}
+	return fmt.Sprintf("%s - %s", monthTitle(report.From), monthTitle(report.To))
+}
+
+// monthTitle formats a YYYY-MM month as "January 2024".
+func monthTitle(month string) string {
+	m, err := domain.ParseMonth(month)
+	if err != nil {
+		return month
+	}
+	return m.Time().Format("January 2006")
+}
+
+// money formats an amount followed by its currency.
+func money(amount decimal.Decimal, currency string) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", amount.StringFixed(2), currency))
+}
+
+// markdownCell escapes text for use inside a table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestExporter(t *testing.T) *Exporter {
+	t.Helper()
+	repo, err := data.NewJsonRepository(filepath.Join(t.TempDir(), "test_data.json"), "USD")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Housing", Order: 2}))
+	require.NoError(t, repo.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Living", Order: 1}))
+
+	require.NoError(t, repo.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(3000)}))
+	require.NoError(t, repo.AddIncome("2024-06", domain.IncomeRecord{IncomeID: "i2", Description: "Freelance", Amount: decimal.NewFromInt(100), Currency: "EUR"}))
+	require.NoError(t, repo.AddCategory("2024-06", domain.Category{
+		CatID: "c1", GroupID: "g1", CategoryName: "Rent",
+		Expense: map[string]domain.ExpenseRecord{
+			"c1": {Budget: decimal.NewFromInt(1200), Amount: decimal.NewFromInt(1200), Status: "Paid", Notes: "June | July"},
+		},
+	}))
+	require.NoError(t, repo.AddCategory("2024-06", domain.Category{CatID: "c2", GroupID: "g2", CategoryName: "Groceries"}))
+	require.NoError(t, repo.AddCategory("2024-07", domain.Category{
+		CatID: "c3", GroupID: "missing", CategoryName: "Gifts",
+		Expense: map[string]domain.ExpenseRecord{
+			"c3": {Budget: decimal.NewFromInt(50), Amount: decimal.NewFromInt(20), Status: "Not Paid"},
+		},
+	}))
+
+	return New(service.NewGroupService(repo), service.NewMonthService(repo))
+}
+
+func TestExporter_Build(t *testing.T) {
+	exporter := setupTestExporter(t)
+
+	report, err := exporter.Build(domain.NewMonth(2024, time.July), domain.NewMonth(2024, time.June), "USD", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", report.From)
+	assert.Equal(t, "2024-07", report.To)
+	require.Len(t, report.Months, 2)
+
+	june := report.Months[0]
+	require.Len(t, june.Incomes, 2)
+	assert.Equal(t, "EUR", june.Incomes[1].Currency)
+	assert.Equal(t, []string{"EUR"}, june.MissingRates)
+	assert.Equal(t, "3000", june.Totals.Income.String())
+	require.Len(t, june.Groups, 2)
+	assert.Equal(t, "Living", june.Groups[0].Name)
+	assert.Equal(t, "Not Set", june.Groups[0].Categories[0].Status)
+	assert.Equal(t, "Housing", june.Groups[1].Name)
+	assert.Equal(t, "1800", june.Totals.Balance.String())
+
+	july := report.Months[1]
+	require.Len(t, july.Groups, 1)
+	assert.Equal(t, ungroupedName, july.Groups[0].Name)
+
+	assert.Equal(t, "1250", report.Totals.Budget.String())
+	assert.Equal(t, "1220", report.Totals.Spent.String())
+	assert.Equal(t, "1780", report.Totals.Balance.String())
+}
+
+func TestJournalTransactions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.June, d, 0, 0, 0, 0, time.UTC) }
+	report := Report{From: "2024-06", To: "2024-06", Months: []MonthReport{{
+		Month: "2024-06",
+		Groups: []GroupReport{{Name: "Living", Categories: []CategoryLine{
+			{Name: "Dining out", Amount: decimal.NewFromInt(30), Currency: "EUR", Status: "Paid", Entries: []domain.ExpenseEntry{
+				{EntryID: "e1", Date: day(12), Description: `Cafe "Blue"`, Amount: decimal.NewFromInt(20)},
+				{EntryID: "e2", Date: day(5), Amount: decimal.NewFromInt(10)},
+			}},
+			{Name: "Phone", Amount: decimal.NewFromInt(35), Currency: "USD", Status: "Not Paid"},
+		}}},
+	}}}
+
+	transactions := journalTransactions(report)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, day(5), transactions[0].Date)
+	assert.Equal(t, "Dining out", transactions[0].Payee)
+	assert.Equal(t, `Cafe "Blue"`, transactions[1].Payee)
+
+	var b bytes.Buffer
+	require.NoError(t, Write(&b, report, FormatBeancount))
+	assert.Contains(t, b.String(), "2024-06-05 open Expenses:Living:Dining-Out\n")
+	assert.Contains(t, b.String(), "2024-06-12 * \"Cafe \\\"Blue\\\"\"\n  Expenses:Living:Dining-Out  20.00 EUR\n")
+
+	b.Reset()
+	require.NoError(t, Write(&b, report, FormatLedger))
+	assert.Contains(t, b.String(), "    Expenses:Living:Dining out  10.00 EUR\n")
+}
+
+func TestWrite(t *testing.T) {
+	exporter := setupTestExporter(t)
+	june := domain.NewMonth(2024, time.June)
+	report, err := exporter.Build(june, june, "USD", "USD")
+	require.NoError(t, err)
+
+	t.Run("csv", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Write(&b, report, FormatCSV))
+		assert.Contains(t, b.String(), "month,type,group,name,budget,amount,currency,status,notes\n")
+		assert.Contains(t, b.String(), "2024-06,income,,Freelance,,100.00,EUR,,\n")
+		assert.Contains(t, b.String(), "2024-06,expense,Housing,Rent,1200.00,1200.00,USD,Paid,June | July\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Write(&b, report, FormatJSON))
+		var decoded Report
+		require.NoError(t, json.Unmarshal(b.Bytes(), &decoded))
+		assert.Equal(t, report.From, decoded.From)
+		assert.Equal(t, "1800", decoded.Totals.Balance.String())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Write(&b, report, "Markdown"))
+		output := b.String()
+		assert.Contains(t, output, "# gocost report: June 2024\n")
+		assert.Contains(t, output, "#### Housing")
+		assert.Contains(t, output, "| Rent | 1200.00 USD | 1200.00 USD | 0.00 USD | Paid | June \\| July |")
+		assert.Contains(t, output, "| Balance | 1800.00 USD |")
+		assert.Contains(t, output, "Missing exchange rates for EUR")
+		assert.NotContains(t, output, "## Total\n")
+	})
+
+	t.Run("ledger", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Write(&b, report, FormatLedger))
+		output := b.String()
+		assert.Contains(t, output, "; gocost journal: June 2024\n")
+		assert.Contains(t, output, "\n2024-06-01 * Salary\n    Income:Salary  -3000.00 USD\n    Assets:Budget  3000.00 USD\n")
+		assert.Contains(t, output, "\n2024-06-01 * Rent\n    Expenses:Housing:Rent  1200.00 USD\n    Assets:Budget  -1200.00 USD\n")
+		assert.Contains(t, output, "Income:Freelance  -100.00 EUR")
+		// Categories without a paid expense are left out
+		assert.NotContains(t, output, "Groceries")
+	})
+
+	t.Run("beancount", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, Write(&b, report, FormatBeancount))
+		output := b.String()
+		assert.Contains(t, output, "2024-06-01 open Assets:Budget\n2024-06-01 open Income:Salary\n")
+		assert.Contains(t, output, "2024-06-01 open Expenses:Housing:Rent\n")
+		assert.Contains(t, output, "\n2024-06-01 * \"Rent\"\n  Expenses:Housing:Rent  1200.00 USD\n  Assets:Budget  -1200.00 USD\n")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		assert.Error(t, Write(&bytes.Buffer{}, report, "xml"))
+	})
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Accounts of the journals that are not derived from names. Incomes are
+// received into fundsAccount and paid expenses are paid from it.
+const (
+	expensesAccount = "Expenses"
+	incomeAccount   = "Income"
+	fundsAccount    = "Assets:Budget"
+)
+
+// journalTransaction moves Amount of Currency from fundsAccount to Account,
+// or from Account to fundsAccount when Amount is negative.
+type journalTransaction struct {
+	Date     time.Time
+	Payee    string
+	Account  []string // Segments of the account
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// journalTransactions returns the transactions of the incomes and paid
+// expenses of report, ordered by date. Incomes and expenses without entries
+// are dated on the first day of their month, and expenses with entries get
+// one transaction per entry.
+func journalTransactions(report Report) []journalTransaction {
+	var transactions []journalTransaction
+	for _, month := range report.Months {
+		m, err := domain.ParseMonth(month.Month)
+		if err != nil {
+			continue
+		}
+		first := m.Time()
+
+		for _, income := range month.Incomes {
+			transactions = append(transactions, journalTransaction{
+				Date:     first,
+				Payee:    income.Description,
+				Account:  []string{incomeAccount, income.Description},
+				Amount:   income.Amount.Neg(),
+				Currency: income.Currency,
+			})
+		}
+
+		for _, group := range month.Groups {
+			for _, category := range group.Categories {
+				if category.Status != "Paid" {
+					continue
+				}
+				account := []string{expensesAccount, group.Name, category.Name}
+				if len(category.Entries) == 0 {
+					if category.Amount.IsPositive() {
+						transactions = append(transactions, journalTransaction{
+							Date: first, Payee: category.Name, Account: account, Amount: category.Amount, Currency: category.Currency,
+						})
+					}
+					continue
+				}
+				for _, entry := range category.Entries {
+					payee := entry.Description
+					if payee == "" {
+						payee = category.Name
+					}
+					transactions = append(transactions, journalTransaction{
+						Date: entry.Date, Payee: payee, Account: account, Amount: entry.Amount, Currency: category.Currency,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+	return transactions
+}
+
+// writeLedger renders the incomes and paid expenses as a ledger journal,
+// which hledger reads as well.
+func writeLedger(w io.Writer, report Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "; gocost journal: %s\n", reportTitle(report))
+
+	for _, transaction := range journalTransactions(report) {
+		account := make([]string, len(transaction.Account))
+		for i, segment := range transaction.Account {
+			account[i] = ledgerAccountSegment(segment)
+		}
+		fmt.Fprintf(&b, "\n%s * %s\n", transaction.Date.Format("2006-01-02"), journalText(transaction.Payee))
+		fmt.Fprintf(&b, "    %s  %s\n", strings.Join(account, ":"), money(transaction.Amount, transaction.Currency))
+		fmt.Fprintf(&b, "    %s  %s\n", fundsAccount, money(transaction.Amount.Neg(), transaction.Currency))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeBeancount renders the incomes and paid expenses as a beancount
+// journal, opening every account on the date of its first transaction.
+func writeBeancount(w io.Writer, report Report) error {
+	transactions := journalTransactions(report)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; gocost journal: %s\n", reportTitle(report))
+
+	if len(transactions) > 0 {
+		opened := map[string]bool{fundsAccount: true}
+		fmt.Fprintf(&b, "\n%s open %s\n", transactions[0].Date.Format("2006-01-02"), fundsAccount)
+		for _, transaction := range transactions {
+			account := beancountAccount(transaction.Account)
+			if !opened[account] {
+				opened[account] = true
+				fmt.Fprintf(&b, "%s open %s\n", transaction.Date.Format("2006-01-02"), account)
+			}
+		}
+	}
+
+	for _, transaction := range transactions {
+		payee := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(journalText(transaction.Payee))
+		fmt.Fprintf(&b, "\n%s * \"%s\"\n", transaction.Date.Format("2006-01-02"), payee)
+		fmt.Fprintf(&b, "  %s  %s\n", beancountAccount(transaction.Account), money(transaction.Amount, transaction.Currency))
+		fmt.Fprintf(&b, "  %s  %s\n", fundsAccount, money(transaction.Amount.Neg(), transaction.Currency))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// journalText returns text on a single line, for payees.
+func journalText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// ledgerAccountSegment returns name as a segment of a ledger account. Colons
+// separate segments and two spaces end the account, so neither is kept.
+func ledgerAccountSegment(name string) string {
+	segment := journalText(strings.ReplaceAll(name, ":", " "))
+	if segment == "" {
+		return ungroupedName
+	}
+	return segment
+}
+
+// beancountAccount returns the beancount account made of segments. Beancount
+// segments start with a capital letter or a digit and hold only letters,
+// digits and dashes, so "dining out" becomes "Dining-Out".
+func beancountAccount(segments []string) string {
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		words := strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for j, word := range words {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			words[j] = string(runes)
+		}
+		parts[i] = strings.Join(words, "-")
+		if parts[i] == "" {
+			parts[i] = ungroupedName
+		}
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,205 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/shopspring/decimal"
+)
+
+// ungroupedName is the group name of categories whose group no longer exists.
+const ungroupedName = "Ungrouped"
+
+// Report holds the data of one or more months in a storage independent
+// shape. Totals are expressed in Currency.
+type Report struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Currency string        `json:"currency"`
+	Months   []MonthReport `json:"months"`
+	Totals   Totals        `json:"totals"`
+}
+
+// MonthReport holds the incomes and expenses of a month.
+type MonthReport struct {
+	Month        string        `json:"month"`
+	Incomes      []IncomeLine  `json:"incomes"`
+	Groups       []GroupReport `json:"groups"`
+	Totals       Totals        `json:"totals"`
+	MissingRates []string      `json:"missingRates,omitempty"`
+}
+
+// IncomeLine is an income of a month.
+type IncomeLine struct {
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
+}
+
+// GroupReport holds the categories of a group. Its budget and spent amounts
+// are expressed in the currency of the report.
+type GroupReport struct {
+	Name       string          `json:"name"`
+	Categories []CategoryLine  `json:"categories"`
+	Budget     decimal.Decimal `json:"budget"`
+	Spent      decimal.Decimal `json:"spent"`
+}
+
+// CategoryLine is the expense of a category in its own currency.
+type CategoryLine struct {
+	Name     string                `json:"name"`
+	Budget   decimal.Decimal       `json:"budget"`
+	Amount   decimal.Decimal       `json:"amount"`
+	Currency string                `json:"currency"`
+	Status   string                `json:"status"`
+	Notes    string                `json:"notes,omitempty"`
+	Entries  []domain.ExpenseEntry `json:"entries,omitempty"`
+}
+
+// Totals sums the incomes and expenses of a month or of a whole report.
+type Totals struct {
+	Income  decimal.Decimal `json:"income"`
+	Budget  decimal.Decimal `json:"budget"`
+	Spent   decimal.Decimal `json:"spent"`
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// add adds other to t.
+func (t Totals) add(other Totals) Totals {
+	return Totals{
+		Income:  t.Income.Add(other.Income),
+		Budget:  t.Budget.Add(other.Budget),
+		Spent:   t.Spent.Add(other.Spent),
+		Balance: t.Balance.Add(other.Balance),
+	}
+}
+
+// Exporter builds reports from the application services.
+type Exporter struct {
+	groupSvc *service.GroupService
+	monthSvc *service.MonthService
+}
+
+// New creates a new Exporter.
+func New(groupService *service.GroupService, monthService *service.MonthService) *Exporter {
+	return &Exporter{
+		groupSvc: groupService,
+		monthSvc: monthService,
+	}
+}
+
+// Build creates a report of the months from from to to, both included.
+// Records without a currency are in baseCurrency and totals are converted
+// into currency; records without an exchange rate are left out of them.
+func (e *Exporter) Build(from, to domain.Month, baseCurrency, currency string) (Report, error) {
+	groups, err := e.groupSvc.GetAllGroups()
+	if err != nil {
+		return Report{}, err
+	}
+	months, err := e.monthSvc.GetMonthRange(from, to)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Currency: currency}
+	for _, month := range months {
+		monthReport := buildMonth(month, groups, baseCurrency, currency)
+		report.Months = append(report.Months, monthReport)
+		report.Totals = report.Totals.add(monthReport.Totals)
+	}
+	if len(months) > 0 {
+		report.From = months[0].Month.String()
+		report.To = months[len(months)-1].Month.String()
+	}
+	return report, nil
+}
+
+// buildMonth creates the report of a single month.
+func buildMonth(month domain.MonthData, groups []domain.CategoryGroup, baseCurrency, currency string) MonthReport {
+	converter := domain.NewConverter(baseCurrency, month.Rates)
+
+	report := MonthReport{
+		Month:   month.Month.String(),
+		Incomes: []IncomeLine{},
+		Groups:  []GroupReport{},
+	}
+	missing := make(map[string]bool)
+	convert := func(amount decimal.Decimal, from string) decimal.Decimal {
+		value, ok := converter.Convert(amount, from, currency)
+		if !ok {
+			missing[converter.Currency(from)] = true
+		}
+		return value
+	}
+
+	for _, income := range month.Incomes {
+		report.Incomes = append(report.Incomes, IncomeLine{
+			Description: income.Description,
+			Amount:      income.Amount,
+			Currency:    converter.Currency(income.Currency),
+		})
+		report.Totals.Income = report.Totals.Income.Add(convert(income.Amount, income.Currency))
+	}
+
+	order := make(map[string]int)
+	names := make(map[string]string)
+	for _, group := range groups {
+		order[group.GroupID] = group.Order
+		names[group.GroupID] = group.GroupName
+	}
+	byGroup := make(map[string]*GroupReport)
+	var groupIDs []string
+
+	for _, category := range month.Categories {
+		groupID := category.GroupID
+		if _, ok := names[groupID]; !ok {
+			groupID = ""
+		}
+		group, ok := byGroup[groupID]
+		if !ok {
+			name := names[groupID]
+			if groupID == "" {
+				name = ungroupedName
+			}
+			group = &GroupReport{Name: name}
+			byGroup[groupID] = group
+			groupIDs = append(groupIDs, groupID)
+		}
+
+		line := CategoryLine{Name: category.CategoryName, Status: "Not Set", Currency: converter.Currency("")}
+		if expense, ok := category.Expense[category.CatID]; ok {
+			line.Budget = expense.Budget
+			line.Amount = expense.Amount
+			line.Currency = converter.Currency(expense.Currency)
+			line.Status = expense.Status
+			line.Notes = expense.Notes
+			line.Entries = expense.Entries
+			group.Budget = group.Budget.Add(convert(expense.Budget, expense.Currency))
+			group.Spent = group.Spent.Add(convert(expense.Amount, expense.Currency))
+		}
+		group.Categories = append(group.Categories, line)
+	}
+
+	// Groups keep their configured order, with ungrouped categories last
+	sort.SliceStable(groupIDs, func(i, j int) bool {
+		a, b := groupIDs[i], groupIDs[j]
+		if a == "" || b == "" {
+			return b == "" && a != ""
+		}
+		return order[a] < order[b]
+	})
+	for _, groupID := range groupIDs {
+		group := byGroup[groupID]
+		report.Groups = append(report.Groups, *group)
+		report.Totals.Budget = report.Totals.Budget.Add(group.Budget)
+		report.Totals.Spent = report.Totals.Spent.Add(group.Spent)
+	}
+	report.Totals.Balance = report.Totals.Income.Sub(report.Totals.Spent)
+
+	for currency := range missing {
+		report.MissingRates = append(report.MissingRates, currency)
+	}
+	sort.Strings(report.MissingRates)
+	return report
+}
@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultLayout is the name of the layout used when none is given.
+const DefaultLayout = "default"
+
+// Layout describes the columns of a bank statement in CSV format. Columns
+// are given by header name, case-insensitively, or by 1-based position.
+// Statements either have a single signed Amount column or separate Debit
+// and Credit columns.
+type Layout struct {
+	Delimiter        string `mapstructure:"delimiter"`
+	NoHeader         bool   `mapstructure:"noHeader"`
+	Date             string `mapstructure:"date"`
+	DateFormat       string `mapstructure:"dateFormat"` // Go time layout
+	Description      string `mapstructure:"description"`
+	Amount           string `mapstructure:"amount"`
+	Debit            string `mapstructure:"debit"`
+	Credit           string `mapstructure:"credit"`
+	DecimalComma     bool   `mapstructure:"decimalComma"`
+	ExpensesPositive bool   `mapstructure:"expensesPositive"` // Spent amounts are positive in Amount
+}
+
+// BuiltinLayouts returns the layouts available without configuration.
+func BuiltinLayouts() map[string]Layout {
+	return map[string]Layout{
+		DefaultLayout: {
+			Date:        "Date",
+			DateFormat:  "2006-01-02",
+			Description: "Description",
+			Amount:      "Amount",
+		},
+		"debit-credit": {
+			Date:        "Date",
+			DateFormat:  "2006-01-02",
+			Description: "Description",
+			Debit:       "Debit",
+			Credit:      "Credit",
+		},
+		"european": {
+			Delimiter:    ";",
+			Date:         "Date",
+			DateFormat:   "02.01.2006",
+			Description:  "Description",
+			Amount:       "Amount",
+			DecimalComma: true,
+		},
+	}
+}
+
+// validate checks that the layout names the columns it needs.
+func (l Layout) validate() error {
+	if l.Date == "" || l.Description == "" {
+		return errors.New("layout must name the date and description columns")
+	}
+	if l.Amount == "" && l.Debit == "" && l.Credit == "" {
+		return errors.New("layout must name an amount column or debit and credit columns")
+	}
+	if len([]rune(l.Delimiter)) > 1 {
+		return fmt.Errorf("layout delimiter must be a single character, got %q", l.Delimiter)
+	}
+	return nil
+}
+
+// ReadCSV reads the transactions of a bank statement in CSV format.
+// Rows without an amount are skipped.
+func ReadCSV(r io.Reader, layout Layout) ([]Transaction, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	if layout.DateFormat == "" {
+		layout.DateFormat = "2006-01-02"
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if layout.Delimiter != "" {
+		reader.Comma = []rune(layout.Delimiter)[0]
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var header []string
+	firstRow := 0
+	if !layout.NoHeader {
+		header = records[0]
+		firstRow = 1
+	}
+
+	columns := make(map[string]int)
+	for _, name := range []string{layout.Date, layout.Description, layout.Amount, layout.Debit, layout.Credit} {
+		if name == "" {
+			continue
+		}
+		index, err := columnIndex(header, name)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = index
+	}
+
+	var transactions []Transaction
+	for i, record := range records[firstRow:] {
+		line := firstRow + i + 1
+		value := func(name string) string {
+			if name == "" || columns[name] >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[columns[name]])
+		}
+
+		amount, ok, err := layout.amount(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !ok {
+			continue
+		}
+
+		date, err := time.Parse(layout.DateFormat, value(layout.Date))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, value(layout.Date))
+		}
+
+		transactions = append(transactions, Transaction{
+			Date:   date,
+			Payee:  value(layout.Description),
+			Amount: amount,
+		})
+	}
+	return transactions, nil
+}
+
+// amount returns the spent amount of a row. It reports false when the row has no amount.
+func (l Layout) amount(value func(name string) string) (decimal.Decimal, bool, error) {
+	if l.Amount != "" {
+		raw := value(l.Amount)
+		if raw == "" {
+			return decimal.Zero, false, nil
+		}
+		amount, err := ParseAmount(raw, l.DecimalComma)
+		if err != nil {
+			return decimal.Zero, false, err
+		}
+		if !l.ExpensesPositive {
+			amount = amount.Neg()
+		}
+		return amount, true, nil
+	}
+
+	debit, credit := value(l.Debit), value(l.Credit)
+	if debit == "" && credit == "" {
+		return decimal.Zero, false, nil
+	}
+	var amount decimal.Decimal
+	if debit != "" {
+		spent, err := ParseAmount(debit, l.DecimalComma)
+		if err != nil {
+			return decimal.Zero, false, err
+		}
+		amount = amount.Add(spent.Abs())
+	}
+	if credit != "" {
+		received, err := ParseAmount(credit, l.DecimalComma)
+		if err != nil {
+			return decimal.Zero, false, err
+		}
+		amount = amount.Sub(received.Abs())
+	}
+	return amount, true, nil
+}
+
+// ParseAmount parses an amount as written in bank statements, ignoring
+// currency symbols and thousands separators. Amounts in parentheses are negative.
+func ParseAmount(value string, decimalComma bool) (decimal.Decimal, error) {
+	raw := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(raw, "(") && strings.HasSuffix(raw, ")") {
+		negative = true
+		raw = raw[1 : len(raw)-1]
+	}
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '+':
+			b.WriteRune(r)
+		case r == '.' && !decimalComma, r == ',' && decimalComma:
+			b.WriteRune('.')
+		}
+	}
+
+	amount, err := decimal.NewFromString(b.String())
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// columnIndex returns the position of a column given by header name or 1-based position.
+func columnIndex(header []string, name string) (int, error) {
+	if position, err := strconv.Atoi(name); err == nil {
+		if position < 1 {
+			return 0, fmt.Errorf("invalid column position %d", position)
+		}
+		return position - 1, nil
+	}
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")), name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("column %q not found in the header", name)
+}
@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	layouts := BuiltinLayouts()
+
+	t.Run("default layout", func(t *testing.T) {
+		statement := "Date,Description,Amount\n2024-06-03,TESCO STORES 123,-42.10\n2024-06-04,Salary,\"2,500.00\"\n2024-06-05,Pending,\n"
+		transactions, err := ReadCSV(strings.NewReader(statement), layouts[DefaultLayout])
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		assert.Equal(t, "TESCO STORES 123", transactions[0].Payee)
+		assert.Equal(t, "42.1", transactions[0].Amount.String())
+		assert.Equal(t, "-2500", transactions[1].Amount.String())
+		assert.Equal(t, 3, transactions[0].Date.Day())
+	})
+
+	t.Run("debit and credit columns", func(t *testing.T) {
+		statement := "date,description,debit,credit\n2024-06-03,Rent,1200.00,\n2024-06-04,Refund,,15.00\n"
+		transactions, err := ReadCSV(strings.NewReader(statement), layouts["debit-credit"])
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		assert.Equal(t, "1200", transactions[0].Amount.String())
+		assert.Equal(t, "-15", transactions[1].Amount.String())
+	})
+
+	t.Run("european layout", func(t *testing.T) {
+		statement := "Date;Description;Amount\n03.06.2024;Bäckerei;-1.234,56 €\n"
+		transactions, err := ReadCSV(strings.NewReader(statement), layouts["european"])
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, "1234.56", transactions[0].Amount.String())
+	})
+
+	t.Run("columns by position without header", func(t *testing.T) {
+		layout := Layout{NoHeader: true, Date: "1", Description: "3", Amount: "2", DateFormat: "01/02/2006", ExpensesPositive: true}
+		transactions, err := ReadCSV(strings.NewReader("06/03/2024,(12.50),Coffee\n"), layout)
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, "-12.5", transactions[0].Amount.String())
+		assert.Equal(t, "Coffee", transactions[0].Payee)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ReadCSV(strings.NewReader("Date,Payee,Amount\n"), layouts[DefaultLayout])
+		assert.ErrorContains(t, err, `column "Description" not found`)
+
+		_, err = ReadCSV(strings.NewReader("Date,Description,Amount\nyesterday,Coffee,-3\n"), layouts[DefaultLayout])
+		assert.ErrorContains(t, err, "line 2")
+
+		_, err = ReadCSV(strings.NewReader(""), Layout{Date: "Date"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,290 @@
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/madalinpopa/gocost/internal/ui"
+)
+
+// Status tells what importing a transaction will do.
+type Status string
+
+const (
+	StatusNew             Status = "new"
+	StatusDuplicate       Status = "duplicate"
+	StatusUnmatched       Status = "unmatched"
+	StatusMissingCategory Status = "missing category"
+	StatusIncome          Status = "income"
+	StatusSkipped         Status = "skipped"
+)
+
+// Item is a transaction together with where it will be imported.
+type Item struct {
+	Transaction
+	MonthKey string
+	Category string
+	Group    string
+	CatID    string // ID of the category when the month has it
+	EntryID  string
+	Status   Status
+	Reason   string // Why the item is skipped
+}
+
+// Plan lists what an import will do with each transaction. It is the
+// preview shown for a dry run.
+type Plan struct {
+	Items []Item
+}
+
+// Count returns the number of items with the given status.
+func (p Plan) Count(status Status) int {
+	count := 0
+	for _, item := range p.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Importer turns bank transactions and journal postings into dated entries
+// of the category expenses and into incomes.
+type Importer struct {
+	categorySvc *service.CategoryService
+	groupSvc    *service.GroupService
+	incomeSvc   *service.IncomeService
+}
+
+// New creates a new Importer.
+func New(categoryService *service.CategoryService, groupService *service.GroupService, incomeService *service.IncomeService) *Importer {
+	return &Importer{
+		categorySvc: categoryService,
+		groupSvc:    groupService,
+		incomeSvc:   incomeService,
+	}
+}
+
+// Plan assigns every transaction to a month and a category using rules, or
+// the category given by the statement, without changing any data. Amounts
+// are in the default currency, currency, so transactions of categories whose
+// expense is in another one are skipped. Received money is left out, and
+// transactions imported before are recognized as duplicates.
+func (im *Importer) Plan(transactions []Transaction, rules RuleSet, currency string) (Plan, error) {
+	groups, err := im.groupSvc.GetAllGroups()
+	if err != nil {
+		return Plan{}, err
+	}
+	currency = domain.NormalizeCurrency(currency)
+	months := make(map[string][]domain.Category)
+	seen := make(map[string]int)
+
+	var plan Plan
+	for _, transaction := range transactions {
+		item := Item{
+			Transaction: transaction,
+			MonthKey:    ui.GetMonthKey(transaction.Date.Month(), transaction.Date.Year()),
+		}
+
+		// Identical transactions in the same statement get distinct IDs
+		key := fmt.Sprintf("%s|%s|%s", transaction.Date.Format("2006-01-02"), transaction.Payee, transaction.Amount.String())
+		if transaction.ID != "" {
+			key = "id|" + transaction.ID
+		}
+		item.EntryID = entryID(key, seen[key])
+		seen[key]++
+
+		if !transaction.Amount.IsPositive() {
+			item.Status = StatusIncome
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+
+		// Transactions the services would refuse are left out of the import
+		entry := domain.ExpenseEntry{Description: transaction.Payee, Amount: transaction.Amount}
+		if err := service.ValidateEntry(entry); err != nil {
+			item.Status = StatusSkipped
+			item.Reason = err.Error()
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+
+		rule, ok := rules.Match(transaction.Payee)
+		if !ok && transaction.Category != "" {
+			// The category given by the statement is used when no rule matches
+			rule, ok = Rule{Category: transaction.Category}, true
+		}
+		if !ok {
+			item.Status = StatusUnmatched
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+		item.Category = rule.Category
+		item.Group = rule.Group
+
+		categories, ok := months[item.MonthKey]
+		if !ok {
+			categories, err = im.categorySvc.GetCategoriesForMonth(item.MonthKey)
+			if err != nil {
+				return Plan{}, err
+			}
+			months[item.MonthKey] = categories
+		}
+
+		category, found := itemCategory(categories, groups, item)
+		if found {
+			item.CatID = category.CatID
+		}
+		expense, exists := category.Expense[category.CatID]
+		expenseCurrency := domain.NormalizeCurrency(expense.Currency)
+		switch {
+		case found && hasEntry(category, item.EntryID):
+			item.Status = StatusDuplicate
+		case exists && expenseCurrency != "" && expenseCurrency != currency:
+			item.Status = StatusSkipped
+			item.Reason = fmt.Sprintf("expense is in %s", expenseCurrency)
+		case found:
+			item.Status = StatusNew
+		case rule.Group != "" && findGroup(groups, rule.Group) != "":
+			item.Status = StatusNew
+			if err := service.ValidateName("category name", rule.Category); err != nil {
+				item.Status = StatusSkipped
+				item.Reason = err.Error()
+			}
+		default:
+			item.Status = StatusMissingCategory
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, nil
+}
+
+// Apply imports the new items of a plan and returns how many were imported.
+// Categories missing from a month are created in the group of their rule.
+// The items are imported in a single write, so nothing is imported when one
+// of them fails.
+func (im *Importer) Apply(plan Plan) (int, error) {
+	count := 0
+	err := im.categorySvc.Atomically(func() error {
+		groups, err := im.groupSvc.GetAllGroups()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range plan.Items {
+			if item.Status != StatusNew {
+				continue
+			}
+
+			categories, err := im.categorySvc.GetCategoriesForMonth(item.MonthKey)
+			if err != nil {
+				return err
+			}
+			category, found := itemCategory(categories, groups, item)
+			if found && hasEntry(category, item.EntryID) {
+				continue
+			}
+			if !found {
+				category = domain.Category{
+					CatID:        ui.GenerateID(),
+					GroupID:      findGroup(groups, item.Group),
+					CategoryName: item.Category,
+				}
+			}
+
+			entry := domain.ExpenseEntry{EntryID: item.EntryID, Date: item.Date, Description: item.Payee, Amount: item.Amount}
+			if err := im.addEntry(item.MonthKey, category, found, entry, ""); err != nil {
+				return fmt.Errorf("failed to import '%s' into %s: %w", item.Payee, item.Category, err)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// addEntry adds entry to the expense of category in monthKey, adding the
+// category when it was not found in the month. An expense created for the
+// entry is paid and in currency, which is empty for the default one.
+func (im *Importer) addEntry(monthKey string, category domain.Category, found bool, entry domain.ExpenseEntry, currency string) error {
+	expenses := make(map[string]domain.ExpenseRecord, len(category.Expense)+1)
+	for key, record := range category.Expense {
+		expenses[key] = record
+	}
+	expense, exists := expenses[category.CatID]
+	if !exists {
+		expense.Status = "Paid"
+		expense.Currency = currency
+	}
+	expense.Entries = append(append([]domain.ExpenseEntry{}, expense.Entries...), entry)
+	expense.SyncAmount()
+	expenses[category.CatID] = expense
+	category.Expense = expenses
+
+	if found {
+		return im.categorySvc.UpdateCategory(monthKey, category)
+	}
+	return im.categorySvc.AddCategory(monthKey, category)
+}
+
+// entryID derives a stable entry ID from a transaction key and its
+// occurrence in the statement, so importing it again is detected.
+func entryID(key string, occurrence int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, occurrence)))
+	return "import-" + hex.EncodeToString(sum[:8])
+}
+
+// itemCategory returns the category item is imported into: the one with its
+// ID when it has one, and otherwise the one named after it, preferably in its
+// group. Names of categories are only unique within a group.
+func itemCategory(categories []domain.Category, groups []domain.CategoryGroup, item Item) (domain.Category, bool) {
+	if item.CatID != "" {
+		for _, category := range categories {
+			if category.CatID == item.CatID {
+				return category, true
+			}
+		}
+		return domain.Category{}, false
+	}
+	if category, found := findGroupCategory(categories, findGroup(groups, item.Group), item.Category); found {
+		return category, true
+	}
+	return findCategory(categories, item.Category)
+}
+
+// findCategory returns the category named name, case-insensitively.
+func findCategory(categories []domain.Category, name string) (domain.Category, bool) {
+	for _, category := range categories {
+		if strings.EqualFold(category.CategoryName, name) {
+			return category, true
+		}
+	}
+	return domain.Category{}, false
+}
+
+// findGroup returns the ID of the group named name, or an empty string.
+func findGroup(groups []domain.CategoryGroup, name string) string {
+	for _, group := range groups {
+		if strings.EqualFold(group.GroupName, name) || group.GroupID == name {
+			return group.GroupID
+		}
+	}
+	return ""
+}
+
+// hasEntry reports whether the expense of category holds the entry entryID.
+func hasEntry(category domain.Category, entryID string) bool {
+	for _, entry := range category.Expense[category.CatID].Entries {
+		if entry.EntryID == entryID {
+			return true
+		}
+	}
+	return false
+}
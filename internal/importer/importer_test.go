@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileRules(t *testing.T) {
+	rules, err := CompileRules([]Rule{
+		{Pattern: "tesco|lidl", Category: "Groceries"},
+		{Pattern: ".*", Category: "Other"},
+	})
+	require.NoError(t, err)
+
+	rule, ok := rules.Match("LIDL 0042 BERLIN")
+	require.True(t, ok)
+	assert.Equal(t, "Groceries", rule.Category)
+
+	rule, ok = rules.Match("Cinema")
+	require.True(t, ok)
+	assert.Equal(t, "Other", rule.Category)
+
+	_, err = CompileRules([]Rule{{Pattern: "(", Category: "Broken"}})
+	assert.Error(t, err)
+	_, err = CompileRules([]Rule{{Pattern: "shop"}})
+	assert.Error(t, err)
+}
+
+func TestImporter_PlanAndApply(t *testing.T) {
+	repo, err := data.NewJsonRepository(filepath.Join(t.TempDir(), "test_data.json"), "USD")
+	require.NoError(t, err)
+	categorySvc := service.NewCategoryService(repo)
+	groupSvc := service.NewGroupService(repo)
+	require.NoError(t, groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Living", Order: 1}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Groceries"}))
+
+	rules, err := CompileRules([]Rule{
+		{Pattern: "tesco", Category: "Groceries"},
+		{Pattern: "netflix", Category: "Subscriptions", Group: "Living"},
+		{Pattern: "gym", Category: "Sport"},
+	})
+	require.NoError(t, err)
+
+	day := func(d int) time.Time { return time.Date(2024, time.June, d, 0, 0, 0, 0, time.UTC) }
+	transactions := []Transaction{
+		{Date: day(3), Payee: "TESCO", Amount: decimal.RequireFromString("42.10")},
+		{Date: day(3), Payee: "TESCO", Amount: decimal.RequireFromString("42.10")},
+		{Date: day(5), Payee: "NETFLIX.COM", Amount: decimal.RequireFromString("15.99")},
+		{Date: day(6), Payee: "City Gym", Amount: decimal.RequireFromString("30")},
+		{Date: day(7), Payee: "Unknown shop", Amount: decimal.RequireFromString("5")},
+		{Date: day(8), Payee: "Salary", Amount: decimal.RequireFromString("-2500")},
+		{Date: day(9), Payee: "CARD PAYMENT TO TESCO STORES 3297 LONDON GB ON 09 JUN 2024 REF 48291044", Amount: decimal.RequireFromString("1")},
+		{Date: day(9), Payee: "TESCO " + strings.Repeat("x", 250), Amount: decimal.RequireFromString("1")},
+	}
+
+	im := New(categorySvc, groupSvc, service.NewIncomeService(repo))
+	plan, err := im.Plan(transactions, rules, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 4, plan.Count(StatusNew))
+	assert.Equal(t, 1, plan.Count(StatusMissingCategory))
+	assert.Equal(t, 1, plan.Count(StatusUnmatched))
+	assert.Equal(t, 1, plan.Count(StatusIncome))
+	assert.NotEqual(t, plan.Items[0].EntryID, plan.Items[1].EntryID)
+	// Payees longer than the services accept are skipped in the preview
+	assert.Equal(t, StatusSkipped, plan.Items[7].Status)
+	assert.Equal(t, "entry descriptions cannot be longer than 250 characters", plan.Items[7].Reason)
+
+	count, err := im.Apply(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	categories, err := categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	groceries := categories[0].Expense["c1"]
+	assert.Len(t, groceries.Entries, 3)
+	assert.Equal(t, "85.2", groceries.Amount.String())
+	assert.Equal(t, "Subscriptions", categories[1].CategoryName)
+	assert.Equal(t, "g1", categories[1].GroupID)
+
+	// Importing the same statement again finds only duplicates
+	plan, err = im.Plan(transactions, rules, "USD")
+	require.NoError(t, err)
+	assert.Zero(t, plan.Count(StatusNew))
+	assert.Equal(t, 4, plan.Count(StatusDuplicate))
+}
+
+func TestImporter_PlanStatementCategories(t *testing.T) {
+	repo, err := data.NewJsonRepository(filepath.Join(t.TempDir(), "test_data.json"), "USD")
+	require.NoError(t, err)
+	categorySvc := service.NewCategoryService(repo)
+	groupSvc := service.NewGroupService(repo)
+	require.NoError(t, groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Living", Order: 1}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Groceries"}))
+
+	rules, err := CompileRules([]Rule{{Pattern: "netflix", Category: "Subscriptions"}})
+	require.NoError(t, err)
+
+	day := time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Date: day, Payee: "Tesco", Amount: decimal.RequireFromString("42.10"), ID: "A1", Category: "groceries"},
+		{Date: day, Payee: "Tesco", Amount: decimal.RequireFromString("42.10"), ID: "A2", Category: "groceries"},
+		{Date: day, Payee: "Netflix", Amount: decimal.RequireFromString("15.99"), Category: "Groceries"},
+	}
+
+	im := New(categorySvc, groupSvc, service.NewIncomeService(repo))
+	plan, err := im.Plan(transactions, rules, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, StatusNew, plan.Items[0].Status)
+	assert.Equal(t, "groceries", plan.Items[0].Category)
+	// Transactions with bank IDs are told apart by them
+	assert.NotEqual(t, plan.Items[0].EntryID, plan.Items[1].EntryID)
+	// Rules come before the category of the statement
+	assert.Equal(t, "Subscriptions", plan.Items[2].Category)
+	assert.Equal(t, StatusMissingCategory, plan.Items[2].Status)
+
+	// Categories of the same name are told apart by the group of the rule
+	require.NoError(t, groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: "Leisure", Order: 2}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "Other"}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{CatID: "c3", GroupID: "g2", CategoryName: "Other"}))
+	rules, err = CompileRules([]Rule{{Pattern: "cinema", Category: "Other", Group: "Leisure"}})
+	require.NoError(t, err)
+	plan, err = im.Plan([]Transaction{{Date: day, Payee: "Cinema", Amount: decimal.RequireFromString("12")}}, rules, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "c3", plan.Items[0].CatID)
+
+	count, err := im.Apply(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	categories, err := categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	assert.Empty(t, categories[1].Expense["c2"].Entries)
+	assert.Len(t, categories[2].Expense["c3"].Entries, 1)
+}
+
+func TestImporter_PlanCurrency(t *testing.T) {
+	repo, err := data.NewJsonRepository(filepath.Join(t.TempDir(), "test_data.json"), "USD")
+	require.NoError(t, err)
+	categorySvc := service.NewCategoryService(repo)
+	groupSvc := service.NewGroupService(repo)
+	require.NoError(t, groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Living", Order: 1}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{
+		CatID: "c1", GroupID: "g1", CategoryName: "Rent",
+		Expense: map[string]domain.ExpenseRecord{"c1": {Amount: decimal.RequireFromString("900"), Currency: "EUR", Status: "Paid"}},
+	}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{
+		CatID: "c2", GroupID: "g1", CategoryName: "Groceries",
+		Expense: map[string]domain.ExpenseRecord{"c2": {Amount: decimal.RequireFromString("10"), Currency: "usd", Status: "Paid"}},
+	}))
+
+	rules, err := CompileRules([]Rule{
+		{Pattern: "landlord", Category: "Rent"},
+		{Pattern: "tesco", Category: "Groceries"},
+	})
+	require.NoError(t, err)
+
+	day := time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Date: day, Payee: "Landlord", Amount: decimal.RequireFromString("900")},
+		{Date: day, Payee: "Tesco", Amount: decimal.RequireFromString("42.10")},
+	}
+
+	im := New(categorySvc, groupSvc, service.NewIncomeService(repo))
+	plan, err := im.Plan(transactions, rules, "usd")
+	require.NoError(t, err)
+	// Statement amounts are in the default currency
+	assert.Equal(t, StatusSkipped, plan.Items[0].Status)
+	assert.Equal(t, "expense is in EUR", plan.Items[0].Reason)
+	assert.Equal(t, StatusNew, plan.Items[1].Status)
+
+	count, err := im.Apply(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	categories, err := categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	assert.Empty(t, categories[0].Expense["c1"].Entries)
+}
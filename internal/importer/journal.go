@@ -0,0 +1,296 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Formats of the plain-text accounting journals read by ReadJournal.
+const (
+	FormatLedger    = "ledger" // Also written by hledger
+	FormatBeancount = "beancount"
+)
+
+// Posting moves Amount of Commodity into Account, or out of it when Amount
+// is negative. Commodity is empty for amounts written without one.
+type Posting struct {
+	Account   string
+	Amount    decimal.Decimal
+	Commodity string
+}
+
+// JournalTransaction is a transaction of a plain-text accounting journal.
+type JournalTransaction struct {
+	Date     time.Time
+	Payee    string
+	Postings []Posting
+	Line     int // Line of the transaction in the journal
+}
+
+// journalDateLayouts are the layouts of the dates of ledger transactions.
+var journalDateLayouts = []string{"2006-01-02", "2006/01/02", "2006.01.02"}
+
+var (
+	// quotedPattern matches the quoted payee and narration of beancount transactions.
+	quotedPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+	// metadataPattern matches the metadata lines of beancount transactions.
+	metadataPattern = regexp.MustCompile(`^[a-z][A-Za-z0-9_-]*:(\s|$)`)
+	// ledgerPostingPattern splits a ledger posting into its account and its
+	// amount, which are separated by a tab or at least two spaces.
+	ledgerPostingPattern = regexp.MustCompile(`^(\S.*?)(?:\t|\s{2,})\s*(.*)$`)
+)
+
+// ReadJournal reads the transactions of a ledger, hledger or beancount
+// journal. Directives other than transactions are skipped, and a posting
+// without an amount gets the amount balancing its transaction.
+func ReadJournal(r io.Reader, format string) ([]JournalTransaction, error) {
+	if format != FormatLedger && format != FormatBeancount {
+		return nil, fmt.Errorf("unknown journal format %q", format)
+	}
+
+	var transactions []JournalTransaction
+	var current *JournalTransaction
+	elided := -1 // Index of the posting of current without an amount
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if elided >= 0 {
+			if err := balance(current, elided); err != nil {
+				return err
+			}
+		}
+		transactions = append(transactions, *current)
+		current, elided = nil, -1
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(text)
+
+		switch {
+		case trimmed == "":
+			if err := finish(); err != nil {
+				return nil, err
+			}
+
+		case text[0] == ' ' || text[0] == '\t':
+			if current == nil || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") ||
+				(format == FormatBeancount && metadataPattern.MatchString(trimmed)) {
+				continue
+			}
+			posting, ok, err := parsePosting(trimmed, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if !ok {
+				continue
+			}
+			if posting == nil {
+				if elided >= 0 {
+					return nil, fmt.Errorf("line %d: only one posting of a transaction can leave out its amount", line)
+				}
+				elided = len(current.Postings)
+				posting = &Posting{}
+				posting.Account = accountOf(trimmed, format)
+			}
+			current.Postings = append(current.Postings, *posting)
+
+		default:
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			transaction, ok, err := parseTransactionHeader(trimmed, format)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if ok {
+				transaction.Line = line
+				current = &transaction
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// parseTransactionHeader parses the first line of a transaction. It reports
+// false for the lines of other directives, such as accounts and prices.
+func parseTransactionHeader(text, format string) (JournalTransaction, bool, error) {
+	if !unicode.IsDigit(rune(text[0])) {
+		return JournalTransaction{}, false, nil
+	}
+	dateText, rest, _ := strings.Cut(text, " ")
+	rest = strings.TrimSpace(rest)
+
+	if format == FormatBeancount {
+		date, err := time.Parse("2006-01-02", dateText)
+		if err != nil {
+			return JournalTransaction{}, false, fmt.Errorf("invalid date %q", dateText)
+		}
+		flag, _, _ := strings.Cut(rest, " ")
+		if flag != "*" && flag != "!" && flag != "txn" {
+			return JournalTransaction{}, false, nil
+		}
+		// The payee comes first, or is left out when only the narration is given
+		transaction := JournalTransaction{Date: date}
+		if quoted := quotedPattern.FindAllStringSubmatch(rest, 2); len(quoted) > 0 {
+			transaction.Payee = quoted[0][1]
+		}
+		return transaction, true, nil
+	}
+
+	// Ledger dates may be followed by an auxiliary date, as in 2024/06/03=2024/06/05
+	dateText, _, _ = strings.Cut(dateText, "=")
+	var date time.Time
+	var err error
+	for _, layout := range journalDateLayouts {
+		if date, err = time.Parse(layout, dateText); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return JournalTransaction{}, false, fmt.Errorf("invalid date %q", dateText)
+	}
+
+	rest = strings.TrimSpace(strings.TrimLeft(rest, "*!"))
+	if strings.HasPrefix(rest, "(") {
+		if end := strings.Index(rest, ")"); end >= 0 {
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+	}
+	payee, _, _ := strings.Cut(rest, ";")
+	// hledger descriptions are written as "payee | note"
+	payee, _, _ = strings.Cut(payee, "|")
+	return JournalTransaction{Date: date, Payee: strings.TrimSpace(payee)}, true, nil
+}
+
+// parsePosting parses a posting line. It returns a nil posting when the
+// amount is left out, and reports false for virtual postings, which do not
+// move money.
+func parsePosting(text, format string) (*Posting, bool, error) {
+	text, _, _ = strings.Cut(text, ";")
+	text = strings.TrimSpace(text)
+	// Postings may be flagged as cleared or pending
+	if strings.HasPrefix(text, "* ") || strings.HasPrefix(text, "! ") {
+		text = strings.TrimSpace(text[2:])
+	}
+	if strings.HasPrefix(text, "(") || strings.HasPrefix(text, "[") {
+		return nil, false, nil
+	}
+
+	account, amountText := text, ""
+	if format == FormatBeancount {
+		if fields := strings.Fields(text); len(fields) > 1 {
+			account = fields[0]
+			amountText = strings.Join(fields[1:], " ")
+		}
+	} else if match := ledgerPostingPattern.FindStringSubmatch(text); match != nil {
+		account, amountText = match[1], match[2]
+	}
+
+	// Prices, costs and balance assertions follow the amount
+	if i := strings.IndexAny(amountText, "@{="); i >= 0 {
+		amountText = amountText[:i]
+	}
+	amountText = strings.TrimSpace(amountText)
+	if amountText == "" {
+		return nil, true, nil
+	}
+
+	amount, commodity, err := parseJournalAmount(amountText)
+	if err != nil {
+		return nil, false, err
+	}
+	return &Posting{Account: strings.TrimSpace(account), Amount: amount, Commodity: commodity}, true, nil
+}
+
+// accountOf returns the account of a posting line without an amount.
+func accountOf(text, format string) string {
+	text, _, _ = strings.Cut(text, ";")
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "* ") || strings.HasPrefix(text, "! ") {
+		text = strings.TrimSpace(text[2:])
+	}
+	if format == FormatBeancount {
+		if fields := strings.Fields(text); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return text
+}
+
+// parseJournalAmount parses an amount such as "$12.50", "-12.50 EUR" or
+// "EUR 1,200" into its value and its commodity.
+func parseJournalAmount(text string) (decimal.Decimal, string, error) {
+	rest := strings.TrimSpace(text)
+	negative := false
+	if strings.HasPrefix(rest, "-") {
+		negative = true
+		rest = strings.TrimSpace(rest[1:])
+	}
+
+	start := strings.IndexFunc(rest, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '-' || r == '+' || r == '.'
+	})
+	if start < 0 {
+		return decimal.Zero, "", fmt.Errorf("invalid amount %q", text)
+	}
+	prefix := strings.TrimSpace(rest[:start])
+	number := rest[start:]
+	suffix := ""
+	if end := strings.IndexFunc(number, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '-' && r != '+' && r != '.' && r != ','
+	}); end >= 0 {
+		number, suffix = number[:end], strings.TrimSpace(number[end:])
+	}
+	if prefix != "" && suffix != "" {
+		return decimal.Zero, "", fmt.Errorf("invalid amount %q", text)
+	}
+
+	amount, err := decimal.NewFromString(strings.ReplaceAll(number, ",", ""))
+	if err != nil {
+		return decimal.Zero, "", fmt.Errorf("invalid amount %q", text)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, strings.Trim(prefix+suffix, `"`), nil
+}
+
+// balance sets the amount of the posting at index elided of transaction to
+// the amount balancing the others, which must share a single commodity.
+func balance(transaction *JournalTransaction, elided int) error {
+	sum := decimal.Zero
+	commodity, first := "", true
+	for i, posting := range transaction.Postings {
+		if i == elided {
+			continue
+		}
+		if !first && posting.Commodity != commodity {
+			return fmt.Errorf("line %d: cannot infer a left out amount in a transaction with several commodities", transaction.Line)
+		}
+		commodity, first = posting.Commodity, false
+		sum = sum.Add(posting.Amount)
+	}
+	transaction.Postings[elided].Amount = sum.Neg()
+	transaction.Postings[elided].Commodity = commodity
+	return nil
+}
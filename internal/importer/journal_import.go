@@ -0,0 +1,391 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/madalinpopa/gocost/internal/ui"
+	"github.com/shopspring/decimal"
+)
+
+// Roots of the journal accounts that are imported. Postings to other
+// accounts, such as assets and liabilities, are the other side of the
+// transactions and are left out.
+var (
+	expenseRoots = []string{"expenses", "expense"}
+	incomeRoots  = []string{"income", "revenue", "revenues"}
+)
+
+// commoditySymbols maps the currency symbols of journals to currency codes.
+var commoditySymbols = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"¥": "JPY",
+}
+
+// JournalItem is a posting to an expense or income account of a journal,
+// together with the record it will be imported into.
+type JournalItem struct {
+	Date        time.Time
+	Payee       string
+	Account     string
+	Amount      decimal.Decimal
+	Currency    string // Empty for the default currency
+	MonthKey    string
+	Income      bool
+	Group       string // Group and category of expenses
+	Category    string
+	RecordID    string // Entry ID of expenses, income ID of incomes
+	NewGroup    bool   // Set on the first item of a group or category to create
+	NewCategory bool
+	Status      Status
+	Reason      string // Why the item is skipped
+}
+
+// JournalPlan lists what the import of a journal will do with each posting.
+// It is the preview shown before any data is written.
+type JournalPlan struct {
+	Items []JournalItem
+}
+
+// Count returns the number of items with the given status.
+func (p JournalPlan) Count(status Status) int {
+	count := 0
+	for _, item := range p.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Created returns the number of groups and categories the plan creates.
+func (p JournalPlan) Created() (groups, categories int) {
+	for _, item := range p.Items {
+		if item.NewGroup {
+			groups++
+		}
+		if item.NewCategory {
+			categories++
+		}
+	}
+	return groups, categories
+}
+
+// PlanJournal maps the postings of transactions to expense entries and
+// incomes without changing any data. An account such as
+// Expenses:Living:Groceries is imported into the category Groceries of the
+// group Living, and one such as Income:Salary into an income of the month.
+// Amounts in currency are kept in the default currency, and postings
+// imported before are recognized as duplicates.
+func (im *Importer) PlanJournal(transactions []JournalTransaction, currency string) (JournalPlan, error) {
+	groups, err := im.groupSvc.GetAllGroups()
+	if err != nil {
+		return JournalPlan{}, err
+	}
+	currency = domain.NormalizeCurrency(currency)
+
+	months := make(map[string]journalMonth)
+	seen := make(map[string]int)
+	plannedGroups := make(map[string]bool)
+	plannedCurrencies := make(map[string]string) // Currencies of the expenses the plan creates
+
+	var plan JournalPlan
+	for _, transaction := range transactions {
+		for _, posting := range transaction.Postings {
+			root, rest, _ := strings.Cut(posting.Account, ":")
+			income := containsFold(incomeRoots, root)
+			if !income && !containsFold(expenseRoots, root) {
+				continue
+			}
+
+			item := JournalItem{
+				Date:     transaction.Date,
+				Payee:    transaction.Payee,
+				Account:  posting.Account,
+				Amount:   posting.Amount,
+				Currency: journalCurrency(posting.Commodity, currency),
+				MonthKey: ui.GetMonthKey(transaction.Date.Month(), transaction.Date.Year()),
+				Income:   income,
+			}
+			// Income accounts are credited, so money received is negative
+			if income {
+				item.Amount = item.Amount.Neg()
+			} else {
+				item.Group, item.Category = accountCategory(root, rest)
+			}
+
+			// Identical postings in the same journal get distinct IDs
+			key := fmt.Sprintf("%s|%s|%s|%s|%s", transaction.Date.Format("2006-01-02"), transaction.Payee,
+				posting.Account, posting.Amount.String(), posting.Commodity)
+			item.RecordID = entryID(key, seen[key])
+			seen[key]++
+
+			month, ok := months[item.MonthKey]
+			if !ok {
+				if month, err = im.journalMonth(item.MonthKey); err != nil {
+					return JournalPlan{}, err
+				}
+				months[item.MonthKey] = month
+			}
+
+			switch {
+			case !item.Amount.IsPositive():
+				item.Status = StatusSkipped
+				item.Reason = "refund or zero amount"
+			case income:
+				if hasIncome(month.incomes, item.RecordID) {
+					item.Status = StatusDuplicate
+				} else if err := service.ValidateIncome(journalIncome(item)); err != nil {
+					item.Status = StatusSkipped
+					item.Reason = err.Error()
+				} else {
+					item.Status = StatusNew
+				}
+			default:
+				groupID := findGroup(groups, item.Group)
+
+				category, found := findGroupCategory(month.categories, groupID, item.Category)
+				expense, exists := category.Expense[category.CatID]
+				planned := strings.ToLower(item.MonthKey + "|" + item.Group + "|" + item.Category)
+				expenseCurrency, isPlanned := plannedCurrencies[planned]
+				if exists {
+					expenseCurrency = domain.NormalizeCurrency(expense.Currency)
+					if expenseCurrency == currency {
+						expenseCurrency = ""
+					}
+				}
+
+				switch {
+				case found && hasEntry(category, item.RecordID):
+					item.Status = StatusDuplicate
+				case (exists || isPlanned) && expenseCurrency != item.Currency:
+					item.Status = StatusSkipped
+					item.Reason = fmt.Sprintf("expense is in %s", displayCurrency(expenseCurrency, currency))
+				default:
+					if err := validateJournalExpense(item, groupID == "", !found); err != nil {
+						item.Status = StatusSkipped
+						item.Reason = err.Error()
+						break
+					}
+					item.Status = StatusNew
+					if groupID == "" && !plannedGroups[strings.ToLower(item.Group)] {
+						item.NewGroup = true
+						plannedGroups[strings.ToLower(item.Group)] = true
+					}
+					if !found && !isPlanned {
+						item.NewCategory = true
+					}
+					if !exists && !isPlanned {
+						plannedCurrencies[planned] = item.Currency
+					}
+				}
+			}
+			plan.Items = append(plan.Items, item)
+		}
+	}
+	return plan, nil
+}
+
+// ApplyJournal imports the new items of a plan and returns how many were
+// imported. Groups and categories missing from a month are created. The items
+// are imported in a single write, so nothing is imported when one of them
+// fails.
+func (im *Importer) ApplyJournal(plan JournalPlan) (int, error) {
+	count := 0
+	err := im.categorySvc.Atomically(func() error {
+		groups, err := im.groupSvc.GetAllGroups()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range plan.Items {
+			if item.Status != StatusNew {
+				continue
+			}
+
+			if item.Income {
+				incomes, err := im.incomeSvc.GetIncomesForMonth(item.MonthKey)
+				if err != nil {
+					return err
+				}
+				if hasIncome(incomes, item.RecordID) {
+					continue
+				}
+				if err := im.incomeSvc.AddIncome(item.MonthKey, journalIncome(item)); err != nil {
+					return fmt.Errorf("failed to import '%s' into %s: %w", item.Payee, item.Account, err)
+				}
+				count++
+				continue
+			}
+
+			groupID := findGroup(groups, item.Group)
+			if groupID == "" {
+				group := domain.CategoryGroup{GroupID: ui.GenerateID(), GroupName: item.Group, Order: nextGroupOrder(groups)}
+				if err := im.groupSvc.AddGroup(group); err != nil {
+					return fmt.Errorf("failed to create group %s: %w", item.Group, err)
+				}
+				groups = append(groups, group)
+				groupID = group.GroupID
+			}
+
+			categories, err := im.categorySvc.GetCategoriesForMonth(item.MonthKey)
+			if err != nil {
+				return err
+			}
+			category, found := findGroupCategory(categories, groupID, item.Category)
+			if found && hasEntry(category, item.RecordID) {
+				continue
+			}
+			if !found {
+				category = domain.Category{CatID: ui.GenerateID(), GroupID: groupID, CategoryName: item.Category}
+			}
+
+			entry := domain.ExpenseEntry{EntryID: item.RecordID, Date: item.Date, Description: item.Payee, Amount: item.Amount}
+			if err := im.addEntry(item.MonthKey, category, found, entry, item.Currency); err != nil {
+				return fmt.Errorf("failed to import '%s' into %s: %w", item.Payee, item.Account, err)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// journalIncome returns the income item imports. Incomes without a payee are
+// described by the last segment of their account.
+func journalIncome(item JournalItem) domain.IncomeRecord {
+	income := domain.IncomeRecord{
+		IncomeID:    item.RecordID,
+		Description: item.Payee,
+		Amount:      item.Amount,
+		Currency:    item.Currency,
+	}
+	if income.Description == "" {
+		income.Description = accountLeaf(item.Account)
+	}
+	return income
+}
+
+// validateJournalExpense checks the entry an expense item imports, and the
+// names of its group and category when they are created.
+func validateJournalExpense(item JournalItem, newGroup, newCategory bool) error {
+	if err := service.ValidateEntry(domain.ExpenseEntry{Description: item.Payee, Amount: item.Amount}); err != nil {
+		return err
+	}
+	if newGroup {
+		if err := service.ValidateName("group name", item.Group); err != nil {
+			return err
+		}
+	}
+	if newCategory {
+		return service.ValidateName("category name", item.Category)
+	}
+	return nil
+}
+
+// journalMonth holds the records of a month looked up while planning.
+type journalMonth struct {
+	categories []domain.Category
+	incomes    []domain.IncomeRecord
+}
+
+// journalMonth returns the categories and incomes of monthKey.
+func (im *Importer) journalMonth(monthKey string) (journalMonth, error) {
+	categories, err := im.categorySvc.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return journalMonth{}, err
+	}
+	incomes, err := im.incomeSvc.GetIncomesForMonth(monthKey)
+	if err != nil {
+		return journalMonth{}, err
+	}
+	return journalMonth{categories: categories, incomes: incomes}, nil
+}
+
+// accountCategory returns the group and category of an expense account
+// below root. Accounts with a single level below root, such as
+// Expenses:Rent, are imported into a group named after root.
+func accountCategory(root, rest string) (group, category string) {
+	group, category, found := strings.Cut(rest, ":")
+	if !found || category == "" {
+		return root, group
+	}
+	return group, category
+}
+
+// accountLeaf returns the last segment of an account.
+func accountLeaf(account string) string {
+	return account[strings.LastIndex(account, ":")+1:]
+}
+
+// journalCurrency returns the currency code of a commodity, or an empty
+// string for the default currency and amounts without a commodity.
+func journalCurrency(commodity, defaultCurrency string) string {
+	code, ok := commoditySymbols[commodity]
+	if !ok {
+		code = domain.NormalizeCurrency(commodity)
+	}
+	if code == defaultCurrency {
+		return ""
+	}
+	return code
+}
+
+// displayCurrency returns the code of currency, which is empty for the default one.
+func displayCurrency(currency, defaultCurrency string) string {
+	if currency == "" {
+		return defaultCurrency
+	}
+	return currency
+}
+
+// findGroupCategory returns the category named name, case-insensitively,
+// in the group groupID.
+func findGroupCategory(categories []domain.Category, groupID, name string) (domain.Category, bool) {
+	if groupID == "" {
+		return domain.Category{}, false
+	}
+	for _, category := range categories {
+		if category.GroupID == groupID && strings.EqualFold(category.CategoryName, name) {
+			return category, true
+		}
+	}
+	return domain.Category{}, false
+}
+
+// hasIncome reports whether incomes hold the income incomeID.
+func hasIncome(incomes []domain.IncomeRecord, incomeID string) bool {
+	for _, income := range incomes {
+		if income.IncomeID == incomeID {
+			return true
+		}
+	}
+	return false
+}
+
+// nextGroupOrder returns the order of a group added after groups.
+func nextGroupOrder(groups []domain.CategoryGroup) int {
+	order := 0
+	for _, group := range groups {
+		order = max(order, group.Order)
+	}
+	return order + 1
+}
+
+// containsFold reports whether values hold value, case-insensitively.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
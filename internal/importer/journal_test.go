@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/data"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/madalinpopa/gocost/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ledgerJournal = `; Household journal
+account Expenses:Living:Groceries
+
+2024/06/03 * (1042) Tesco | weekly shop  ; cleared
+    Expenses:Living:Groceries      $42.10
+    Assets:Checking
+
+2024-06-05 Landlord
+    Expenses:Rent                  1,200.00 USD
+    (Budget:Rent)                  -1,200.00 USD
+    Assets:Checking               -1,200.00 USD
+
+2024-06-28 Employer
+    Assets:Checking                $2500
+    Income:Salary
+`
+
+const beancountJournal = `option "operating_currency" "EUR"
+
+2024-06-01 open Assets:Bank EUR
+
+2024-06-03 * "Tesco" "Weekly shop"
+  id: "abc"
+  Expenses:Living:Groceries   42.10 EUR
+  Assets:Bank                -42.10 EUR
+
+2024-06-10 txn "Museum tickets"
+  Expenses:Leisure:Outings    30 USD @ 0.92 EUR
+  Assets:Bank
+
+2024-06-30 balance Assets:Bank 100 EUR
+`
+
+func TestReadJournal(t *testing.T) {
+	t.Run("ledger", func(t *testing.T) {
+		transactions, err := ReadJournal(strings.NewReader(ledgerJournal), FormatLedger)
+		require.NoError(t, err)
+		require.Len(t, transactions, 3)
+
+		assert.Equal(t, "Tesco", transactions[0].Payee)
+		assert.Equal(t, 4, transactions[0].Line)
+		require.Len(t, transactions[0].Postings, 2)
+		assert.Equal(t, Posting{Account: "Expenses:Living:Groceries", Amount: transactions[0].Postings[0].Amount, Commodity: "$"}, transactions[0].Postings[0])
+		assert.Equal(t, "42.1", transactions[0].Postings[0].Amount.String())
+		assert.Equal(t, "-42.1", transactions[0].Postings[1].Amount.String())
+
+		// Virtual postings are left out
+		require.Len(t, transactions[1].Postings, 2)
+		assert.Equal(t, "1200", transactions[1].Postings[0].Amount.String())
+		assert.Equal(t, "USD", transactions[1].Postings[0].Commodity)
+
+		assert.Equal(t, "Income:Salary", transactions[2].Postings[1].Account)
+		assert.Equal(t, "-2500", transactions[2].Postings[1].Amount.String())
+	})
+
+	t.Run("beancount", func(t *testing.T) {
+		transactions, err := ReadJournal(strings.NewReader(beancountJournal), FormatBeancount)
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+
+		assert.Equal(t, "Tesco", transactions[0].Payee)
+		require.Len(t, transactions[0].Postings, 2)
+		assert.Equal(t, "EUR", transactions[0].Postings[0].Commodity)
+
+		assert.Equal(t, "Museum tickets", transactions[1].Payee)
+		assert.Equal(t, "30", transactions[1].Postings[0].Amount.String())
+		assert.Equal(t, "USD", transactions[1].Postings[0].Commodity)
+		assert.Equal(t, "Assets:Bank", transactions[1].Postings[1].Account)
+		assert.Equal(t, "-30", transactions[1].Postings[1].Amount.String())
+	})
+
+	t.Run("invalid journals", func(t *testing.T) {
+		_, err := ReadJournal(strings.NewReader("2024-13-01 Shop\n    Expenses:Food  $1\n"), FormatLedger)
+		assert.ErrorContains(t, err, "line 1")
+
+		_, err = ReadJournal(strings.NewReader("2024-06-01 Shop\n    Expenses:Food  $1\n    Expenses:Fees  1 EUR\n    Assets:Bank\n"), FormatLedger)
+		assert.ErrorContains(t, err, "several commodities")
+
+		_, err = ReadJournal(strings.NewReader("2024-06-01 Shop\n    Expenses:Food\n    Assets:Bank\n"), FormatLedger)
+		assert.ErrorContains(t, err, "only one posting")
+
+		_, err = ReadJournal(strings.NewReader(""), "gnucash")
+		assert.Error(t, err)
+	})
+}
+
+func TestImporter_PlanAndApplyJournal(t *testing.T) {
+	repo, err := data.NewJsonRepository(filepath.Join(t.TempDir(), "test_data.json"), "USD")
+	require.NoError(t, err)
+	categorySvc := service.NewCategoryService(repo)
+	groupSvc := service.NewGroupService(repo)
+	incomeSvc := service.NewIncomeService(repo)
+	require.NoError(t, groupSvc.AddGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "Living", Order: 1}))
+	require.NoError(t, categorySvc.AddCategory("2024-06", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Groceries"}))
+
+	journal := ledgerJournal + `
+2024-06-12 Refund
+    Expenses:Living:Groceries     $-5
+    Assets:Checking
+
+2024-07-02 Bookshop
+    Expenses:Leisure:Books         20 EUR
+    Assets:Checking
+`
+	transactions, err := ReadJournal(strings.NewReader(journal), FormatLedger)
+	require.NoError(t, err)
+
+	im := New(categorySvc, groupSvc, incomeSvc)
+	plan, err := im.PlanJournal(transactions, "usd")
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 5)
+	assert.Equal(t, 4, plan.Count(StatusNew))
+	assert.Equal(t, 1, plan.Count(StatusSkipped))
+	groups, categories := plan.Created()
+	assert.Equal(t, 2, groups)
+	assert.Equal(t, 2, categories)
+
+	assert.Equal(t, "Expenses", plan.Items[1].Group)
+	assert.Equal(t, "Rent", plan.Items[1].Category)
+	assert.True(t, plan.Items[2].Income)
+	assert.Equal(t, "2500", plan.Items[2].Amount.String())
+	assert.Equal(t, "EUR", plan.Items[4].Currency)
+	// Skipped refunds still name the record they would go to
+	assert.Equal(t, StatusSkipped, plan.Items[3].Status)
+	assert.Equal(t, "Living", plan.Items[3].Group)
+	assert.Equal(t, "Groceries", plan.Items[3].Category)
+
+	// A failing item leaves nothing imported
+	failing := JournalPlan{Items: append(append([]JournalItem{}, plan.Items...), JournalItem{
+		MonthKey: "2024-06", Income: true, Amount: plan.Items[2].Amount, RecordID: "broken", Status: StatusNew,
+	})}
+	_, err = im.ApplyJournal(failing)
+	require.ErrorIs(t, err, domain.ErrInvalid)
+	june, err := categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, june, 1)
+	assert.Empty(t, june[0].Expense)
+	allGroups, err := groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	assert.Len(t, allGroups, 1)
+
+	count, err := im.ApplyJournal(plan)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	june, err = categorySvc.GetCategoriesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, june, 2)
+	assert.Equal(t, "42.1", june[0].Expense["c1"].Amount.String())
+	assert.Equal(t, "Rent", june[1].CategoryName)
+	assert.Equal(t, "1200", june[1].Expense[june[1].CatID].Amount.String())
+
+	incomes, err := incomeSvc.GetIncomesForMonth("2024-06")
+	require.NoError(t, err)
+	require.Len(t, incomes, 1)
+	assert.Equal(t, "Employer", incomes[0].Description)
+	assert.Equal(t, "2500", incomes[0].Amount.String())
+
+	july, err := categorySvc.GetCategoriesForMonth("2024-07")
+	require.NoError(t, err)
+	require.Len(t, july, 1)
+	assert.Equal(t, "EUR", july[0].Expense[july[0].CatID].Currency)
+
+	allGroups, err = groupSvc.GetAllGroups()
+	require.NoError(t, err)
+	assert.Len(t, allGroups, 3)
+
+	// Importing the same journal again finds only duplicates
+	plan, err = im.PlanJournal(transactions, "USD")
+	require.NoError(t, err)
+	assert.Zero(t, plan.Count(StatusNew))
+	assert.Equal(t, 4, plan.Count(StatusDuplicate))
+
+	// Postings in another currency than their expense are skipped
+	transactions, err = ReadJournal(strings.NewReader("2024-07-20 Bookshop\n    Expenses:Leisure:Books  $12\n    Assets:Checking\n"), FormatLedger)
+	require.NoError(t, err)
+	plan, err = im.PlanJournal(transactions, "USD")
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, StatusSkipped, plan.Items[0].Status)
+	assert.Equal(t, "expense is in EUR", plan.Items[0].Reason)
+
+	// Postings the services would refuse are skipped in the preview
+	journal = "2024-07-21 Shop\n    Expenses:Leisure:" + strings.Repeat("X", 51) + "  20 EUR\n    Assets:Checking\n"
+	transactions, err = ReadJournal(strings.NewReader(journal), FormatLedger)
+	require.NoError(t, err)
+	plan, err = im.PlanJournal(transactions, "USD")
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, StatusSkipped, plan.Items[0].Status)
+	assert.Equal(t, "category name cannot be longer than 50 characters", plan.Items[0].Reason)
+	_, categories = plan.Created()
+	assert.Zero(t, categories)
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// ReadOFX reads the transactions of a bank or credit card statement in OFX
+// format, both the SGML form of OFX 1, where elements are not closed, and
+// the XML form of OFX 2. QFX files are OFX files and are read as well.
+func ReadOFX(r io.Reader) ([]Transaction, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+
+	var transactions []Transaction
+	var fields map[string]string // Elements of the transaction being read
+	// Every tag starts with "<", and what precedes the first one is the header
+	for _, token := range strings.Split(string(content), "<")[1:] {
+		tag, value, _ := strings.Cut(token, ">")
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+
+		switch {
+		case tag == "STMTTRN":
+			fields = make(map[string]string)
+		case tag == "/STMTTRN":
+			if fields == nil {
+				continue
+			}
+			transaction, err := ofxTransaction(fields)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: %w", len(transactions)+1, err)
+			}
+			transactions = append(transactions, transaction)
+			fields = nil
+		case fields != nil && !strings.HasPrefix(tag, "/"):
+			// The NAME of a transaction comes before the one of its PAYEE
+			if _, exists := fields[tag]; !exists {
+				fields[tag] = html.UnescapeString(strings.TrimSpace(value))
+			}
+		}
+	}
+	if fields != nil {
+		return nil, fmt.Errorf("transaction %d is not closed", len(transactions)+1)
+	}
+	return transactions, nil
+}
+
+// ofxTransaction returns the transaction made of the elements of a STMTTRN.
+func ofxTransaction(fields map[string]string) (Transaction, error) {
+	posted := fields["DTPOSTED"]
+	if len(posted) < 8 {
+		return Transaction{}, fmt.Errorf("invalid date %q", posted)
+	}
+	// Dates are written as YYYYMMDD, followed by an optional time and time zone
+	date, err := time.Parse("20060102", posted[:8])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid date %q", posted)
+	}
+
+	value := fields["TRNAMT"]
+	amount, err := ParseAmount(value, strings.Contains(value, ",") && !strings.Contains(value, "."))
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	payee := fields["NAME"]
+	if payee == "" {
+		payee = fields["MEMO"]
+	}
+	return Transaction{
+		Date:   date,
+		Payee:  payee,
+		Amount: amount.Neg(), // Statements credit received money and debit spent money
+		ID:     fields["FITID"],
+	}, nil
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOFX(t *testing.T) {
+	t.Run("sgml", func(t *testing.T) {
+		statement := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<BANKTRANLIST>
+<DTSTART>20240601
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240603120000.000[-5:EST]
+<TRNAMT>-42.10
+<FITID>2024060301
+<NAME>TESCO STORES &amp; CO
+<MEMO>Card payment
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240628
+<TRNAMT>2500.00
+<FITID>2024062801
+<MEMO>Salary
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+		transactions, err := ReadOFX(strings.NewReader(statement))
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		assert.Equal(t, "TESCO STORES & CO", transactions[0].Payee)
+		assert.Equal(t, "42.1", transactions[0].Amount.String())
+		assert.Equal(t, "2024060301", transactions[0].ID)
+		assert.Equal(t, 3, transactions[0].Date.Day())
+		assert.Equal(t, "Salary", transactions[1].Payee)
+		assert.Equal(t, "-2500", transactions[1].Amount.String())
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		statement := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20240605</DTPOSTED><TRNAMT>-15,99</TRNAMT><FITID>A1</FITID><NAME>NETFLIX.COM</NAME></STMTTRN>
+</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1></OFX>
+`
+		transactions, err := ReadOFX(strings.NewReader(statement))
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, "NETFLIX.COM", transactions[0].Payee)
+		assert.Equal(t, "15.99", transactions[0].Amount.String())
+	})
+
+	t.Run("invalid statements", func(t *testing.T) {
+		_, err := ReadOFX(strings.NewReader("<STMTTRN><DTPOSTED>June<TRNAMT>-1</STMTTRN>"))
+		assert.ErrorContains(t, err, "transaction 1")
+
+		_, err = ReadOFX(strings.NewReader("<STMTTRN><DTPOSTED>20240603<TRNAMT>-1"))
+		assert.ErrorContains(t, err, "not closed")
+	})
+}
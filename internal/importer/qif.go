@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// qifDateLayouts are the layouts of QIF dates, once apostrophes, dashes and
+// dots are replaced with slashes. Dates are read month first, as written by
+// most software.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "2006/1/2"}
+
+// qifAccountTypes are the QIF types that list transactions. Other types,
+// such as categories and classes, are skipped.
+var qifAccountTypes = []string{"Bank", "Cash", "CCard", "Oth A", "Oth L"}
+
+// ReadQIF reads the transactions of a bank statement in QIF format. The
+// category of a transaction is kept, unless it is a transfer between accounts.
+func ReadQIF(r io.Reader) ([]Transaction, error) {
+	var transactions []Transaction
+	var current Transaction
+	var hasDate, hasAmount, skipped bool
+	start := 0 // Line of the first field of the current transaction
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if start == 0 {
+			start = line
+		}
+		code, value := text[0], strings.TrimSpace(text[1:])
+
+		switch {
+		case code == '!':
+			// Headers such as !Type:Bank tell what the records below list
+			kind, isType := strings.CutPrefix(value, "Type:")
+			skipped = !isType || !containsFold(qifAccountTypes, strings.TrimSpace(kind))
+			start = 0
+
+		case code == '^':
+			if !skipped {
+				if !hasDate || !hasAmount {
+					return nil, fmt.Errorf("line %d: transaction without a date or an amount", start)
+				}
+				transactions = append(transactions, current)
+			}
+			current, hasDate, hasAmount, start = Transaction{}, false, false, 0
+
+		case skipped:
+			continue
+
+		case code == 'D':
+			date, err := parseQIFDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			current.Date, hasDate = date, true
+
+		case code == 'T' || (code == 'U' && !hasAmount):
+			amount, err := ParseAmount(value, false)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			// Spent money is negative in QIF files
+			current.Amount, hasAmount = amount.Neg(), true
+
+		case code == 'P':
+			current.Payee = value
+
+		case code == 'M':
+			if current.Payee == "" {
+				current.Payee = value
+			}
+
+		case code == 'L':
+			// Transfers are written as [Account], and classes follow a slash
+			category, _, _ := strings.Cut(value, "/")
+			if !strings.HasPrefix(category, "[") {
+				current.Category = strings.TrimSpace(category)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+	// The last transaction may lack its closing ^
+	if hasDate && hasAmount && !skipped {
+		transactions = append(transactions, current)
+	}
+	return transactions, nil
+}
+
+// parseQIFDate parses dates such as 6/3/2024, 6/ 3'24 or 2024-06-03.
+func parseQIFDate(value string) (time.Time, error) {
+	normalized := strings.NewReplacer("'", "/", "-", "/", ".", "/", " ", "").Replace(value)
+	for _, layout := range qifDateLayouts {
+		if date, err := time.Parse(layout, normalized); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadQIF(t *testing.T) {
+	t.Run("bank statement", func(t *testing.T) {
+		statement := `!Type:Cat
+NGroceries
+D Food bought in shops
+E
+^
+!Type:Bank
+D6/ 3'24
+T-1,042.10
+PTesco
+LGroceries/Family
+^
+D06/05/2024
+U-15.99
+MNetflix
+^
+D2024-06-06
+T-200.00
+PTransfer to savings
+L[Savings]
+^
+D6/28/24
+T2500.00
+PEmployer
+`
+		transactions, err := ReadQIF(strings.NewReader(statement))
+		require.NoError(t, err)
+		require.Len(t, transactions, 4)
+
+		assert.Equal(t, "Tesco", transactions[0].Payee)
+		assert.Equal(t, "1042.1", transactions[0].Amount.String())
+		assert.Equal(t, "Groceries", transactions[0].Category)
+		assert.Equal(t, "2024-06-03", transactions[0].Date.Format("2006-01-02"))
+
+		assert.Equal(t, "Netflix", transactions[1].Payee)
+		assert.Equal(t, 5, transactions[1].Date.Day())
+
+		// Transfers between accounts have no category
+		assert.Empty(t, transactions[2].Category)
+
+		assert.Equal(t, "-2500", transactions[3].Amount.String())
+		assert.Equal(t, 2024, transactions[3].Date.Year())
+	})
+
+	t.Run("invalid statements", func(t *testing.T) {
+		_, err := ReadQIF(strings.NewReader("!Type:Bank\nDJune\nT-1\n^\n"))
+		assert.ErrorContains(t, err, "line 2")
+
+		_, err = ReadQIF(strings.NewReader("!Type:Bank\nPShop\nT-1\n^\n"))
+		assert.ErrorContains(t, err, "without a date")
+	})
+}
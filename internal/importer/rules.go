@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rule assigns the transactions whose payee matches Pattern to Category.
+// Patterns are case-insensitive regular expressions. When Group is set, the
+// category is created in that group for months that do not have it yet.
+type Rule struct {
+	Pattern  string `mapstructure:"pattern" json:"pattern"`
+	Category string `mapstructure:"category" json:"category"`
+	Group    string `mapstructure:"group" json:"group,omitempty"`
+}
+
+// compiledRule is a rule with its pattern compiled.
+type compiledRule struct {
+	Rule
+	re *regexp.Regexp
+}
+
+// RuleSet matches payees against rules in order.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// CompileRules compiles the patterns of rules into a RuleSet.
+func CompileRules(rules []Rule) (RuleSet, error) {
+	var set RuleSet
+	for i, rule := range rules {
+		if rule.Pattern == "" || rule.Category == "" {
+			return RuleSet{}, fmt.Errorf("rule %d: pattern and category are required", i+1)
+		}
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return RuleSet{}, fmt.Errorf("rule %d: invalid pattern %q: %w", i+1, rule.Pattern, err)
+		}
+		set.rules = append(set.rules, compiledRule{Rule: rule, re: re})
+	}
+	return set, nil
+}
+
+// Len returns the number of rules in the set.
+func (s RuleSet) Len() int {
+	return len(s.rules)
+}
+
+// Match returns the first rule whose pattern matches payee.
+func (s RuleSet) Match(payee string) (Rule, bool) {
+	for _, rule := range s.rules {
+		if rule.re.MatchString(payee) {
+			return rule.Rule, true
+		}
+	}
+	return Rule{}, false
+}
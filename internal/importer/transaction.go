@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transaction is a single movement read from a bank statement. Amount is
+// positive for money spent and negative for money received.
+type Transaction struct {
+	Date     time.Time
+	Payee    string
+	Amount   decimal.Decimal
+	ID       string // ID given by the bank, such as the FITID of OFX statements
+	Category string // Category given by the statement, such as the L field of QIF files
+}
+
+// statementReaders maps the extensions of statement files to the function
+// reading them.
+var statementReaders = map[string]func(io.Reader) ([]Transaction, error){
+	".ofx": ReadOFX,
+	".qfx": ReadOFX,
+	".qif": ReadQIF,
+}
+
+// ReadStatement reads the transactions of the OFX, QFX or QIF statement at
+// path, telling its format from the extension of the file.
+func ReadStatement(path string) ([]Transaction, error) {
+	read, ok := statementReaders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("unknown statement format of %s, expected an .ofx, .qfx or .qif file", filepath.Base(path))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	transactions, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return transactions, nil
+}
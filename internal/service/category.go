@@ -19,12 +19,17 @@ func (s *CategoryService) GetCategoriesForMonth(monthKey string) ([]domain.Categ
 
 // AddCategory adds a new category for a given month.
 func (s *CategoryService) AddCategory(monthKey string, category domain.Category) error {
-	// Future validation logic could go here.
+	if err := s.validateCategory(monthKey, category); err != nil {
+		return err
+	}
 	return s.repo.AddCategory(monthKey, category)
 }
 
 // UpdateCategory updates an existing category for a given month.
 func (s *CategoryService) UpdateCategory(monthKey string, category domain.Category) error {
+	if err := s.validateCategory(monthKey, category); err != nil {
+		return err
+	}
 	return s.repo.UpdateCategory(monthKey, category)
 }
 
@@ -37,3 +42,65 @@ func (s *CategoryService) DeleteCategory(monthKey string, categoryID string) err
 func (s *CategoryService) CopyCategoriesFromMonth(fromMonthKey, toMonthKey string) (int, error) {
 	return s.repo.CopyCategoriesFromMonth(fromMonthKey, toMonthKey)
 }
+
+// Atomically runs fn as a unit of work of the repository, see
+// domain.UnitOfWork. Changes made through any service sharing the repository
+// while fn runs are saved together, or not at all when fn fails.
+func (s *CategoryService) Atomically(fn func() error) error {
+	return atomically(s.repo, fn)
+}
+
+// ReplaceCategoriesForMonth replaces the categories of a month, together
+// with their expenses, by categories in a single write.
+func (s *CategoryService) ReplaceCategoriesForMonth(monthKey string, categories []domain.Category) error {
+	return s.Atomically(func() error {
+		existing, err := s.repo.GetCategoriesForMonth(monthKey)
+		if err != nil {
+			return err
+		}
+		for _, category := range existing {
+			if err := s.repo.DeleteCategory(monthKey, category.CatID); err != nil {
+				return err
+			}
+		}
+		for _, category := range categories {
+			if err := s.repo.AddCategory(monthKey, category.Clone()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateCategory checks the name and group of category, which must be
+// unique within its group in the month, and the fields of its expense.
+func (s *CategoryService) validateCategory(monthKey string, category domain.Category) error {
+	var v validation
+	v.name(domain.FieldName, "category name", category.CategoryName)
+	if category.GroupID == "" {
+		v.fail(domain.FieldGroup, "category group cannot be empty")
+	}
+
+	if !v.failed(domain.FieldName) && !v.failed(domain.FieldGroup) {
+		categories, err := s.repo.GetCategoriesForMonth(monthKey)
+		if err != nil {
+			return err
+		}
+		for _, existing := range categories {
+			if existing.CatID != category.CatID && existing.GroupID == category.GroupID &&
+				sameName(existing.CategoryName, category.CategoryName) {
+				v.fail(domain.FieldName, "a category named '%s' already exists in this group", existing.CategoryName)
+			}
+		}
+	}
+
+	if expense, ok := category.Expense[category.CatID]; ok {
+		v.amount(domain.FieldAmount, "amount", expense.Amount)
+		v.amount(domain.FieldBudget, "budget", expense.Budget)
+		v.length(domain.FieldNotes, "notes", expense.Notes, maxNotesLength)
+		for _, entry := range expense.Entries {
+			v.entry(entry)
+		}
+	}
+	return v.err()
+}
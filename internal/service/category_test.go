@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,7 +51,7 @@ func (m *mockCategoryRepo) CopyCategoriesFromMonth(fromMonthKey, toMonthKey stri
 }
 
 func TestCategoryService(t *testing.T) {
-	mockCat := domain.Category{CatID: "c1", CategoryName: "Test"}
+	mockCat := domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Test"}
 	mockRepo := &mockCategoryRepo{
 		categories: []domain.Category{mockCat},
 	}
@@ -62,7 +64,7 @@ func TestCategoryService(t *testing.T) {
 	})
 
 	t.Run("AddCategory", func(t *testing.T) {
-		newCat := domain.Category{CatID: "c2", CategoryName: "New Test"}
+		newCat := domain.Category{CatID: "c2", GroupID: "g1", CategoryName: "New Test"}
 		err := service.AddCategory("any-month", newCat)
 		require.NoError(t, err)
 		// Check if it was added to the mock's slice
@@ -78,3 +80,87 @@ func TestCategoryService(t *testing.T) {
 		assert.Equal(t, "db error", err.Error())
 	})
 }
+
+func TestCategoryService_Validation(t *testing.T) {
+	repo := &mockCategoryRepo{categories: []domain.Category{
+		{CatID: "c1", GroupID: "g1", CategoryName: "Rent"},
+		{CatID: "c2", GroupID: "g2", CategoryName: "Phone"},
+	}}
+	service := NewCategoryService(repo)
+
+	withExpense := func(category domain.Category, expense domain.ExpenseRecord) domain.Category {
+		category.Expense = map[string]domain.ExpenseRecord{category.CatID: expense}
+		return category
+	}
+
+	tests := []struct {
+		name     string
+		category domain.Category
+		fields   map[string]string
+	}{
+		{
+			name:     "Empty name and group",
+			category: domain.Category{CatID: "c3"},
+			fields: map[string]string{
+				domain.FieldName:  "category name cannot be empty",
+				domain.FieldGroup: "category group cannot be empty",
+			},
+		},
+		{
+			name:     "Duplicate name in the group",
+			category: domain.Category{CatID: "c3", GroupID: "g1", CategoryName: "rent"},
+			fields:   map[string]string{domain.FieldName: "a category named 'Rent' already exists in this group"},
+		},
+		{
+			name:     "Name longer than the limit",
+			category: domain.Category{CatID: "c3", GroupID: "g1", CategoryName: strings.Repeat("x", 51)},
+			fields:   map[string]string{domain.FieldName: "category name cannot be longer than 50 characters"},
+		},
+		{
+			name: "Invalid expense",
+			category: withExpense(domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}, domain.ExpenseRecord{
+				Amount: decimal.NewFromInt(-5),
+				Budget: decimal.NewFromInt(2_000_000_000),
+				Notes:  strings.Repeat("x", 501),
+				Entries: []domain.ExpenseEntry{
+					{EntryID: "e1", Amount: decimal.NewFromInt(-5)},
+				},
+			}),
+			fields: map[string]string{
+				domain.FieldAmount:  "amount cannot be negative",
+				domain.FieldBudget:  "budget cannot be more than 1000000000",
+				domain.FieldNotes:   "notes cannot be longer than 500 characters",
+				domain.FieldEntries: "entry amounts cannot be negative",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.AddCategory("2025-01", tt.category)
+			require.ErrorIs(t, err, domain.ErrInvalid)
+			assert.Equal(t, tt.fields, domain.FieldErrors(err))
+
+			err = service.UpdateCategory("2025-01", tt.category)
+			assert.Equal(t, tt.fields, domain.FieldErrors(err))
+		})
+	}
+	assert.Len(t, repo.categories, 2)
+
+	t.Run("Entry descriptions longer than names", func(t *testing.T) {
+		payee := "CARD PAYMENT TO AMAZON MARKETPLACE EU SARL ON 2024-06-03 REF 1234567890"
+		category := withExpense(domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "Rent"}, domain.ExpenseRecord{
+			Entries: []domain.ExpenseEntry{{EntryID: "e1", Description: payee, Amount: decimal.NewFromInt(5)}},
+		})
+		require.NoError(t, service.UpdateCategory("2025-01", category))
+
+		category.Expense["c1"].Entries[0].Description = strings.Repeat("x", 251)
+		err := service.UpdateCategory("2025-01", category)
+		assert.Equal(t, map[string]string{domain.FieldEntries: "entry descriptions cannot be longer than 250 characters"}, domain.FieldErrors(err))
+	})
+
+	t.Run("Same name in another group or the category itself", func(t *testing.T) {
+		require.NoError(t, service.AddCategory("2025-01", domain.Category{CatID: "c3", GroupID: "g2", CategoryName: "Rent"}))
+		require.NoError(t, service.UpdateCategory("2025-01", domain.Category{CatID: "c1", GroupID: "g1", CategoryName: "RENT"}))
+	})
+}
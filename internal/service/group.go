@@ -24,12 +24,17 @@ func (s *GroupService) GetGroupByID(groupID string) (domain.CategoryGroup, error
 
 // AddGroup adds a new category group.
 func (s *GroupService) AddGroup(group domain.CategoryGroup) error {
-	// Future validation logic can be added here.
+	if err := s.validateGroup(group); err != nil {
+		return err
+	}
 	return s.repo.AddGroup(group)
 }
 
 // UpdateGroup updates an existing category group.
 func (s *GroupService) UpdateGroup(group domain.CategoryGroup) error {
+	if err := s.validateGroup(group); err != nil {
+		return err
+	}
 	return s.repo.UpdateGroup(group)
 }
 
@@ -37,3 +42,23 @@ func (s *GroupService) UpdateGroup(group domain.CategoryGroup) error {
 func (s *GroupService) DeleteGroup(groupID string) error {
 	return s.repo.DeleteGroup(groupID)
 }
+
+// validateGroup checks that the name of group is set and used by no other group.
+func (s *GroupService) validateGroup(group domain.CategoryGroup) error {
+	var v validation
+	v.name(domain.FieldName, "group name", group.GroupName)
+	if v.failed(domain.FieldName) {
+		return v.err()
+	}
+
+	groups, err := s.repo.GetAllGroups()
+	if err != nil {
+		return err
+	}
+	for _, existing := range groups {
+		if existing.GroupID != group.GroupID && sameName(existing.GroupName, group.GroupName) {
+			v.fail(domain.FieldName, "a group named '%s' already exists", existing.GroupName)
+		}
+	}
+	return v.err()
+}
@@ -68,3 +68,19 @@ func TestGroupService(t *testing.T) {
 		assert.Equal(t, "db error", err.Error())
 	})
 }
+
+func TestGroupService_Validation(t *testing.T) {
+	repo := &mockGroupRepo{groups: []domain.CategoryGroup{{GroupID: "g1", GroupName: "Housing"}}}
+	service := NewGroupService(repo)
+
+	err := service.AddGroup(domain.CategoryGroup{GroupID: "g2"})
+	require.ErrorIs(t, err, domain.ErrInvalid)
+	assert.Equal(t, map[string]string{domain.FieldName: "group name cannot be empty"}, domain.FieldErrors(err))
+
+	err = service.AddGroup(domain.CategoryGroup{GroupID: "g2", GroupName: " housing "})
+	assert.Equal(t, map[string]string{domain.FieldName: "a group named 'Housing' already exists"}, domain.FieldErrors(err))
+	assert.Len(t, repo.groups, 1)
+
+	// A group keeps its own name when renamed
+	require.NoError(t, service.UpdateGroup(domain.CategoryGroup{GroupID: "g1", GroupName: "HOUSING"}))
+}
@@ -19,11 +19,17 @@ func (s *IncomeService) GetIncomesForMonth(monthKey string) ([]domain.IncomeReco
 
 // AddIncome adds a new income record for a given month.
 func (s *IncomeService) AddIncome(monthKey string, income domain.IncomeRecord) error {
+	if err := ValidateIncome(income); err != nil {
+		return err
+	}
 	return s.repo.AddIncome(monthKey, income)
 }
 
 // UpdateIncome updates an existing income record for a given month.
 func (s *IncomeService) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
+	if err := ValidateIncome(income); err != nil {
+		return err
+	}
 	return s.repo.UpdateIncome(monthKey, income)
 }
 
@@ -31,3 +37,14 @@ func (s *IncomeService) UpdateIncome(monthKey string, income domain.IncomeRecord
 func (s *IncomeService) DeleteIncome(monthKey string, incomeID string) error {
 	return s.repo.DeleteIncome(monthKey, incomeID)
 }
+
+// ValidateIncome checks that income has a description and a positive amount.
+func ValidateIncome(income domain.IncomeRecord) error {
+	var v validation
+	v.required(domain.FieldDescription, "income description", income.Description, maxDescriptionLength)
+	if !income.Amount.IsPositive() {
+		v.fail(domain.FieldAmount, "income amount must be greater than zero")
+	}
+	v.amount(domain.FieldAmount, "income amount", income.Amount)
+	return v.err()
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,7 +55,7 @@ func TestIncomeService(t *testing.T) {
 	})
 
 	t.Run("AddIncome", func(t *testing.T) {
-		newIncome := domain.IncomeRecord{IncomeID: "i2", Description: "Bonus"}
+		newIncome := domain.IncomeRecord{IncomeID: "i2", Description: "Bonus", Amount: decimal.NewFromInt(500)}
 		err := service.AddIncome("any-month", newIncome)
 		require.NoError(t, err)
 		assert.Len(t, mockRepo.incomes, 2)
@@ -67,3 +69,49 @@ func TestIncomeService(t *testing.T) {
 		assert.Equal(t, "db error", err.Error())
 	})
 }
+
+func TestIncomeService_Validation(t *testing.T) {
+	repo := &mockIncomeRepo{}
+	service := NewIncomeService(repo)
+
+	tests := []struct {
+		name   string
+		income domain.IncomeRecord
+		fields map[string]string
+	}{
+		{
+			name:   "Empty description and zero amount",
+			income: domain.IncomeRecord{IncomeID: "i1", Description: "  "},
+			fields: map[string]string{
+				domain.FieldDescription: "income description cannot be empty",
+				domain.FieldAmount:      "income amount must be greater than zero",
+			},
+		},
+		{
+			name:   "Absurd amount",
+			income: domain.IncomeRecord{IncomeID: "i1", Description: "Salary", Amount: decimal.NewFromInt(5_000_000_000)},
+			fields: map[string]string{domain.FieldAmount: "income amount cannot be more than 1000000000"},
+		},
+		{
+			name:   "Description longer than the limit",
+			income: domain.IncomeRecord{IncomeID: "i1", Description: strings.Repeat("x", 251), Amount: decimal.NewFromInt(100)},
+			fields: map[string]string{domain.FieldDescription: "income description cannot be longer than 250 characters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.AddIncome("2025-01", tt.income)
+			require.ErrorIs(t, err, domain.ErrInvalid)
+			assert.Equal(t, tt.fields, domain.FieldErrors(err))
+
+			err = service.UpdateIncome("2025-01", tt.income)
+			assert.Equal(t, tt.fields, domain.FieldErrors(err))
+		})
+	}
+	assert.Empty(t, repo.incomes)
+
+	// Descriptions of imported incomes are longer than names
+	payee := "SEPA CREDIT TRANSFER ACME CORPORATION LTD SALARY JUNE 2024 REF 00012345"
+	require.NoError(t, service.AddIncome("2025-01", domain.IncomeRecord{IncomeID: "i2", Description: payee, Amount: decimal.NewFromInt(100)}))
+}
@@ -0,0 +1,18 @@
+package service
+
+import "github.com/madalinpopa/gocost/internal/domain"
+
+// JournalService gives access to the journal of the changes made to the data.
+type JournalService struct {
+	repo domain.JournalRepository
+}
+
+// NewJournalService creates a new JournalService.
+func NewJournalService(r domain.JournalRepository) *JournalService {
+	return &JournalService{repo: r}
+}
+
+// GetChanges returns the changes selected by filter, newest first.
+func (s *JournalService) GetChanges(filter domain.ChangeFilter) ([]domain.Change, error) {
+	return s.repo.GetChanges(filter)
+}
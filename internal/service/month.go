@@ -0,0 +1,48 @@
+package service
+
+import (
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// MonthService encapsulates business logic for queries spanning several months.
+type MonthService struct {
+	repo domain.MonthRepository
+}
+
+// NewMonthService creates a new MonthService.
+func NewMonthService(r domain.MonthRepository) *MonthService {
+	return &MonthService{repo: r}
+}
+
+// GetMonths retrieves the months holding any data, oldest first.
+func (s *MonthService) GetMonths() ([]domain.Month, error) {
+	return s.repo.GetMonths()
+}
+
+// GetMonthRange retrieves the data of every month from from to to, both
+// included, oldest first. Months without data are returned empty, and the
+// bounds are swapped when to is before from.
+func (s *MonthService) GetMonthRange(from, to domain.Month) ([]domain.MonthData, error) {
+	if to.Before(from) {
+		from, to = to, from
+	}
+	stored, err := s.repo.GetMonthRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[domain.Month]domain.MonthData, len(stored))
+	for _, data := range stored {
+		byMonth[data.Month] = data
+	}
+
+	months := make([]domain.MonthData, 0, from.MonthsUntil(to)+1)
+	for month := from; !month.After(to); month = month.Next() {
+		data, ok := byMonth[month]
+		if !ok {
+			data = domain.MonthData{Month: month}
+		}
+		months = append(months, data)
+	}
+	return months, nil
+}
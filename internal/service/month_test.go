@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockMonthRepo is a mock implementation of the MonthRepository.
+type mockMonthRepo struct {
+	months []domain.MonthData
+	err    error
+}
+
+func (m *mockMonthRepo) GetMonths() ([]domain.Month, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var months []domain.Month
+	for _, data := range m.months {
+		months = append(months, data.Month)
+	}
+	return months, nil
+}
+func (m *mockMonthRepo) GetMonthRange(from, to domain.Month) ([]domain.MonthData, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var months []domain.MonthData
+	for _, data := range m.months {
+		if !data.Month.Before(from) && !data.Month.After(to) {
+			months = append(months, data)
+		}
+	}
+	return months, nil
+}
+
+func TestMonthService_GetMonthRange(t *testing.T) {
+	january := domain.NewMonth(2024, time.January)
+	mockRepo := &mockMonthRepo{months: []domain.MonthData{
+		{Month: domain.NewMonth(2023, time.December), MonthlyRecord: domain.MonthlyRecord{Incomes: []domain.IncomeRecord{{IncomeID: "i0"}}}},
+		{Month: january.AddMonths(1), MonthlyRecord: domain.MonthlyRecord{Incomes: []domain.IncomeRecord{{IncomeID: "i1"}}}},
+	}}
+	service := NewMonthService(mockRepo)
+
+	// Bounds are swapped and empty months filled in
+	months, err := service.GetMonthRange(january.AddMonths(2), january)
+	require.NoError(t, err)
+	require.Len(t, months, 3)
+	assert.Equal(t, "2024-01", months[0].Month.String())
+	assert.Empty(t, months[0].Incomes)
+	assert.Equal(t, "i1", months[1].Incomes[0].IncomeID)
+	assert.Equal(t, "2024-03", months[2].Month.String())
+
+	mockRepo.err = errors.New("db error")
+	_, err = service.GetMonthRange(january, january)
+	assert.Error(t, err)
+}
+
+func TestMonth(t *testing.T) {
+	month, err := domain.ParseMonth("2024-12")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01", month.Next().String())
+	assert.Equal(t, "2023-12", month.AddMonths(-12).String())
+	assert.Equal(t, 13, month.AddMonths(-1).MonthsUntil(month.AddMonths(12)))
+	assert.Equal(t, -1, month.Compare(month.Next()))
+	assert.Equal(t, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), month.Time())
+
+	_, err = domain.ParseMonth("December-2024")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package service
+
+import "github.com/madalinpopa/gocost/internal/domain"
+
+// RateService encapsulates business logic for the exchange rates of a month.
+type RateService struct {
+	repo domain.RateRepository
+}
+
+// NewRateService creates a new RateService.
+func NewRateService(r domain.RateRepository) *RateService {
+	return &RateService{repo: r}
+}
+
+// GetRatesForMonth retrieves all exchange rates for a given month.
+func (s *RateService) GetRatesForMonth(monthKey string) ([]domain.ExchangeRate, error) {
+	return s.repo.GetRatesForMonth(monthKey)
+}
+
+// SetRate adds or replaces the exchange rate of a currency for a given month.
+func (s *RateService) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	rate.Currency = domain.NormalizeCurrency(rate.Currency)
+	if rate.Currency == "" {
+		return &domain.ValidationError{Field: domain.FieldCurrency, Message: "currency cannot be empty"}
+	}
+	if !rate.Rate.IsPositive() {
+		return &domain.ValidationError{Field: domain.FieldRate, Message: "exchange rate must be greater than zero"}
+	}
+	return s.repo.SetRate(monthKey, rate)
+}
+
+// DeleteRate deletes the exchange rate of a currency for a given month.
+func (s *RateService) DeleteRate(monthKey string, currency string) error {
+	return s.repo.DeleteRate(monthKey, domain.NormalizeCurrency(currency))
+}
+
+// ConverterForMonth returns a converter using the exchange rates of a month,
+// expressed in the base currency.
+func (s *RateService) ConverterForMonth(monthKey string, base string) (domain.Converter, error) {
+	rates, err := s.repo.GetRatesForMonth(monthKey)
+	if err != nil {
+		return domain.Converter{}, err
+	}
+	return domain.NewConverter(base, rates), nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRateRepo is a mock implementation of the RateRepository.
+type mockRateRepo struct {
+	rates []domain.ExchangeRate
+	err   error
+}
+
+func (m *mockRateRepo) GetRatesForMonth(monthKey string) ([]domain.ExchangeRate, error) {
+	_ = monthKey
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rates, nil
+}
+func (m *mockRateRepo) SetRate(monthKey string, rate domain.ExchangeRate) error {
+	_ = monthKey
+	if m.err != nil {
+		return m.err
+	}
+	m.rates = append(m.rates, rate)
+	return nil
+}
+func (m *mockRateRepo) DeleteRate(monthKey string, currency string) error {
+	_, _ = monthKey, currency
+	return m.err
+}
+
+func TestRateService(t *testing.T) {
+	mockRepo := &mockRateRepo{}
+	service := NewRateService(mockRepo)
+
+	t.Run("SetRate normalizes the currency", func(t *testing.T) {
+		err := service.SetRate("any-month", domain.ExchangeRate{Currency: " usd ", Rate: decimal.RequireFromString("0.9")})
+		require.NoError(t, err)
+		require.Len(t, mockRepo.rates, 1)
+		assert.Equal(t, "USD", mockRepo.rates[0].Currency)
+	})
+
+	t.Run("SetRate rejects invalid rates", func(t *testing.T) {
+		err := service.SetRate("any-month", domain.ExchangeRate{Currency: "GBP", Rate: decimal.Zero})
+		assert.Error(t, err)
+		err = service.SetRate("any-month", domain.ExchangeRate{Currency: "", Rate: decimal.NewFromInt(1)})
+		assert.Error(t, err)
+	})
+
+	t.Run("ConverterForMonth", func(t *testing.T) {
+		converter, err := service.ConverterForMonth("any-month", "EUR")
+		require.NoError(t, err)
+
+		// 100 USD at 0.9 EUR each
+		amount, ok := converter.Convert(decimal.NewFromInt(100), "USD", "EUR")
+		require.True(t, ok)
+		assert.Equal(t, "90", amount.String())
+
+		amount, ok = converter.Convert(decimal.NewFromInt(90), "", "USD")
+		require.True(t, ok)
+		assert.Equal(t, "100", amount.String())
+
+		_, ok = converter.Convert(decimal.NewFromInt(1), "GBP", "EUR")
+		assert.False(t, ok)
+	})
+}
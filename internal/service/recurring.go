@@ -0,0 +1,388 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// RecurringService encapsulates business logic for recurring templates and
+// materializes them into the categories and incomes of a month.
+type RecurringService struct {
+	repo       domain.RecurringRepository
+	categories domain.CategoryRepository
+	incomes    domain.IncomeRepository
+}
+
+// NewRecurringService creates a new RecurringService.
+func NewRecurringService(r domain.RecurringRepository, c domain.CategoryRepository, i domain.IncomeRepository) *RecurringService {
+	return &RecurringService{repo: r, categories: c, incomes: i}
+}
+
+// GetAllTemplates retrieves all recurring templates.
+func (s *RecurringService) GetAllTemplates() ([]domain.RecurringTemplate, error) {
+	return s.repo.GetAllTemplates()
+}
+
+// AddTemplate adds a new recurring template.
+func (s *RecurringService) AddTemplate(template domain.RecurringTemplate) error {
+	template, err := validateTemplate(template)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddTemplate(template)
+}
+
+// UpdateTemplate updates an existing recurring template.
+func (s *RecurringService) UpdateTemplate(template domain.RecurringTemplate) error {
+	template, err := validateTemplate(template)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateTemplate(template)
+}
+
+// DeleteTemplate deletes a recurring template. Records it already created are kept.
+func (s *RecurringService) DeleteTemplate(templateID string) error {
+	return s.repo.DeleteTemplate(templateID)
+}
+
+// SkipOccurrence prevents a template from being applied to a month. When
+// the month was already applied, the income of the occurrence is deleted and
+// the expense is cleared, unless it was paid or has entries.
+func (s *RecurringService) SkipOccurrence(templateID string, monthKey string) error {
+	return s.updateOverride(templateID, monthKey, func(o *domain.TemplateOverride) {
+		o.Skip = true
+	})
+}
+
+// OverrideOccurrence changes the amount or budget used for a single month.
+// Nil values keep those of the template. When the month was already applied,
+// the income or expense of the occurrence is updated.
+func (s *RecurringService) OverrideOccurrence(templateID string, monthKey string, amount, budget *decimal.Decimal) error {
+	var v validation
+	if amount != nil {
+		v.amount(domain.FieldAmount, "amount", *amount)
+	}
+	if budget != nil {
+		v.amount(domain.FieldBudget, "budget", *budget)
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	return s.updateOverride(templateID, monthKey, func(o *domain.TemplateOverride) {
+		o.Skip = false
+		if amount != nil {
+			o.Amount = amount
+		}
+		if budget != nil {
+			o.Budget = budget
+		}
+	})
+}
+
+// updateOverride applies change to the override of a template for a month
+// and to the records of the occurrence, if it was already applied.
+func (s *RecurringService) updateOverride(templateID string, monthKey string, change func(o *domain.TemplateOverride)) error {
+	if err := domain.ValidateMonthKey(monthKey); err != nil {
+		return err
+	}
+	template, err := s.findTemplate(templateID)
+	if err != nil {
+		return err
+	}
+	overrides := make(map[string]domain.TemplateOverride, len(template.Overrides)+1)
+	for key, override := range template.Overrides {
+		overrides[key] = override
+	}
+	override := overrides[monthKey]
+	change(&override)
+	overrides[monthKey] = override
+	template.Overrides = overrides
+	if !override.Skip {
+		if err := validateOccurrence(monthKey, template); err != nil {
+			return err
+		}
+	}
+
+	return atomically(s.repo, func() error {
+		if err := s.repo.UpdateTemplate(template); err != nil {
+			return err
+		}
+		applied, err := s.repo.GetAppliedTemplates(monthKey)
+		if err != nil || !slices.Contains(applied, templateID) {
+			return err
+		}
+		switch template.Kind {
+		case domain.TemplateIncome:
+			return s.reapplyIncome(monthKey, template, override.Skip)
+		case domain.TemplateExpense:
+			return s.reapplyExpense(monthKey, template, override.Skip)
+		}
+		return nil
+	})
+}
+
+// reapplyIncome deletes the income of an applied occurrence when it is
+// skipped, and otherwise adds or updates it.
+func (s *RecurringService) reapplyIncome(monthKey string, template domain.RecurringTemplate, skip bool) error {
+	incomes, err := s.incomes.GetIncomesForMonth(monthKey)
+	if err != nil {
+		return err
+	}
+	income := occurrenceIncome(monthKey, template)
+	for _, existing := range incomes {
+		if existing.IncomeID != income.IncomeID {
+			continue
+		}
+		if skip {
+			return s.incomes.DeleteIncome(monthKey, income.IncomeID)
+		}
+		existing.Amount = income.Amount
+		return s.incomes.UpdateIncome(monthKey, existing)
+	}
+	if skip {
+		return nil
+	}
+	return s.incomes.AddIncome(monthKey, income)
+}
+
+// reapplyExpense clears the expense of an applied occurrence when it is
+// skipped, and otherwise updates its budget and, unless it is made up of
+// entries, its amount. Paid expenses and expenses with entries are not
+// cleared.
+func (s *RecurringService) reapplyExpense(monthKey string, template domain.RecurringTemplate, skip bool) error {
+	category, found, err := s.templateCategory(monthKey, template)
+	if err != nil || !found {
+		return err
+	}
+	expense, exists := category.Expense[category.CatID]
+	if !exists {
+		if skip {
+			return nil
+		}
+		return s.setExpense(monthKey, category, occurrenceExpense(monthKey, template))
+	}
+
+	expenses := make(map[string]domain.ExpenseRecord, len(category.Expense))
+	for key, record := range category.Expense {
+		expenses[key] = record
+	}
+	if skip {
+		if expense.Status == "Paid" || expense.HasEntries() {
+			return &domain.ValidationError{
+				Field:   domain.FieldAmount,
+				Message: fmt.Sprintf("the expense of '%s' in %s is paid or has entries, change it instead", category.CategoryName, monthKey),
+			}
+		}
+		delete(expenses, category.CatID)
+	} else {
+		amount, budget := template.ValuesFor(monthKey)
+		expense.Budget = budget
+		if !expense.HasEntries() {
+			expense.Amount = amount
+		}
+		expenses[category.CatID] = expense
+	}
+	category.Expense = expenses
+	return s.categories.UpdateCategory(monthKey, category)
+}
+
+// findTemplate returns the template with the given ID.
+func (s *RecurringService) findTemplate(templateID string) (domain.RecurringTemplate, error) {
+	templates, err := s.repo.GetAllTemplates()
+	if err != nil {
+		return domain.RecurringTemplate{}, err
+	}
+	for _, template := range templates {
+		if template.TemplateID == templateID {
+			return template, nil
+		}
+	}
+	return domain.RecurringTemplate{}, &domain.NotFoundError{Record: domain.RecordTemplate, ID: templateID}
+}
+
+// Materialize applies the templates occurring in a month that have not been
+// applied to it yet and returns how many were applied. Income templates add
+// an income; expense templates fill in the expense of their category unless
+// it has already been set. Expense templates whose category does not exist
+// in the month yet are left pending until it is added. Each template is
+// applied and marked as applied in a single unit of work.
+func (s *RecurringService) Materialize(monthKey string) (int, error) {
+	templates, err := s.repo.GetAllTemplates()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := s.repo.GetAppliedTemplates(monthKey)
+	if err != nil {
+		return 0, err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, templateID := range applied {
+		done[templateID] = true
+	}
+
+	count := 0
+	for _, template := range templates {
+		if done[template.TemplateID] || !template.OccursIn(monthKey) {
+			continue
+		}
+
+		var ok bool
+		err := atomically(s.repo, func() error {
+			var err error
+			switch template.Kind {
+			case domain.TemplateIncome:
+				ok, err = s.materializeIncome(monthKey, template)
+			case domain.TemplateExpense:
+				ok, err = s.materializeExpense(monthKey, template)
+			}
+			if err != nil || !ok {
+				return err
+			}
+			return s.repo.MarkTemplateApplied(monthKey, template.TemplateID)
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to apply recurring template '%s': %w", template.Name, err)
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// materializeIncome adds the income of a template occurrence.
+func (s *RecurringService) materializeIncome(monthKey string, template domain.RecurringTemplate) (bool, error) {
+	income := occurrenceIncome(monthKey, template)
+	if err := ValidateIncome(income); err != nil {
+		return false, err
+	}
+	if err := s.incomes.AddIncome(monthKey, income); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// materializeExpense fills in the expense of a template occurrence. It
+// reports false when the category of the template is not in the month.
+func (s *RecurringService) materializeExpense(monthKey string, template domain.RecurringTemplate) (bool, error) {
+	category, found, err := s.templateCategory(monthKey, template)
+	if err != nil || !found {
+		return false, err
+	}
+	if _, exists := category.Expense[category.CatID]; exists {
+		return true, nil
+	}
+	return true, s.setExpense(monthKey, category, occurrenceExpense(monthKey, template))
+}
+
+// templateCategory returns the category of the month named after an expense
+// template and reports whether it was found.
+func (s *RecurringService) templateCategory(monthKey string, template domain.RecurringTemplate) (domain.Category, bool, error) {
+	categories, err := s.categories.GetCategoriesForMonth(monthKey)
+	if err != nil {
+		return domain.Category{}, false, err
+	}
+	for _, category := range categories {
+		if strings.EqualFold(category.CategoryName, template.Name) {
+			return category, true, nil
+		}
+	}
+	return domain.Category{}, false, nil
+}
+
+// setExpense checks expense and saves it as the expense of category.
+func (s *RecurringService) setExpense(monthKey string, category domain.Category, expense domain.ExpenseRecord) error {
+	if err := validateExpense(expense); err != nil {
+		return err
+	}
+	expenses := make(map[string]domain.ExpenseRecord, len(category.Expense)+1)
+	for key, record := range category.Expense {
+		expenses[key] = record
+	}
+	expenses[category.CatID] = expense
+	category.Expense = expenses
+	return s.categories.UpdateCategory(monthKey, category)
+}
+
+// occurrenceIncome returns the income created by an income template in a month.
+func occurrenceIncome(monthKey string, template domain.RecurringTemplate) domain.IncomeRecord {
+	amount, _ := template.ValuesFor(monthKey)
+	return domain.IncomeRecord{
+		IncomeID:    fmt.Sprintf("%s-%s", template.TemplateID, monthKey),
+		Description: template.Name,
+		Amount:      amount,
+		Currency:    template.Currency,
+	}
+}
+
+// occurrenceExpense returns the expense set by an expense template in a month.
+func occurrenceExpense(monthKey string, template domain.RecurringTemplate) domain.ExpenseRecord {
+	amount, budget := template.ValuesFor(monthKey)
+	return domain.ExpenseRecord{
+		Amount:   amount,
+		Budget:   budget,
+		Currency: template.Currency,
+		Status:   "Not Paid",
+	}
+}
+
+// validateOccurrence checks the record a template creates in a month.
+func validateOccurrence(monthKey string, template domain.RecurringTemplate) error {
+	if template.Kind == domain.TemplateIncome {
+		return ValidateIncome(occurrenceIncome(monthKey, template))
+	}
+	return validateExpense(occurrenceExpense(monthKey, template))
+}
+
+// validateExpense checks the amounts of an expense the way they are checked
+// when its category is saved.
+func validateExpense(expense domain.ExpenseRecord) error {
+	var v validation
+	v.amount(domain.FieldAmount, "amount", expense.Amount)
+	v.amount(domain.FieldBudget, "budget", expense.Budget)
+	return v.err()
+}
+
+// validateTemplate checks a template and returns it with its fields normalized.
+func validateTemplate(template domain.RecurringTemplate) (domain.RecurringTemplate, error) {
+	template.Name = strings.TrimSpace(template.Name)
+	template.Currency = domain.NormalizeCurrency(template.Currency)
+	if template.TemplateID == "" {
+		return template, &domain.ValidationError{Field: domain.FieldID, Message: "template ID cannot be empty"}
+	}
+	if template.Kind != domain.TemplateExpense && template.Kind != domain.TemplateIncome {
+		return template, &domain.ValidationError{Field: domain.FieldKind, Message: fmt.Sprintf("unknown template kind %q", template.Kind)}
+	}
+	if template.Name == "" {
+		return template, &domain.ValidationError{Field: domain.FieldName, Message: "template name cannot be empty"}
+	}
+	if template.Interval < 1 {
+		return template, &domain.ValidationError{Field: domain.FieldInterval, Message: "template interval must be at least one month"}
+	}
+	if template.Amount.IsNegative() || template.Budget.IsNegative() {
+		return template, &domain.ValidationError{Field: domain.FieldAmount, Message: "template amounts cannot be negative"}
+	}
+	if template.Amount.GreaterThan(maxAmount) || template.Budget.GreaterThan(maxAmount) {
+		return template, &domain.ValidationError{Field: domain.FieldAmount, Message: fmt.Sprintf("template amounts cannot be more than %s", maxAmount.String())}
+	}
+	if template.Kind == domain.TemplateIncome && !template.Amount.IsPositive() {
+		return template, &domain.ValidationError{Field: domain.FieldAmount, Message: "income template amount must be greater than zero"}
+	}
+	if err := domain.ValidateMonthKey(template.StartMonth); err != nil {
+		return template, &domain.ValidationError{Field: domain.FieldStartMonth, Message: err.Error()}
+	}
+	if template.EndMonth != "" {
+		if err := domain.ValidateMonthKey(template.EndMonth); err != nil {
+			return template, &domain.ValidationError{Field: domain.FieldEndMonth, Message: err.Error()}
+		}
+		if domain.CompareMonthKeys(template.EndMonth, template.StartMonth) < 0 {
+			return template, &domain.ValidationError{Field: domain.FieldEndMonth, Message: "template end month cannot be before its start month"}
+		}
+	}
+	return template, nil
+}
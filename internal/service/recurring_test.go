@@ -0,0 +1,241 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRecurringRepo is a mock implementation of the RecurringRepository.
+type mockRecurringRepo struct {
+	templates []domain.RecurringTemplate
+	applied   map[string][]string
+}
+
+func (m *mockRecurringRepo) GetAllTemplates() ([]domain.RecurringTemplate, error) {
+	return m.templates, nil
+}
+func (m *mockRecurringRepo) AddTemplate(template domain.RecurringTemplate) error {
+	m.templates = append(m.templates, template)
+	return nil
+}
+func (m *mockRecurringRepo) UpdateTemplate(template domain.RecurringTemplate) error {
+	for i, existing := range m.templates {
+		if existing.TemplateID == template.TemplateID {
+			m.templates[i] = template
+		}
+	}
+	return nil
+}
+func (m *mockRecurringRepo) DeleteTemplate(templateID string) error {
+	_ = templateID
+	return nil
+}
+func (m *mockRecurringRepo) GetAppliedTemplates(monthKey string) ([]string, error) {
+	return m.applied[monthKey], nil
+}
+func (m *mockRecurringRepo) MarkTemplateApplied(monthKey string, templateID string) error {
+	if m.applied == nil {
+		m.applied = make(map[string][]string)
+	}
+	m.applied[monthKey] = append(m.applied[monthKey], templateID)
+	return nil
+}
+
+// mockStoringCategoryRepo is a mockCategoryRepo that keeps updated categories.
+type mockStoringCategoryRepo struct {
+	mockCategoryRepo
+}
+
+func (m *mockStoringCategoryRepo) UpdateCategory(monthKey string, category domain.Category) error {
+	_ = monthKey
+	for i, existing := range m.categories {
+		if existing.CatID == category.CatID {
+			m.categories[i] = category
+		}
+	}
+	return nil
+}
+
+// mockStoringIncomeRepo is a mockIncomeRepo that keeps updated and deleted incomes.
+type mockStoringIncomeRepo struct {
+	mockIncomeRepo
+}
+
+func (m *mockStoringIncomeRepo) UpdateIncome(monthKey string, income domain.IncomeRecord) error {
+	_ = monthKey
+	for i, existing := range m.incomes {
+		if existing.IncomeID == income.IncomeID {
+			m.incomes[i] = income
+		}
+	}
+	return nil
+}
+func (m *mockStoringIncomeRepo) DeleteIncome(monthKey string, incomeID string) error {
+	_ = monthKey
+	m.incomes = slices.DeleteFunc(m.incomes, func(income domain.IncomeRecord) bool {
+		return income.IncomeID == incomeID
+	})
+	return nil
+}
+
+func TestRecurringTemplate_OccursIn(t *testing.T) {
+	quarterly := domain.RecurringTemplate{
+		Interval:   3,
+		StartMonth: "2024-01",
+		EndMonth:   "2024-12",
+		Overrides:  map[string]domain.TemplateOverride{"2024-07": {Skip: true}},
+	}
+
+	assert.False(t, quarterly.OccursIn("2023-12"))
+	assert.True(t, quarterly.OccursIn("2024-01"))
+	assert.False(t, quarterly.OccursIn("2024-02"))
+	assert.True(t, quarterly.OccursIn("2024-04"))
+	assert.False(t, quarterly.OccursIn("2024-07"))
+	assert.True(t, quarterly.OccursIn("2024-10"))
+	assert.False(t, quarterly.OccursIn("2025-01"))
+}
+
+func TestRecurringService(t *testing.T) {
+	repo := &mockRecurringRepo{}
+	categories := &mockStoringCategoryRepo{}
+	incomes := &mockIncomeRepo{}
+	service := NewRecurringService(repo, categories, incomes)
+
+	rent := domain.RecurringTemplate{
+		TemplateID: "t1",
+		Kind:       domain.TemplateExpense,
+		Name:       "Rent",
+		Budget:     decimal.NewFromInt(1200),
+		Amount:     decimal.NewFromInt(1200),
+		Interval:   1,
+		StartMonth: "2024-01",
+	}
+	salary := domain.RecurringTemplate{
+		TemplateID: "t2",
+		Kind:       domain.TemplateIncome,
+		Name:       "Salary",
+		Amount:     decimal.NewFromInt(5000),
+		Interval:   1,
+		StartMonth: "2024-01",
+	}
+
+	t.Run("AddTemplate validates templates", func(t *testing.T) {
+		invalid := rent
+		invalid.Interval = 0
+		assert.Error(t, service.AddTemplate(invalid))
+
+		invalid = rent
+		invalid.EndMonth = "2023-12"
+		assert.Error(t, service.AddTemplate(invalid))
+
+		require.NoError(t, service.AddTemplate(rent))
+		require.NoError(t, service.AddTemplate(salary))
+	})
+
+	t.Run("Materialize waits for the category", func(t *testing.T) {
+		count, err := service.Materialize("2024-03")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		require.Len(t, incomes.incomes, 1)
+		assert.Equal(t, "5000", incomes.incomes[0].Amount.String())
+
+		categories.categories = []domain.Category{{CatID: "c1", CategoryName: "rent"}}
+		count, err = service.Materialize("2024-03")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "1200", categories.categories[0].Expense["c1"].Budget.String())
+
+		// Applied templates are not applied again
+		count, err = service.Materialize("2024-03")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Len(t, incomes.incomes, 1)
+	})
+
+	t.Run("Occurrences can be skipped or overridden", func(t *testing.T) {
+		require.NoError(t, service.SkipOccurrence("t2", "2024-04"))
+		amount := decimal.NewFromInt(1300)
+		require.NoError(t, service.OverrideOccurrence("t1", "2024-04", &amount, nil))
+
+		categories.categories = []domain.Category{{CatID: "c1", CategoryName: "Rent"}}
+		incomes.incomes = nil
+		count, err := service.Materialize("2024-04")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Empty(t, incomes.incomes)
+
+		expense := categories.categories[0].Expense["c1"]
+		assert.Equal(t, "1300", expense.Amount.String())
+		assert.Equal(t, "1200", expense.Budget.String())
+	})
+	t.Run("OverrideOccurrence validates the values", func(t *testing.T) {
+		negative := decimal.NewFromInt(-1)
+		assert.ErrorIs(t, service.OverrideOccurrence("t1", "2024-05", &negative, nil), domain.ErrInvalid)
+		tooLarge := maxAmount.Add(decimal.NewFromInt(1))
+		assert.ErrorIs(t, service.OverrideOccurrence("t1", "2024-05", nil, &tooLarge), domain.ErrInvalid)
+		zero := decimal.Zero
+		assert.ErrorIs(t, service.OverrideOccurrence("t2", "2024-05", &zero, nil), domain.ErrInvalid)
+		_, overridden := repo.templates[0].Overrides["2024-05"]
+		assert.False(t, overridden)
+	})
+}
+
+func TestRecurringService_AppliedOccurrences(t *testing.T) {
+	repo := &mockRecurringRepo{templates: []domain.RecurringTemplate{
+		{TemplateID: "t1", Kind: domain.TemplateExpense, Name: "Rent", Amount: decimal.NewFromInt(1200), Budget: decimal.NewFromInt(1200), Interval: 1, StartMonth: "2024-01"},
+		{TemplateID: "t2", Kind: domain.TemplateIncome, Name: "Salary", Amount: decimal.NewFromInt(5000), Interval: 1, StartMonth: "2024-01"},
+	}}
+	categories := &mockStoringCategoryRepo{}
+	categories.categories = []domain.Category{{CatID: "c1", CategoryName: "Rent"}}
+	incomes := &mockStoringIncomeRepo{}
+	service := NewRecurringService(repo, categories, incomes)
+
+	count, err := service.Materialize("2024-08")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	t.Run("Overriding updates the records", func(t *testing.T) {
+		amount := decimal.NewFromInt(5500)
+		require.NoError(t, service.OverrideOccurrence("t2", "2024-08", &amount, nil))
+		budget := decimal.NewFromInt(1300)
+		require.NoError(t, service.OverrideOccurrence("t1", "2024-08", nil, &budget))
+
+		require.Len(t, incomes.incomes, 1)
+		assert.Equal(t, "5500", incomes.incomes[0].Amount.String())
+		expense := categories.categories[0].Expense["c1"]
+		assert.Equal(t, "1200", expense.Amount.String())
+		assert.Equal(t, "1300", expense.Budget.String())
+	})
+
+	t.Run("Skipping removes the records", func(t *testing.T) {
+		require.NoError(t, service.SkipOccurrence("t2", "2024-08"))
+		require.NoError(t, service.SkipOccurrence("t1", "2024-08"))
+
+		count, err := service.Materialize("2024-08")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Empty(t, incomes.incomes)
+		assert.NotContains(t, categories.categories[0].Expense, "c1")
+	})
+
+	t.Run("Overriding a skipped occurrence restores its records", func(t *testing.T) {
+		amount := decimal.NewFromInt(5000)
+		require.NoError(t, service.OverrideOccurrence("t2", "2024-08", &amount, nil))
+		require.Len(t, incomes.incomes, 1)
+		assert.Equal(t, "t2-2024-08", incomes.incomes[0].IncomeID)
+	})
+
+	t.Run("Paid expenses are not skipped", func(t *testing.T) {
+		categories.categories[0].Expense = map[string]domain.ExpenseRecord{
+			"c1": {Amount: decimal.NewFromInt(1200), Status: "Paid"},
+		}
+		err := service.SkipOccurrence("t1", "2024-08")
+		assert.ErrorIs(t, err, domain.ErrInvalid)
+		assert.Equal(t, "Paid", categories.categories[0].Expense["c1"].Status)
+	})
+}
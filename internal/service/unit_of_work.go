@@ -0,0 +1,12 @@
+package service
+
+import "github.com/madalinpopa/gocost/internal/domain"
+
+// atomically runs fn as a unit of work of repo, see domain.UnitOfWork.
+// Repositories without units of work run fn directly.
+func atomically(repo any, fn func() error) error {
+	if unit, ok := repo.(domain.UnitOfWork); ok {
+		return unit.Atomically(fn)
+	}
+	return fn()
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Limits of the values accepted for records. Descriptions of entries and
+// incomes hold the payees of imported bank statements, which are longer than
+// the names typed in.
+const (
+	maxNameLength        = 50
+	maxDescriptionLength = 250
+	maxNotesLength       = 500
+)
+
+// maxAmount is the largest amount accepted, well above any real expense or
+// income, so that amounts typed with extra digits are caught.
+var maxAmount = decimal.NewFromInt(1_000_000_000)
+
+// validation collects the errors of the invalid fields of a record.
+type validation struct {
+	errs domain.ValidationErrors
+}
+
+// fail records that field is invalid, unless an error was already recorded
+// for it.
+func (v *validation) fail(field, format string, args ...any) {
+	if v.failed(field) {
+		return
+	}
+	v.errs = append(v.errs, &domain.ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// failed reports whether field is invalid.
+func (v *validation) failed(field string) bool {
+	for _, err := range v.errs {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// name checks that the name of field, described by label, is not empty and
+// not longer than maxNameLength.
+func (v *validation) name(field, label, value string) {
+	v.required(field, label, value, maxNameLength)
+}
+
+// required checks that value is not empty and not longer than limit characters.
+func (v *validation) required(field, label, value string, limit int) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, "%s cannot be empty", label)
+		return
+	}
+	v.length(field, label, value, limit)
+}
+
+// entry checks the amount and description of an expense entry.
+func (v *validation) entry(entry domain.ExpenseEntry) {
+	v.amount(domain.FieldEntries, "entry amounts", entry.Amount)
+	v.length(domain.FieldEntries, "entry descriptions", entry.Description, maxDescriptionLength)
+}
+
+// length checks that value is not longer than limit characters.
+func (v *validation) length(field, label, value string, limit int) {
+	if utf8.RuneCountInString(value) > limit {
+		v.fail(field, "%s cannot be longer than %d characters", label, limit)
+	}
+}
+
+// amount checks that value is neither negative nor above maxAmount.
+func (v *validation) amount(field, label string, value decimal.Decimal) {
+	if value.IsNegative() {
+		v.fail(field, "%s cannot be negative", label)
+	} else if value.GreaterThan(maxAmount) {
+		v.fail(field, "%s cannot be more than %s", label, maxAmount.String())
+	}
+}
+
+// err returns the errors collected, or nil when every field is valid.
+func (v *validation) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// sameName reports whether two names are the same, ignoring case and
+// surrounding spaces.
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// ValidateName checks a name of a group or category, described by label, the
+// way it is checked when the record is saved. Names used by other records
+// are not looked for.
+func ValidateName(label, name string) error {
+	var v validation
+	v.name(domain.FieldName, label, name)
+	return v.err()
+}
+
+// ValidateEntry checks an expense entry the way it is checked when the
+// expense holding it is saved.
+func ValidateEntry(entry domain.ExpenseEntry) error {
+	var v validation
+	v.entry(entry)
+	return v.err()
+}
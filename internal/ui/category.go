@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ type CategoryModel struct {
 	isEditingName bool
 	editInput     textinput.Model
 	editingIndex  int
+	nameError     string // Error shown below the name input
 
 	isFiltering        bool
 	filterInput        textinput.Model
@@ -130,6 +132,7 @@ func (m CategoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						Expense:      make(map[string]domain.ExpenseRecord, 0),
 					}
 					m.addCategory = false
+					m.nameError = ""
 					m.editInput.SetValue("")
 					m.editInput.Blur()
 					return m, func() tea.Msg {
@@ -138,6 +141,7 @@ func (m CategoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case "esc":
 				m.addCategory = false
+				m.nameError = ""
 				m.editInput.Blur()
 				m.editInput.SetValue("")
 				return m, nil
@@ -162,6 +166,7 @@ func (m CategoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					updatedCategory := m.categories[m.editingIndex]
 					updatedCategory.CategoryName = categoryName
 					m.isEditingName = false
+					m.nameError = ""
 					m.editInput.Blur()
 					return m, func() tea.Msg {
 						return CategoryUpdateMsg{MonthKey: m.MonthKey, Category: updatedCategory}
@@ -169,6 +174,7 @@ func (m CategoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case "esc":
 				m.isEditingName = false
+				m.nameError = ""
 				m.editInput.Blur()
 				m.editInput.SetValue("")
 				return m, nil
@@ -330,6 +336,29 @@ func (m CategoryModel) getGroupName(groupID string) string {
 func (m CategoryModel) AddCategory(group domain.CategoryGroup) (CategoryModel, tea.Cmd) {
 	m.addCategory = true
 	m.selectedGroup = group
+	m.nameError = ""
+	m.editInput.Focus()
+	return m, textinput.Blink
+}
+
+// ShowNameError reopens the name input with the name of category, which was
+// rejected when adding or renaming it, and shows message below the input.
+func (m CategoryModel) ShowNameError(category domain.Category, message string) (CategoryModel, tea.Cmd) {
+	m.editingIndex = slices.IndexFunc(m.categories, func(existing domain.Category) bool {
+		return existing.CatID == category.CatID
+	})
+	if m.editingIndex < 0 {
+		m.addCategory = true
+		for _, group := range m.categoryGroups {
+			if group.GroupID == category.GroupID {
+				m.selectedGroup = group
+			}
+		}
+	} else {
+		m.isEditingName = true
+	}
+	m.nameError = message
+	m.editInput.SetValue(category.CategoryName)
 	m.editInput.Focus()
 	return m, textinput.Blink
 }
@@ -345,6 +374,11 @@ func (m CategoryModel) MoveCategory(group domain.CategoryGroup) (CategoryModel,
 	}
 }
 
+// IsEditing returns true while a category name or the filter is typed in.
+func (m CategoryModel) IsEditing() bool {
+	return m.isEditingName || m.isFiltering
+}
+
 // IsMovingCategory returns true if a category is currently being moved.
 func (m CategoryModel) IsMovingCategory() bool {
 	return m.movingCategory.CatID != ""
@@ -458,6 +492,7 @@ func (m CategoryModel) resetEditingState() CategoryModel {
 	m.selectedGroup = domain.CategoryGroup{}
 	m.movingCategory = domain.Category{}
 	m.isEditingName = false
+	m.nameError = ""
 	m.editInput.Blur()
 	m.editInput.SetValue("")
 	m.editingIndex = -1
@@ -468,6 +503,7 @@ func (m CategoryModel) resetEditingState() CategoryModel {
 // focusInput activates the text input for category name editing.
 func (m CategoryModel) focusInput() (tea.Model, tea.Cmd) {
 	m.isEditingName = true
+	m.nameError = ""
 	m.editInput.Focus()
 	return m, textinput.Blink
 }
@@ -498,10 +534,12 @@ func (m CategoryModel) headerView() string {
 		b.WriteString("\n")
 		b.WriteString("Enter Category Name (Enter to save, Esc to cancel):\n")
 		b.WriteString(m.editInput.View())
+		b.WriteString(errorView(m.nameError))
 	} else if m.addCategory {
 		b.WriteString("\n")
 		b.WriteString("Enter Category Name (Enter to save, Esc to cancel):\n")
 		b.WriteString(m.editInput.View())
+		b.WriteString(errorView(m.nameError))
 	} else if m.IsMovingCategory() {
 		b.WriteString("\n")
 		b.WriteString(MutedText.Render(fmt.Sprintf("Select a new group for category '%s'", m.movingCategory.CategoryName)))
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -26,6 +27,7 @@ type CategoryGroupModel struct {
 
 	isEditingName bool            // True if currently editing a group name or adding new one
 	editInput     textinput.Model // Text input for the group name
+	nameError     string          // Error shown below the name input
 	editingIndex  int             // Index of the group being edited, -1 for new group
 
 	viewport viewport.Model
@@ -287,6 +289,7 @@ func (m CategoryGroupModel) UpdateData(groups []domain.CategoryGroup) CategoryGr
 // focusInput activates the text input for group name editing.
 func (m CategoryGroupModel) focusInput() (tea.Model, tea.Cmd) {
 	m.isEditingName = true
+	m.nameError = ""
 	m.editInput.Focus()
 	return m, textinput.Blink
 }
@@ -294,12 +297,31 @@ func (m CategoryGroupModel) focusInput() (tea.Model, tea.Cmd) {
 // blurInput deactivates the text input and resets editing state.
 func (m CategoryGroupModel) blurInput() tea.Model {
 	m.isEditingName = false
+	m.nameError = ""
 	m.editInput.Blur()
 	m.editInput.SetValue("")
 	m.editingIndex = -1
 	return m
 }
 
+// ShowNameError reopens the name input with the name of group, which was
+// rejected when adding or renaming it, and shows message below the input.
+func (m CategoryGroupModel) ShowNameError(group domain.CategoryGroup, message string) (CategoryGroupModel, tea.Cmd) {
+	m.editingIndex = slices.IndexFunc(m.groups, func(existing domain.CategoryGroup) bool {
+		return existing.GroupID == group.GroupID
+	})
+	m.isEditingName = true
+	m.nameError = message
+	m.editInput.SetValue(group.GroupName)
+	m.editInput.Focus()
+	return m, textinput.Blink
+}
+
+// IsEditing returns true while a group name is typed in.
+func (m CategoryGroupModel) IsEditing() bool {
+	return m.isEditingName
+}
+
 // SelectGroup enables group selection mode.
 func (m CategoryGroupModel) SelectGroup() CategoryGroupModel {
 	m.selectGroup = true
@@ -310,6 +332,7 @@ func (m CategoryGroupModel) SelectGroup() CategoryGroupModel {
 func (m CategoryGroupModel) resetEditingState() CategoryGroupModel {
 	m.selectGroup = false
 	m.isEditingName = false
+	m.nameError = ""
 	m.editInput.Blur()
 	m.editInput.SetValue("")
 	m.editingIndex = -1
@@ -336,6 +359,7 @@ func (m CategoryGroupModel) headerView() string {
 		b.WriteString("\n")
 		b.WriteString("Enter Category Group Name (Enter to save, Esc to cancel):\n")
 		b.WriteString(m.editInput.View())
+		b.WriteString(errorView(m.nameError))
 	}
 
 	return b.String()
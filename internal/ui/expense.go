@@ -3,36 +3,64 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/madalinpopa/gocost/internal/config"
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 )
 
 const (
 	focusAmount = iota
 	focusBudget
+	focusCurrency
 	focusNotes
+	focusEntries
 	focusSave
 	focusCancel
 	focusClear
 )
 
+const (
+	entryFocusDate = iota
+	entryFocusDescription
+	entryFocusAmount
+)
+
+// entryDateLayout is the layout used to enter and display expense entry dates.
+const entryDateLayout = "2006-01-02"
+
 type ExpenseModel struct {
 	WindowSize
 
-	amountInput textinput.Model
-	budgetInput textinput.Model
-	notesInput  textarea.Model
+	amountInput   textinput.Model
+	budgetInput   textinput.Model
+	currencyInput textinput.Model
+	notesInput    textarea.Model
 
-	focusIndex int // 0. amount, 1: budget, 2: notes, 3: Save, 4: Cancel
+	focusIndex int // 0: amount, 1: budget, 2: currency, 3: notes, 4: entries, 5: Save, 6: Cancel, 7: Clear
+
+	entries     []domain.ExpenseEntry
+	entryCursor int
+
+	isEditingEntry   bool
+	editingEntryIdx  int // -1 when adding a new entry
+	entryFocus       int
+	entryDateInput   textinput.Model
+	entryDescInput   textinput.Model
+	entryAmountInput textinput.Model
 
 	expenseCategory    domain.Category
 	existingExpense    domain.ExpenseRecord
 	monthKey           string
 	hasExistingExpense bool
+
+	fieldErrors map[string]string // Errors shown below the invalid fields, by field
 }
 
 // NewExpenseModel creates a new ExpenseModel instance for managing expense data.
@@ -49,11 +77,31 @@ func NewExpenseModel(category domain.Category, monthKey string) ExpenseModel {
 	bi.CharLimit = 10
 	bi.Width = 20
 
+	ci := textinput.New()
+	ci.Placeholder = viper.GetString(config.CurrencyField)
+	ci.CharLimit = 10
+	ci.Width = 20
+
 	ni := textarea.New()
 	ni.Placeholder = "Optional notes.."
 	ni.SetHeight(3)
 	ni.SetWidth(30)
 
+	di := textinput.New()
+	di.Placeholder = entryDateLayout
+	di.CharLimit = len(entryDateLayout)
+	di.Width = 12
+
+	ei := textinput.New()
+	ei.Placeholder = "e.g., Electricity bill"
+	ei.CharLimit = 250
+	ei.Width = 30
+
+	eai := textinput.New()
+	eai.Placeholder = "0.00"
+	eai.CharLimit = 10
+	eai.Width = 20
+
 	var expenseRecord domain.ExpenseRecord
 	var existing bool
 
@@ -64,15 +112,25 @@ func NewExpenseModel(category domain.Category, monthKey string) ExpenseModel {
 	if !existing {
 		expenseRecord = domain.ExpenseRecord{}
 	} else {
-		ai.SetValue(fmt.Sprintf("%.2f", expenseRecord.Amount))
-		bi.SetValue(fmt.Sprintf("%.2f", expenseRecord.Budget))
+		ai.SetValue(expenseRecord.Amount.StringFixed(2))
+		bi.SetValue(expenseRecord.Budget.StringFixed(2))
+		ci.SetValue(expenseRecord.Currency)
 		ni.SetValue(expenseRecord.Notes)
 	}
 
+	entries := make([]domain.ExpenseEntry, len(expenseRecord.Entries))
+	copy(entries, expenseRecord.Entries)
+
 	m := ExpenseModel{
 		amountInput:        ai,
 		budgetInput:        bi,
+		currencyInput:      ci,
 		notesInput:         ni,
+		entries:            entries,
+		editingEntryIdx:    -1,
+		entryDateInput:     di,
+		entryDescInput:     ei,
+		entryAmountInput:   eai,
 		expenseCategory:    category,
 		existingExpense:    expenseRecord,
 		monthKey:           monthKey,
@@ -84,8 +142,16 @@ func NewExpenseModel(category domain.Category, monthKey string) ExpenseModel {
 	}
 	m.amountInput.Width = m.Width - 10
 	m.budgetInput.Width = m.Width - 10
+	m.currencyInput.Width = m.Width - 10
 	m.notesInput.SetWidth(m.Width - 6)
 
+	// The amount is derived from the entries, so start on the budget instead.
+	if len(entries) > 0 {
+		m.focusIndex = focusBudget
+		m.amountInput.Blur()
+		m.budgetInput.Focus()
+	}
+
 	return m
 }
 
@@ -107,6 +173,16 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 
+		if m.isEditingEntry {
+			return m.handleEntryEditor(msg)
+		}
+
+		if m.focusIndex == focusEntries {
+			if handled, updated, entryCmd := m.handleEntriesList(msg); handled {
+				return updated, entryCmd
+			}
+		}
+
 		switch msg.String() {
 
 		case "esc":
@@ -118,35 +194,13 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "tab", "shift+tab", "up", "down":
 			// Focus traversal
-			if msg.String() == "shift+tab" || msg.String() == "up" {
-				m.focusIndex--
-			} else {
-				m.focusIndex++
-			}
-
-			maxFocus := focusCancel
-			if m.hasExistingExpense {
-				maxFocus = focusClear
-			}
-
-			if m.focusIndex > maxFocus {
-				m.focusIndex = focusAmount
-			} else if m.focusIndex < focusAmount {
-				m.focusIndex = maxFocus
-			}
-
-			// Skip focusClear if no existing expense
-			if !m.hasExistingExpense && m.focusIndex == focusClear {
-				if msg.String() == "shift+tab" || msg.String() == "up" {
-					m.focusIndex = focusCancel
-				} else {
-					m.focusIndex = focusAmount
-				}
-			}
+			backwards := msg.String() == "shift+tab" || msg.String() == "up"
+			m.focusIndex = m.nextFocus(backwards)
 
 			// Update focus on inputs
 			m.amountInput.Blur()
 			m.budgetInput.Blur()
+			m.currencyInput.Blur()
 			m.notesInput.Blur()
 
 			switch m.focusIndex {
@@ -156,6 +210,9 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case focusBudget:
 				m.budgetInput.Focus()
 				cmds = append(cmds, textinput.Blink)
+			case focusCurrency:
+				m.currencyInput.Focus()
+				cmds = append(cmds, textinput.Blink)
 			case focusNotes:
 				m.notesInput.Focus()
 				cmds = append(cmds, textarea.Blink)
@@ -164,18 +221,26 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.focusIndex == focusSave {
 				// Validate and save expense
-				amount, err := ValidAmount(m.amountInput.Value())
-				if err != nil {
-					return m, func() tea.Msg {
-						return ViewErrorMsg{
-							Text:  "Please provide a valid amount",
-							Model: m,
+				var amount decimal.Decimal
+				if len(m.entries) > 0 {
+					amount = m.entriesTotal()
+				} else {
+					var err error
+					amount, err = ValidAmount(m.amountInput.Value())
+					if err != nil {
+						m.fieldErrors = map[string]string{domain.FieldAmount: "Please provide a valid amount"}
+						return m, func() tea.Msg {
+							return ViewErrorMsg{
+								Text:  "Please provide a valid amount",
+								Model: m,
+							}
 						}
 					}
 				}
 
 				budget, err := ValidAmount(m.budgetInput.Value())
 				if err != nil {
+					m.fieldErrors = map[string]string{domain.FieldBudget: "Please provide a valid budget"}
 					return m, func() tea.Msg {
 						return ViewErrorMsg{
 							Text:  "Please provide a valid budget",
@@ -190,10 +255,12 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				expense := domain.ExpenseRecord{
-					Amount: amount,
-					Budget: budget,
-					Status: status,
-					Notes:  m.notesInput.Value(),
+					Amount:   amount,
+					Budget:   budget,
+					Currency: formCurrency(m.currencyInput.Value()),
+					Status:   status,
+					Notes:    m.notesInput.Value(),
+					Entries:  m.entries,
 				}
 
 				return m, func() tea.Msg {
@@ -226,6 +293,9 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.budgetInput.Focused() {
 				m.budgetInput, cmd = m.budgetInput.Update(msg)
 				cmds = append(cmds, cmd)
+			} else if m.currencyInput.Focused() {
+				m.currencyInput, cmd = m.currencyInput.Update(msg)
+				cmds = append(cmds, cmd)
 			} else if m.notesInput.Focused() {
 				m.notesInput, cmd = m.notesInput.Update(msg)
 				cmds = append(cmds, cmd)
@@ -238,6 +308,9 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.budgetInput.Focused() {
 				m.budgetInput, cmd = m.budgetInput.Update(msg)
 				cmds = append(cmds, cmd)
+			} else if m.currencyInput.Focused() {
+				m.currencyInput, cmd = m.currencyInput.Update(msg)
+				cmds = append(cmds, cmd)
 			} else if m.notesInput.Focused() {
 				m.notesInput, cmd = m.notesInput.Update(msg)
 				cmds = append(cmds, cmd)
@@ -248,6 +321,211 @@ func (m ExpenseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// nextFocus returns the next focusable element, skipping the amount when it is
+// derived from entries and the clear button when there is nothing to clear.
+func (m ExpenseModel) nextFocus(backwards bool) int {
+	maxFocus := focusCancel
+	if m.hasExistingExpense {
+		maxFocus = focusClear
+	}
+
+	focus := m.focusIndex
+	for {
+		if backwards {
+			focus--
+		} else {
+			focus++
+		}
+		if focus > maxFocus {
+			focus = focusAmount
+		} else if focus < focusAmount {
+			focus = maxFocus
+		}
+		if focus == focusAmount && len(m.entries) > 0 {
+			continue
+		}
+		return focus
+	}
+}
+
+// handleEntriesList processes keys while the entries list is focused. It
+// reports whether the key was handled.
+func (m ExpenseModel) handleEntriesList(msg tea.KeyMsg) (bool, ExpenseModel, tea.Cmd) {
+	switch msg.String() {
+	case "j":
+		if len(m.entries) > 0 {
+			m.entryCursor = (m.entryCursor + 1) % len(m.entries)
+		}
+		return true, m, nil
+	case "k":
+		if len(m.entries) > 0 {
+			m.entryCursor--
+			if m.entryCursor < 0 {
+				m.entryCursor = len(m.entries) - 1
+			}
+		}
+		return true, m, nil
+	case "a", "n":
+		return true, m.openEntryEditor(-1), textinput.Blink
+	case "e", "enter":
+		if len(m.entries) == 0 {
+			return true, m.openEntryEditor(-1), textinput.Blink
+		}
+		return true, m.openEntryEditor(m.entryCursor), textinput.Blink
+	case "d":
+		if len(m.entries) > 0 {
+			m.entries = append(m.entries[:m.entryCursor:m.entryCursor], m.entries[m.entryCursor+1:]...)
+			if m.entryCursor >= len(m.entries) && m.entryCursor > 0 {
+				m.entryCursor--
+			}
+			m.amountInput.SetValue(m.entriesTotal().StringFixed(2))
+		}
+		return true, m, nil
+	}
+	return false, m, nil
+}
+
+// openEntryEditor opens the entry editor for the entry at index, or for a new entry when index is -1.
+func (m ExpenseModel) openEntryEditor(index int) ExpenseModel {
+	m.isEditingEntry = true
+	m.editingEntryIdx = index
+	m.entryFocus = entryFocusDate
+
+	if index >= 0 && index < len(m.entries) {
+		entry := m.entries[index]
+		m.entryDateInput.SetValue(entry.Date.Format(entryDateLayout))
+		m.entryDescInput.SetValue(entry.Description)
+		m.entryAmountInput.SetValue(entry.Amount.StringFixed(2))
+	} else {
+		m.entryDateInput.SetValue(m.defaultEntryDate().Format(entryDateLayout))
+		m.entryDescInput.SetValue("")
+		m.entryAmountInput.SetValue("")
+	}
+
+	m.entryDateInput.Focus()
+	m.entryDescInput.Blur()
+	m.entryAmountInput.Blur()
+	return m
+}
+
+// handleEntryEditor processes keys while an entry is being added or edited.
+func (m ExpenseModel) handleEntryEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		m.isEditingEntry = false
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		if msg.String() == "shift+tab" || msg.String() == "up" {
+			m.entryFocus--
+		} else {
+			m.entryFocus++
+		}
+		if m.entryFocus > entryFocusAmount {
+			m.entryFocus = entryFocusDate
+		} else if m.entryFocus < entryFocusDate {
+			m.entryFocus = entryFocusAmount
+		}
+
+		m.entryDateInput.Blur()
+		m.entryDescInput.Blur()
+		m.entryAmountInput.Blur()
+		switch m.entryFocus {
+		case entryFocusDate:
+			m.entryDateInput.Focus()
+		case entryFocusDescription:
+			m.entryDescInput.Focus()
+		case entryFocusAmount:
+			m.entryAmountInput.Focus()
+		}
+		return m, textinput.Blink
+
+	case "enter":
+		entry, err := m.validateEntry()
+		if err != nil {
+			return m, func() tea.Msg {
+				return ViewErrorMsg{
+					Text:  err.Error(),
+					Model: m,
+				}
+			}
+		}
+
+		if m.editingEntryIdx >= 0 && m.editingEntryIdx < len(m.entries) {
+			entry.EntryID = m.entries[m.editingEntryIdx].EntryID
+			m.entries[m.editingEntryIdx] = entry
+			m.entryCursor = m.editingEntryIdx
+		} else {
+			entry.EntryID = GenerateID()
+			m.entries = append(m.entries, entry)
+			m.entryCursor = len(m.entries) - 1
+		}
+		m.isEditingEntry = false
+		m.amountInput.SetValue(m.entriesTotal().StringFixed(2))
+		return m, nil
+	}
+
+	switch m.entryFocus {
+	case entryFocusDate:
+		m.entryDateInput, cmd = m.entryDateInput.Update(msg)
+	case entryFocusDescription:
+		m.entryDescInput, cmd = m.entryDescInput.Update(msg)
+	case entryFocusAmount:
+		m.entryAmountInput, cmd = m.entryAmountInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// validateEntry builds an entry from the editor inputs, checking that its date falls within the expense month.
+func (m ExpenseModel) validateEntry() (domain.ExpenseEntry, error) {
+	date, err := time.Parse(entryDateLayout, strings.TrimSpace(m.entryDateInput.Value()))
+	if err != nil {
+		return domain.ExpenseEntry{}, fmt.Errorf("please provide a date as %s", entryDateLayout)
+	}
+
+	if month, year, err := ParseMonthKey(m.monthKey); err == nil {
+		if date.Month() != month || date.Year() != year {
+			return domain.ExpenseEntry{}, fmt.Errorf("the date must be in %s %d", month.String(), year)
+		}
+	}
+
+	amount, err := ValidAmount(m.entryAmountInput.Value())
+	if err != nil {
+		return domain.ExpenseEntry{}, fmt.Errorf("please provide a valid entry amount")
+	}
+
+	return domain.ExpenseEntry{
+		Date:        date,
+		Description: strings.TrimSpace(m.entryDescInput.Value()),
+		Amount:      amount,
+	}, nil
+}
+
+// defaultEntryDate returns today when it falls within the expense month, otherwise the first day of that month.
+func (m ExpenseModel) defaultEntryDate() time.Time {
+	now := time.Now()
+	month, year, err := ParseMonthKey(m.monthKey)
+	if err != nil || (now.Month() == month && now.Year() == year) {
+		return now
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// entriesTotal returns the sum of the amounts of the current entries.
+func (m ExpenseModel) entriesTotal() decimal.Decimal {
+	record := domain.ExpenseRecord{Entries: m.entries}
+	return record.EntriesTotal()
+}
+
+// SetFieldErrors shows errs, the messages of the fields rejected when saving
+// the expense, below the fields.
+func (m ExpenseModel) SetFieldErrors(errs map[string]string) ExpenseModel {
+	m.fieldErrors = errs
+	return m
+}
+
 // View renders the ExpenseModel as a form for editing expense details.
 func (m ExpenseModel) View() string {
 	var b strings.Builder
@@ -258,19 +536,43 @@ func (m ExpenseModel) View() string {
 
 	// Amount
 	b.WriteString("Amount: \n")
-	b.WriteString(m.amountInput.View())
+	if len(m.entries) > 0 {
+		fmt.Fprintf(&b, "%s ", m.entriesTotal().StringFixed(2))
+		b.WriteString(MutedText.Render(fmt.Sprintf("(sum of %d entries)", len(m.entries))))
+	} else {
+		b.WriteString(m.amountInput.View())
+	}
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldAmount))
 	b.WriteString("\n\n")
 
 	// Budget
 	b.WriteString("Budget: \n")
 	b.WriteString(m.budgetInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldBudget))
+	b.WriteString("\n\n")
+
+	// Currency
+	b.WriteString("Currency: \n")
+	b.WriteString(m.currencyInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldCurrency))
 	b.WriteString("\n\n")
 
 	// Notes
 	b.WriteString("Notes: \n")
 	b.WriteString(m.notesInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldNotes))
 	b.WriteString("\n\n")
 
+	// Entries
+	b.WriteString(m.entriesView())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldEntries))
+	b.WriteString("\n\n")
+
+	if m.isEditingEntry {
+		b.WriteString(m.entryEditorView())
+		b.WriteString("\n\n")
+	}
+
 	// Buttons
 	saveButton := RenderButton("Save", m.focusIndex == focusSave)
 	cancelButton := RenderButton("Cancel", m.focusIndex == focusCancel)
@@ -285,13 +587,72 @@ func (m ExpenseModel) View() string {
 	b.WriteString(buttons)
 	b.WriteString("\n\n")
 
-	helpText := "(Tab/Shift+Tab to navigate, Enter to select/save, Esc to cancel"
-	if m.hasExistingExpense {
-		helpText += ", Clear to reset"
+	var helpText string
+	switch {
+	case m.isEditingEntry:
+		helpText = "(Tab/Shift+Tab to navigate, Enter to keep the entry, Esc to discard it)"
+	case m.focusIndex == focusEntries:
+		helpText = "(j/k to select, a: Add entry, e/Enter: Edit entry, d: Delete entry, Tab to continue)"
+	default:
+		helpText = "(Tab/Shift+Tab to navigate, Enter to select/save, Esc to cancel"
+		if m.hasExistingExpense {
+			helpText += ", Clear to reset"
+		}
+		helpText += ", Status can be toggled from monthly view with 't')"
 	}
-	helpText += ", Status can be toggled from monthly view with 't')"
 	b.WriteString(MutedText.Render(helpText))
 
 	popupContent := AppStyle.Width(m.Width).Align(lipgloss.Center).Render(b.String())
 	return FocusedBorder.Render(popupContent)
 }
+
+// entriesView renders the list of expense entries.
+func (m ExpenseModel) entriesView() string {
+	var b strings.Builder
+
+	header := "Entries:"
+	if m.focusIndex == focusEntries && !m.isEditingEntry {
+		header = EmphasisStyle.Render(header)
+	}
+	b.WriteString(header)
+	b.WriteString("\n")
+
+	if len(m.entries) == 0 {
+		b.WriteString(MutedText.Render("No entries. Focus this list and press 'a' to add one."))
+		return b.String()
+	}
+
+	for i, entry := range m.entries {
+		style := NormalListItem
+		prefix := "  "
+		if m.focusIndex == focusEntries && i == m.entryCursor {
+			style = FocusedListItem
+			prefix = "> "
+		}
+		line := fmt.Sprintf("%s%s  %-20s %10s", prefix, entry.Date.Format(entryDateLayout), entry.Description, entry.Amount.StringFixed(2))
+		b.WriteString(style.Render(line))
+		if i < len(m.entries)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// entryEditorView renders the inputs used to add or edit an entry.
+func (m ExpenseModel) entryEditorView() string {
+	var b strings.Builder
+
+	title := "New Entry"
+	if m.editingEntryIdx >= 0 {
+		title = "Edit Entry"
+	}
+	b.WriteString(EmphasisStyle.Render(title))
+	b.WriteString("\n")
+	b.WriteString("Date:\n")
+	b.WriteString(m.entryDateInput.View())
+	b.WriteString("\nDescription:\n")
+	b.WriteString(m.entryDescInput.View())
+	b.WriteString("\nAmount:\n")
+	b.WriteString(m.entryAmountInput.View())
+	return b.String()
+}
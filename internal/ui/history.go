@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+// HistoryLimit is the maximum number of changes shown by the history view.
+const HistoryLimit = 200
+
+// HistoryModel lists the changes recorded in the change journal for a month
+// or for every month, optionally only those of a category.
+type HistoryModel struct {
+	WindowSize
+	MonthYear
+
+	changes  []domain.Change // Newest first
+	monthKey string
+	category string
+	cursor   int
+}
+
+// NewHistoryModel creates a new HistoryModel for monthYear.
+func NewHistoryModel(monthYear MonthYear) HistoryModel {
+	return HistoryModel{MonthYear: monthYear}
+}
+
+// Init initializes the HistoryModel.
+func (m HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the HistoryModel state.
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+
+		case "q", "esc":
+			return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+		case "j", "down":
+			if len(m.changes) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.changes)
+			}
+
+		case "k", "up":
+			if len(m.changes) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.changes)) % len(m.changes)
+			}
+
+		case "m":
+			// Switch between the current month and every month
+			monthKey := ""
+			if m.monthKey == "" {
+				monthKey = GetMonthKey(m.CurrentMonth, m.CurrentYear)
+			}
+			category := m.category
+			return m, func() tea.Msg { return HistoryViewMsg{MonthKey: monthKey, Category: category} }
+
+		case "c":
+			if m.category != "" {
+				monthKey := m.monthKey
+				return m, func() tea.Msg { return HistoryViewMsg{MonthKey: monthKey} }
+			}
+		}
+	}
+	return m, nil
+}
+
+// SetMonthYear sets the month toggled to with the m key.
+func (m HistoryModel) SetMonthYear(month time.Month, year int) HistoryModel {
+	m.CurrentMonth = month
+	m.CurrentYear = year
+	return m
+}
+
+// Filter returns the month key and category of the shown changes.
+func (m HistoryModel) Filter() (monthKey, category string) {
+	return m.monthKey, m.category
+}
+
+// UpdateData refreshes the model with the changes selected for monthKey and
+// category, where empty values stand for every month and every category.
+func (m HistoryModel) UpdateData(changes []domain.Change, monthKey, category string) HistoryModel {
+	if monthKey != m.monthKey || category != m.category {
+		m.cursor = 0
+	}
+	m.changes = changes
+	m.monthKey = monthKey
+	m.category = category
+	if m.cursor >= len(changes) {
+		m.cursor = max(len(changes)-1, 0)
+	}
+	return m
+}
+
+// View renders the HistoryModel.
+func (m HistoryModel) View() string {
+	var b strings.Builder
+
+	title := "History - all months"
+	if m.monthKey != "" {
+		if month, err := domain.ParseMonth(m.monthKey); err == nil {
+			title = fmt.Sprintf("History - %s", month.Time().Format("January 2006"))
+		}
+	}
+	if m.category != "" {
+		title += fmt.Sprintf(" - %s", m.category)
+	}
+	b.WriteString(HeaderText.Render(title))
+	b.WriteString("\n\n")
+
+	if len(m.changes) == 0 {
+		b.WriteString(MutedText.Render("No changes recorded."))
+		b.WriteString("\n\n")
+		b.WriteString(MutedText.Render(m.keyHints()))
+		return AppStyle.Render(b.String())
+	}
+
+	nameWidth := 20
+	changesWidth := 30
+	if m.Width > 0 {
+		changesWidth = max(m.Width-AppStyle.GetHorizontalFrameSize()-nameWidth-50, 10)
+	}
+
+	header := fmt.Sprintf("%-16s  %-6s  %-8s  %-7s  %-*s  %s", "Time", "Action", "Record", "Month", nameWidth, "Name", "Changes")
+	b.WriteString(MutedText.Render(header))
+	b.WriteString("\n")
+
+	// Keep the selected change visible when the list is taller than the window
+	rows := len(m.changes)
+	if m.Height > 0 {
+		rows = max(m.Height-14, 3)
+	}
+	first := 0
+	if m.cursor >= rows {
+		first = m.cursor - rows + 1
+	}
+	last := min(first+rows, len(m.changes))
+
+	for i := first; i < last; i++ {
+		change := m.changes[i]
+		month := change.MonthKey
+		if month == "" {
+			month = "-"
+		}
+		line := fmt.Sprintf("%-16s  %-6s  %-8s  %-7s  %-*s  %s",
+			change.Time.Local().Format("2006-01-02 15:04"),
+			change.Action,
+			change.Record,
+			month,
+			nameWidth, truncate(change.Name, nameWidth),
+			truncate(change.Summary(), changesWidth))
+
+		if i == m.cursor {
+			b.WriteString(FocusedListItem.Render(line))
+		} else {
+			b.WriteString(NormalListItem.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	// The full description of the selected change
+	selected := m.changes[m.cursor]
+	detail := fmt.Sprintf("%s %s %q by %s on %s",
+		strings.ToUpper(selected.Action[:1])+selected.Action[1:], selected.Record, selected.Name,
+		selected.User, selected.Time.Local().Format("2006-01-02 15:04:05"))
+	if summary := selected.Summary(); summary != "" {
+		detail += "\n" + summary
+	}
+	detailStyle := lipgloss.NewStyle()
+	if m.Width > 0 {
+		detailStyle = detailStyle.Width(max(m.Width-AppStyle.GetHorizontalFrameSize(), 20))
+	}
+	b.WriteString("\n")
+	b.WriteString(detailStyle.Render(detail))
+	b.WriteString("\n\n")
+	b.WriteString(MutedText.Render(m.keyHints()))
+
+	return AppStyle.Render(b.String())
+}
+
+// keyHints returns the key hints of the view.
+func (m HistoryModel) keyHints() string {
+	monthHint := "m: All months"
+	if m.monthKey == "" {
+		monthHint = "m: This month"
+	}
+	if m.category != "" {
+		return fmt.Sprintf("(j/k: Nav, %s, c: All categories, Esc/q: Back)", monthHint)
+	}
+	return fmt.Sprintf("(j/k: Nav, %s, Esc/q: Back)", monthHint)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+)
+
+func TestHistoryModel(t *testing.T) {
+	changes := []domain.Change{
+		{
+			Time:     time.Date(2024, 6, 3, 10, 0, 0, 0, time.Local),
+			User:     "ana",
+			Action:   domain.ChangeUpdate,
+			Record:   domain.RecordExpense,
+			MonthKey: "2024-06",
+			RecordID: "c1",
+			Name:     "Rent",
+			Before:   json.RawMessage(`{"amount":"850","status":"Not Paid"}`),
+			After:    json.RawMessage(`{"amount":"900","status":"Not Paid"}`),
+		},
+		{
+			Time:     time.Date(2024, 6, 1, 9, 0, 0, 0, time.Local),
+			User:     "ana",
+			Action:   domain.ChangeAdd,
+			Record:   domain.RecordCategory,
+			MonthKey: "2024-06",
+			RecordID: "c1",
+			Name:     "Rent",
+		},
+	}
+
+	m := NewHistoryModel(MonthYear{CurrentMonth: time.June, CurrentYear: 2024})
+	m = m.UpdateData(changes, "2024-06", "")
+
+	view := m.View()
+	if !strings.Contains(view, "History - June 2024") {
+		t.Errorf("View() does not show the month: %q", view)
+	}
+	if !strings.Contains(view, "Update expense \"Rent\" by ana") || !strings.Contains(view, "amount: 850 → 900") {
+		t.Errorf("View() does not describe the selected change: %q", view)
+	}
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = model.(HistoryModel)
+	if !strings.Contains(m.View(), "Add category \"Rent\"") {
+		t.Errorf("View() does not describe the second change after moving down")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if cmd == nil {
+		t.Fatal("Expected a command when switching months")
+	}
+	if msg, ok := cmd().(HistoryViewMsg); !ok || msg.MonthKey != "" {
+		t.Errorf("m key sent %#v; want the changes of every month", msg)
+	}
+
+	m = m.UpdateData(nil, "", "Rent")
+	if !strings.Contains(m.View(), "No changes recorded.") {
+		t.Errorf("View() does not show that there are no changes")
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if msg, ok := cmd().(HistoryViewMsg); !ok || msg.MonthKey != "2024-06" || msg.Category != "Rent" {
+		t.Errorf("m key sent %#v; want the changes of Rent in 2024-06", msg)
+	}
+}
@@ -0,0 +1,314 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shopspring/decimal"
+)
+
+// ImportItem is a transaction of a bank statement under review, together
+// with the category of the month it goes to.
+type ImportItem struct {
+	Date     time.Time
+	Payee    string
+	Amount   decimal.Decimal
+	EntryID  string
+	CatID    string // ID of the category, empty until one is picked
+	Category string // Label of the category, see ImportCategory
+	Accepted bool
+}
+
+// ImportCategory is a category of the month transactions can be imported
+// into. Names of categories are only unique within a group, so the label
+// holds both, as in "Living / Groceries".
+type ImportCategory struct {
+	CatID string
+	Label string
+}
+
+// ImportModel asks for the path of a bank statement and then lists its
+// transactions for the current month, so that each one gets a category and
+// is accepted or skipped before it is imported.
+type ImportModel struct {
+	WindowSize
+	MonthYear
+
+	pathInput  textinput.Model
+	reviewing  bool
+	path       string
+	items      []ImportItem
+	categories []ImportCategory
+	cursor     int
+}
+
+// NewImportModel creates a new ImportModel for monthYear.
+func NewImportModel(monthYear MonthYear) ImportModel {
+	pi := textinput.New()
+	pi.Placeholder = "e.g., ~/Downloads/statement.ofx"
+	pi.CharLimit = 512
+	pi.Width = 60
+
+	return ImportModel{
+		MonthYear: monthYear,
+		pathInput: pi,
+	}
+}
+
+// Init initializes the ImportModel.
+func (m ImportModel) Init() tea.Cmd {
+	return nil
+}
+
+// Open asks for the path of a statement to import into the month of monthYear.
+func (m ImportModel) Open(monthYear MonthYear) (ImportModel, tea.Cmd) {
+	m.MonthYear = monthYear
+	m.reviewing = false
+	m.items = nil
+	m.cursor = 0
+	m.pathInput.SetValue(m.path)
+	m.pathInput.CursorEnd()
+	m.pathInput.Focus()
+	return m, textinput.Blink
+}
+
+// Review lists items, the transactions of the statement at path, to be
+// assigned to categories, the categories of the month.
+func (m ImportModel) Review(path string, items []ImportItem, categories []ImportCategory) ImportModel {
+	m.path = path
+	m.items = items
+	m.categories = categories
+	m.reviewing = true
+	m.cursor = 0
+	m.pathInput.Blur()
+	return m
+}
+
+// IsEditing returns true while the path of the statement is typed in.
+func (m ImportModel) IsEditing() bool {
+	return !m.reviewing
+}
+
+// Update handles messages and updates the ImportModel state.
+func (m ImportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if !m.reviewing {
+			return m.handlePathInput(msg)
+		}
+
+		switch msg.String() {
+
+		case "q", "esc":
+			return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+		case "j", "down":
+			if len(m.items) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.items)
+			}
+
+		case "k", "up":
+			if len(m.items) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.items)) % len(m.items)
+			}
+
+		case "l", "right":
+			m = m.pickCategory(1)
+
+		case "h", "left":
+			m = m.pickCategory(-1)
+
+		case " ":
+			if len(m.items) == 0 {
+				break
+			}
+			item := &m.items[m.cursor]
+			if item.CatID == "" {
+				return m, func() tea.Msg {
+					return ViewErrorMsg{Text: "Pick a category before accepting the transaction", Model: m}
+				}
+			}
+			item.Accepted = !item.Accepted
+
+		case "s":
+			if len(m.items) > 0 {
+				m.items[m.cursor].Accepted = false
+				m.cursor = min(m.cursor+1, len(m.items)-1)
+			}
+
+		case "a":
+			for i := range m.items {
+				if m.items[i].CatID != "" {
+					m.items[i].Accepted = true
+				}
+			}
+
+		case "enter":
+			accepted := m.Accepted()
+			if len(accepted) == 0 {
+				return m, func() tea.Msg {
+					return ViewErrorMsg{Text: "No transactions accepted", Model: m}
+				}
+			}
+			monthKey := GetMonthKey(m.CurrentMonth, m.CurrentYear)
+			return m, func() tea.Msg { return ApplyImportMsg{MonthKey: monthKey, Items: accepted} }
+		}
+	}
+	return m, nil
+}
+
+// handlePathInput processes keys while the path of the statement is typed in.
+func (m ImportModel) handlePathInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+
+	case "esc":
+		return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+	case "enter":
+		path := strings.TrimSpace(m.pathInput.Value())
+		if path == "" {
+			return m, func() tea.Msg {
+				return ViewErrorMsg{Text: "Please provide the path of a statement", Model: m}
+			}
+		}
+		m.path = path
+		return m, func() tea.Msg { return LoadStatementMsg{Path: path} }
+	}
+
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	return m, cmd
+}
+
+// pickCategory moves the category of the selected item by step through the
+// categories of the month, where no category comes before the first. Picking
+// a category accepts the item, and removing it skips the item.
+func (m ImportModel) pickCategory(step int) ImportModel {
+	if len(m.items) == 0 || len(m.categories) == 0 {
+		return m
+	}
+	item := &m.items[m.cursor]
+
+	// Choice 0 is no category, and choice i the category i-1
+	choices := len(m.categories) + 1
+	choice := 0
+	for i, category := range m.categories {
+		if category.CatID == item.CatID {
+			choice = i + 1
+			break
+		}
+	}
+	choice = (choice + step + choices) % choices
+
+	if choice == 0 {
+		item.CatID, item.Category = "", ""
+		item.Accepted = false
+	} else {
+		item.CatID, item.Category = m.categories[choice-1].CatID, m.categories[choice-1].Label
+		item.Accepted = true
+	}
+	return m
+}
+
+// Accepted returns the accepted items.
+func (m ImportModel) Accepted() []ImportItem {
+	var accepted []ImportItem
+	for _, item := range m.items {
+		if item.Accepted {
+			accepted = append(accepted, item)
+		}
+	}
+	return accepted
+}
+
+// View renders the ImportModel.
+func (m ImportModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderText.Render(fmt.Sprintf("Import Statement - %s %d", m.CurrentMonth.String(), m.CurrentYear)))
+	b.WriteString("\n\n")
+
+	if !m.reviewing {
+		b.WriteString("Path of the OFX, QFX or QIF statement:\n")
+		b.WriteString(m.pathInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(MutedText.Render("(Enter to review the transactions, Esc to cancel)"))
+		return AppStyle.Render(b.String())
+	}
+
+	b.WriteString(MutedText.Render(m.path))
+	b.WriteString("\n\n")
+
+	if len(m.items) == 0 {
+		b.WriteString(MutedText.Render("No new transactions for this month."))
+		b.WriteString("\n\n")
+		b.WriteString(MutedText.Render("(Esc/q: Back)"))
+		return AppStyle.Render(b.String())
+	}
+
+	payeeWidth := 30
+	if m.Width > 0 {
+		payeeWidth = max(min(m.Width-AppStyle.GetHorizontalFrameSize()-70, 40), 10)
+	}
+
+	header := fmt.Sprintf("      %-10s  %-*s  %12s  %s", "Date", payeeWidth, "Payee", "Amount", "Category")
+	b.WriteString(MutedText.Render(header))
+	b.WriteString("\n")
+
+	// Keep the selected transaction visible when the list is taller than the window
+	rows := len(m.items)
+	if m.Height > 0 {
+		rows = max(m.Height-14, 3)
+	}
+	first := 0
+	if m.cursor >= rows {
+		first = m.cursor - rows + 1
+	}
+	last := min(first+rows, len(m.items))
+
+	for i := first; i < last; i++ {
+		item := m.items[i]
+		prefix := "  "
+		if i == m.cursor {
+			prefix = "> "
+		}
+		mark := "[ ]"
+		if item.Accepted {
+			mark = "[✓]"
+		}
+		category := item.Category
+		if item.CatID == "" {
+			category = "-"
+		}
+		line := fmt.Sprintf("%s%s %-10s  %-*s  %12s  %s", prefix, mark,
+			item.Date.Format(entryDateLayout), payeeWidth, truncate(item.Payee, payeeWidth),
+			item.Amount.StringFixed(2), truncate(category, 32))
+
+		if i == m.cursor {
+			b.WriteString(FocusedListItem.Render(line))
+		} else {
+			b.WriteString(NormalListItem.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	accepted := m.Accepted()
+	total := decimal.Zero
+	for _, item := range accepted {
+		total = total.Add(item.Amount)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%d of %d accepted, %s %s", len(accepted), len(m.items), total.StringFixed(2), recordCurrency(""))
+	b.WriteString("\n\n")
+	b.WriteString(MutedText.Render("(j/k: Nav, h/l: Category, Space: Accept, s: Skip, a: Accept all, Enter: Import, Esc/q: Back)"))
+
+	return AppStyle.Render(b.String())
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportModel_Review(t *testing.T) {
+	monthYear := MonthYear{CurrentMonth: time.June, CurrentYear: 2024}
+	model, _ := NewImportModel(monthYear).Open(monthYear)
+	assert.True(t, model.IsEditing())
+
+	day := time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC)
+	model = model.Review("statement.qif", []ImportItem{
+		{Date: day, Payee: "Tesco", Amount: decimal.NewFromInt(42), EntryID: "e1", CatID: "c1", Category: "Living / Groceries", Accepted: true},
+		{Date: day, Payee: "Cinema", Amount: decimal.NewFromInt(12), EntryID: "e2"},
+	}, []ImportCategory{
+		{CatID: "c1", Label: "Living / Groceries"},
+		{CatID: "c2", Label: "Living / Other"},
+		{CatID: "c3", Label: "Leisure / Other"},
+	})
+	assert.False(t, model.IsEditing())
+	view := model.View()
+	assert.Contains(t, view, "1 of 2 accepted")
+	assert.Contains(t, view, "Living / Groceries")
+
+	press := func(m ImportModel, key string) (ImportModel, tea.Cmd) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, cmd := m.Update(msg)
+		return updated.(ImportModel), cmd
+	}
+
+	// Transactions without a category cannot be accepted
+	model, _ = press(model, "j")
+	model, cmd := press(model, " ")
+	require.NotNil(t, cmd)
+	assert.IsType(t, ViewErrorMsg{}, cmd())
+
+	// Picking a category accepts the transaction, and going back to none skips it
+	model, _ = press(model, "h")
+	assert.Equal(t, "c3", model.items[1].CatID)
+	assert.Equal(t, "Leisure / Other", model.items[1].Category)
+	assert.True(t, model.items[1].Accepted)
+	model, _ = press(model, "l")
+	assert.Empty(t, model.items[1].CatID)
+	assert.False(t, model.items[1].Accepted)
+
+	// Categories of the same name in other groups are picked one after the other
+	for _, catID := range []string{"c1", "c2", "c3"} {
+		model, _ = press(model, "l")
+		assert.Equal(t, catID, model.items[1].CatID)
+	}
+	model, _ = press(model, "h")
+	assert.Equal(t, "c2", model.items[1].CatID)
+
+	model, _ = press(model, "k")
+	model, _ = press(model, "s")
+	assert.False(t, model.items[0].Accepted)
+
+	_, cmd = press(model, "enter")
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(ApplyImportMsg)
+	require.True(t, ok)
+	assert.Equal(t, "2024-06", msg.MonthKey)
+	require.Len(t, msg.Items, 1)
+	assert.Equal(t, "e2", msg.Items[0].EntryID)
+	assert.Equal(t, "c2", msg.Items[0].CatID)
+}
@@ -8,9 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/madalinpopa/gocost/internal/config"
 	"github.com/madalinpopa/gocost/internal/domain"
-	"github.com/spf13/viper"
 )
 
 type IncomeModel struct {
@@ -188,11 +186,11 @@ func (m IncomeModel) getIncomesContent() string {
 				lineStyle = FocusedListItem
 				prefix = "> "
 			}
-			line := fmt.Sprintf("%s%s: %.2f %s",
+			line := fmt.Sprintf("%s%s: %s %s",
 				prefix,
 				entry.Description,
-				entry.Amount,
-				viper.GetString(config.CurrencyField),
+				entry.Amount.StringFixed(2),
+				recordCurrency(entry.Currency),
 			)
 			b.WriteString(lineStyle.Render(line))
 			b.WriteString("\n")
@@ -8,12 +8,15 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/madalinpopa/gocost/internal/config"
 	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/spf13/viper"
 )
 
 const (
 	editFocusDescription = iota
 	editFocusAmount
+	editFocusCurrency
 	editFocusSave
 	editFocusCancel
 )
@@ -27,8 +30,10 @@ type IncomeFormModel struct {
 	incomeId         string
 	descriptionInput textinput.Model
 	amountInput      textinput.Model
+	currencyInput    textinput.Model
 
-	focusIndex int
+	focusIndex  int
+	fieldErrors map[string]string // Errors shown below the invalid fields, by field
 }
 
 // NewIncomeFormModel creates a new IncomeFormModel instance for adding or editing income.
@@ -39,7 +44,7 @@ func NewIncomeFormModel(currentMonth time.Month, year int, income *domain.Income
 	descInput := textinput.New()
 	descInput.Placeholder = "e.g., Salary, Freelance Project"
 	descInput.Focus()
-	descInput.CharLimit = 50
+	descInput.CharLimit = 250
 	descInput.Width = 30
 
 	amountInput := textinput.New()
@@ -47,6 +52,11 @@ func NewIncomeFormModel(currentMonth time.Month, year int, income *domain.Income
 	amountInput.CharLimit = 10
 	amountInput.Width = 20
 
+	currencyInput := textinput.New()
+	currencyInput.Placeholder = viper.GetString(config.CurrencyField)
+	currencyInput.CharLimit = 10
+	currencyInput.Width = 20
+
 	newEntry := true
 	originalEntryId := ""
 
@@ -54,7 +64,8 @@ func NewIncomeFormModel(currentMonth time.Month, year int, income *domain.Income
 		newEntry = false
 		originalEntryId = income.IncomeID
 		descInput.SetValue(income.Description)
-		amountInput.SetValue(fmt.Sprintf("%.2f", income.Amount))
+		amountInput.SetValue(income.Amount.StringFixed(2))
+		currencyInput.SetValue(income.Currency)
 	}
 
 	m := IncomeFormModel{
@@ -63,6 +74,7 @@ func NewIncomeFormModel(currentMonth time.Month, year int, income *domain.Income
 		MonthKey:         monthKey,
 		descriptionInput: descInput,
 		amountInput:      amountInput,
+		currencyInput:    currencyInput,
 		WindowSize: WindowSize{
 			Width:  50,
 			Height: 10,
@@ -110,6 +122,7 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.descriptionInput.Blur()
 			m.amountInput.Blur()
+			m.currencyInput.Blur()
 
 			switch m.focusIndex {
 			case editFocusDescription:
@@ -118,6 +131,9 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case editFocusAmount:
 				m.amountInput.Focus()
 				cmds = append(cmds, textinput.Blink)
+			case editFocusCurrency:
+				m.currencyInput.Focus()
+				cmds = append(cmds, textinput.Blink)
 			}
 
 		case "enter":
@@ -127,6 +143,7 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// amount cannot be 0 or invalid
 					amount, err := ValidAmount(m.amountInput.Value())
 					if err != nil {
+						m.fieldErrors = map[string]string{domain.FieldAmount: "Please provide a valid amount"}
 						return m, func() tea.Msg {
 							return ViewErrorMsg{
 								Text:  "Please provide a valid amount",
@@ -139,6 +156,7 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						IncomeID:    GenerateID(),
 						Description: m.descriptionInput.Value(),
 						Amount:      amount,
+						Currency:    formCurrency(m.currencyInput.Value()),
 					}
 
 					return m, func() tea.Msg {
@@ -152,6 +170,7 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// amount cannot be 0 or invalid
 					amount, err := ValidAmount(m.amountInput.Value())
 					if err != nil {
+						m.fieldErrors = map[string]string{domain.FieldAmount: "Please provide a valid amount"}
 						return m, func() tea.Msg {
 							return ViewErrorMsg{
 								Text:  "Please provide a valid amount",
@@ -161,6 +180,7 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 
 					m.IncomeRecord.Amount = amount
+					m.IncomeRecord.Currency = formCurrency(m.currencyInput.Value())
 					m.IncomeRecord.IncomeID = m.incomeId
 					m.IncomeRecord.Description = m.descriptionInput.Value()
 					return m, func() tea.Msg {
@@ -182,6 +202,9 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.amountInput.Focused() {
 				m.amountInput, cmd = m.amountInput.Update(msg)
 				cmds = append(cmds, cmd)
+			} else if m.currencyInput.Focused() {
+				m.currencyInput, cmd = m.currencyInput.Update(msg)
+				cmds = append(cmds, cmd)
 			}
 		}
 
@@ -196,13 +219,20 @@ func (m IncomeFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if (m.descriptionInput.Focused() || m.amountInput.Focused()) && !isBlinking {
+	if (m.descriptionInput.Focused() || m.amountInput.Focused() || m.currencyInput.Focused()) && !isBlinking {
 		cmds = append(cmds, textinput.Blink)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// SetFieldErrors shows errs, the messages of the fields rejected when saving
+// the income, below the fields.
+func (m IncomeFormModel) SetFieldErrors(errs map[string]string) IncomeFormModel {
+	m.fieldErrors = errs
+	return m
+}
+
 // View renders the IncomeFormModel as a form for adding or editing income.
 func (m IncomeFormModel) View() string {
 	var b strings.Builder
@@ -215,10 +245,17 @@ func (m IncomeFormModel) View() string {
 
 	b.WriteString("Description:\n")
 	b.WriteString(m.descriptionInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldDescription))
 	b.WriteString("\n\n")
 
 	b.WriteString("Amount:\n")
 	b.WriteString(m.amountInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldAmount))
+	b.WriteString("\n\n")
+
+	b.WriteString("Currency:\n")
+	b.WriteString(m.currencyInput.View())
+	b.WriteString(fieldErrorView(m.fieldErrors, domain.FieldCurrency))
 	b.WriteString("\n\n")
 
 	saveButton := RenderButton("Save", m.focusIndex == editFocusSave)
@@ -34,6 +34,7 @@ type MonthlyModel struct {
 	categories     []domain.Category
 	categoryGroups []domain.CategoryGroup
 	incomes        []domain.IncomeRecord
+	rates          []domain.ExchangeRate
 
 	groupsViewport     viewport.Model
 	categoriesViewport viewport.Model
@@ -47,6 +48,7 @@ func NewMonthlyModel(appData AppData, monthYear MonthYear) MonthlyModel {
 		categories:         appData.Categories,
 		categoryGroups:     appData.CategoryGroups,
 		incomes:            appData.Incomes,
+		rates:              appData.Rates,
 		groupsViewport:     viewport.New(80, 20),
 		categoriesViewport: viewport.New(80, 20),
 		ready:              false,
@@ -101,6 +103,10 @@ func (m MonthlyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.String() == "p" && m.currentMonthHasNoCategories() {
 			return m.handlePopulateCategories()
 		}
+		if msg.String() == "e" {
+			monthKey := GetMonthKey(m.CurrentMonth, m.CurrentYear)
+			return m, func() tea.Msg { return ExportMonthMsg{MonthKey: monthKey} }
+		}
 
 		switch m.Level {
 
@@ -134,7 +140,7 @@ func (m MonthlyModel) View() string {
 
 	var b strings.Builder
 
-	defaultCurrency := viper.GetString(config.CurrencyField)
+	defaultCurrency := config.DisplayCurrency()
 
 	var totalExpenses decimal.Decimal
 	var totalExpensesGroup map[string]decimal.Decimal
@@ -145,6 +151,10 @@ func (m MonthlyModel) View() string {
 	balance := totalIncome.Sub(totalExpenses)
 
 	header := m.getHeader(totalIncome, defaultCurrency)
+	if missing := m.missingRates(); len(missing) > 0 {
+		warning := fmt.Sprintf("Missing exchange rates for %s, left out of totals (x: Rates)", strings.Join(missing, ", "))
+		header = strings.TrimSuffix(header, "\n") + ErrorStyle.Render(warning) + "\n"
+	}
 	footer := m.getFooter(totalExpenses, balance, defaultCurrency)
 
 	if m.ready {
@@ -221,7 +231,7 @@ func (m MonthlyModel) getGroupsContent(totalGroupExpenses map[string]decimal.Dec
 		}
 		groupNameRender := groupStyle.Render(fmt.Sprintf("%s%s", groupPrefix, group.GroupName))
 		totalRender := MutedText.Render("Total:")
-		groupTotalRender := groupStyle.Render(fmt.Sprintf("%s %s %s", totalRender, groupTotal.String(), currency))
+		groupTotalRender := groupStyle.Render(fmt.Sprintf("%s %s %s", totalRender, groupTotal.StringFixed(2), currency))
 
 		groupHeaderSpacerWidth := max(m.Width-lipgloss.Width(groupNameRender)-lipgloss.Width(groupTotalRender)-AppStyle.GetHorizontalPadding(), 0)
 		groupHeader := lipgloss.JoinHorizontal(lipgloss.Left, groupNameRender, CreateSpacer(groupHeaderSpacerWidth).Render(""), groupTotalRender)
@@ -294,18 +304,22 @@ func (m MonthlyModel) getCategoriesContent(totalGroupExpenses map[string]decimal
 		budgetStr := "0.00"
 		statusStr := "Not Set"
 		notesIndicator := ""
+		expenseCurrency := currency
 
 		if hasExpense {
-			amountStr = fmt.Sprintf("%.2f", expense.Amount)
-			budgetStr = fmt.Sprintf("%.2f", expense.Budget)
+			if expense.Currency != "" {
+				expenseCurrency = expense.Currency
+			}
+			amountStr = expense.Amount.StringFixed(2)
+			budgetStr = expense.Budget.StringFixed(2)
 			statusStr = expense.Status
 			if expense.Notes != "" {
 				notesIndicator = " (N)"
 			}
 		}
 
-		amountText := fmt.Sprintf("%s %s", amountStr, currency)
-		budgetText := fmt.Sprintf("/%s %s", budgetStr, currency)
+		amountText := fmt.Sprintf("%s %s", amountStr, expenseCurrency)
+		budgetText := fmt.Sprintf("/%s %s", budgetStr, expenseCurrency)
 		statusText := fmt.Sprintf("[%s]", statusStr)
 
 		if len(amountText) > amountColWidth {
@@ -372,10 +386,14 @@ func (m MonthlyModel) getCategoriesContent(totalGroupExpenses map[string]decimal
 		budgetStr := "0.00"
 		statusStr := "Not Set"
 		notesIndicator := ""
+		expenseCurrency := currency
 
 		if hasExpense {
-			amountStr = fmt.Sprintf("%.2f", expense.Amount)
-			budgetStr = fmt.Sprintf("%.2f", expense.Budget)
+			if expense.Currency != "" {
+				expenseCurrency = expense.Currency
+			}
+			amountStr = expense.Amount.StringFixed(2)
+			budgetStr = expense.Budget.StringFixed(2)
 			statusStr = expense.Status
 			if expense.Notes != "" {
 				notesIndicator = " (N)"
@@ -384,8 +402,8 @@ func (m MonthlyModel) getCategoriesContent(totalGroupExpenses map[string]decimal
 
 		// Build category line with columns using consistent widths
 		catNameRender := catStyle.Render(fmt.Sprintf("%s%s", catPrefix, category.CategoryName))
-		amountRender := catStyle.Render(CreateRightAlignedColumn(amountColWidth).Render(fmt.Sprintf("%s %s", amountStr, currency)))
-		budgetRender := catStyle.Render(CreateRightAlignedColumn(budgetColWidth).Render(fmt.Sprintf("/%s %s", budgetStr, currency)))
+		amountRender := catStyle.Render(CreateRightAlignedColumn(amountColWidth).Render(fmt.Sprintf("%s %s", amountStr, expenseCurrency)))
+		budgetRender := catStyle.Render(CreateRightAlignedColumn(budgetColWidth).Render(fmt.Sprintf("/%s %s", budgetStr, expenseCurrency)))
 		statusRender := catStyle.Render(CreateCenterAlignedColumn(statusColWidth).Render(RenderStatusBadge(statusStr)))
 		notesRender := catStyle.Render(CreateCenterAlignedColumn(notesColWidth).Render(notesIndicator))
 
@@ -446,7 +464,7 @@ func (m MonthlyModel) getCategoryGroupHeader(totalGroupExpenses map[string]decim
 	}
 	groupNameRender := ActiveGroupStyle.Render(fmt.Sprintf(">> %s", selectedGroup.GroupName))
 	totalRender := MutedText.Render("Total:")
-	groupTotalRender := ActiveGroupStyle.Render(fmt.Sprintf("%s %s %s", totalRender, groupTotal.String(), currency))
+	groupTotalRender := ActiveGroupStyle.Render(fmt.Sprintf("%s %s %s", totalRender, groupTotal.StringFixed(2), currency))
 
 	groupHeaderSpacerWidth := max(m.Width-lipgloss.Width(groupNameRender)-lipgloss.Width(groupTotalRender)-AppStyle.GetHorizontalPadding(), 0)
 	groupHeader := lipgloss.JoinHorizontal(lipgloss.Left, groupNameRender, CreateSpacer(groupHeaderSpacerWidth).Render(""), groupTotalRender)
@@ -598,26 +616,72 @@ func (m MonthlyModel) updateViewportHeight() MonthlyModel {
 	return m
 }
 
-// getMonthIncome calculates the total income for the month.
+// converter returns the converter for the exchange rates of the month.
+func (m MonthlyModel) converter() domain.Converter {
+	return domain.NewConverter(viper.GetString(config.CurrencyField), m.rates)
+}
+
+// missingRates returns the currencies used in the month that cannot be
+// converted into the display currency.
+func (m MonthlyModel) missingRates() []string {
+	converter := m.converter()
+	display := config.DisplayCurrency()
+	seen := make(map[string]bool)
+	var missing []string
+
+	check := func(currency string) {
+		currency = converter.Currency(currency)
+		if seen[currency] {
+			return
+		}
+		seen[currency] = true
+		if _, ok := converter.Convert(decimal.Zero, currency, display); !ok {
+			missing = append(missing, currency)
+		}
+	}
+
+	for _, income := range m.incomes {
+		check(income.Currency)
+	}
+	for _, category := range m.categories {
+		for _, expense := range category.Expense {
+			check(expense.Currency)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// getMonthIncome calculates the total income for the month in the display
+// currency. Incomes without an exchange rate are left out.
 func (m MonthlyModel) getMonthIncome() decimal.Decimal {
+	converter := m.converter()
+	display := config.DisplayCurrency()
+
 	var totalIncome decimal.Decimal
 	for _, income := range m.incomes {
-		amount := decimal.NewFromFloat(income.Amount)
-		totalIncome = totalIncome.Add(amount)
+		if amount, ok := converter.Convert(income.Amount, income.Currency, display); ok {
+			totalIncome = totalIncome.Add(amount)
+		}
 	}
 	return totalIncome
 }
 
-// getMonthExpenses calculates total expenses and group totals for the month.
+// getMonthExpenses calculates total expenses and group totals for the month
+// in the display currency. Expenses without an exchange rate are left out.
 func (m MonthlyModel) getMonthExpenses() (decimal.Decimal, map[string]decimal.Decimal) {
+	converter := m.converter()
+	display := config.DisplayCurrency()
+
 	var expenseTotals decimal.Decimal
 	groupTotals := make(map[string]decimal.Decimal)
 
 	for _, category := range m.categories {
 		var categoryTotal decimal.Decimal
 		for _, expense := range category.Expense {
-			amount := decimal.NewFromFloat(expense.Amount)
-			categoryTotal = categoryTotal.Add(amount)
+			if amount, ok := converter.Convert(expense.Amount, expense.Currency, display); ok {
+				categoryTotal = categoryTotal.Add(amount)
+			}
 		}
 		expenseTotals = expenseTotals.Add(categoryTotal)
 		groupTotals[category.GroupID] = groupTotals[category.GroupID].Add(categoryTotal)
@@ -656,7 +720,7 @@ func (m MonthlyModel) getHeader(totalIncome decimal.Decimal, defaultCurrency str
 	b.WriteString(bottomBorder)
 	b.WriteString("\n")
 
-	income := fmt.Sprintf("Total Income: %s %s", totalIncome.String(), defaultCurrency)
+	income := fmt.Sprintf("Total Income: %s %s", totalIncome.StringFixed(2), defaultCurrency)
 	b.WriteString(MutedText.Render(income))
 	b.WriteString("\n\n")
 
@@ -682,13 +746,13 @@ func (m MonthlyModel) getFooter(totalExpenses, balance decimal.Decimal, defaultC
 
 	switch m.Level {
 	case focusLevelGroups:
-		keyHints = "j/k: Nav | Ent: Select" + populateHint + " | i: Income | c: Categories | g: Groups | h/l: Month" + resetHint
+		keyHints = "j/k: Nav | Ent: Select" + populateHint + " | i: Income | c: Categories | g: Groups | x: Rates | v: Trends | H: History | e: Export | I: Import | h/l: Month" + resetHint
 	case focusLevelCategories:
-		keyHints = "j/k: Nav | Ent: Expense | t: Toggle | Esc: Back" + populateHint + " | i: Income | c: Categories | g: Groups | h/l: Month" + resetHint
+		keyHints = "j/k: Nav | Ent: Expense | t: Toggle | Esc: Back" + populateHint + " | i: Income | c: Categories | g: Groups | x: Rates | v: Trends | H: History | e: Export | I: Import | h/l: Month" + resetHint
 	}
-	totalExpensesStr := fmt.Sprintf("Total Expenses: %s %s", totalExpenses.String(), defaultCurrency)
+	totalExpensesStr := fmt.Sprintf("Total Expenses: %s %s", totalExpenses.StringFixed(2), defaultCurrency)
 
-	balanceStr := fmt.Sprintf("Balance: %s %s", balance.String(), defaultCurrency)
+	balanceStr := fmt.Sprintf("Balance: %s %s", balance.StringFixed(2), defaultCurrency)
 	footerSummarySpacerWidth := max(m.Width-lipgloss.Width(totalExpensesStr)-lipgloss.Width(balanceStr)-AppStyle.GetHorizontalPadding(), 0)
 
 	space := CreateSpacer(footerSummarySpacerWidth).Render("")
@@ -760,6 +824,9 @@ func (m MonthlyModel) handleGroupNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.focusedCategoryIndex = 0
 			m = m.ensureCategoriesCursorVisible()
 		}
+	case "H":
+		monthKey := GetMonthKey(m.CurrentMonth, m.CurrentYear)
+		return m, func() tea.Msg { return HistoryViewMsg{MonthKey: monthKey} }
 
 	}
 	return m, nil
@@ -826,6 +893,18 @@ func (m MonthlyModel) handleCategoryNavigation(msg tea.KeyMsg) (tea.Model, tea.C
 				}
 			}
 		}
+	case "H":
+		// Show the history of the selected category
+		if numCategories > 0 && m.focusedCategoryIndex >= 0 && m.focusedCategoryIndex < numCategories {
+			selectedCategory := categoriesInGroup[m.focusedCategoryIndex]
+			monthKey := GetMonthKey(m.CurrentMonth, m.CurrentYear)
+			return m, func() tea.Msg {
+				return HistoryViewMsg{
+					MonthKey: monthKey,
+					Category: selectedCategory.CategoryName,
+				}
+			}
+		}
 	case "esc":
 		// Go back to group navigation
 		m.Level = focusLevelGroups
@@ -921,6 +1000,7 @@ func (m MonthlyModel) UpdateData(appData AppData) MonthlyModel {
 	m.categories = appData.Categories
 	m.categoryGroups = appData.CategoryGroups
 	m.incomes = appData.Incomes
+	m.rates = appData.Rates
 
 	// Reset focus indices if they're out of bounds
 	// Group categories by their GroupID
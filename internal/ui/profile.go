@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
+)
+
+// ProfileModel lists the profiles of the config file to switch between them.
+type ProfileModel struct {
+	WindowSize
+
+	profiles []config.Profile
+	active   string
+	cursor   int
+}
+
+// NewProfileModel creates a new ProfileModel.
+func NewProfileModel() ProfileModel {
+	return ProfileModel{}
+}
+
+// Init initializes the ProfileModel.
+func (m ProfileModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the ProfileModel state.
+func (m ProfileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+
+		case "q", "esc":
+			return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+		case "j", "down":
+			if len(m.profiles) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.profiles)
+			}
+
+		case "k", "up":
+			if len(m.profiles) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.profiles)) % len(m.profiles)
+			}
+
+		case "enter":
+			if len(m.profiles) > 0 {
+				name := m.profiles[m.cursor].Name
+				return m, func() tea.Msg { return SwitchProfileMsg{Name: name} }
+			}
+		}
+	}
+	return m, nil
+}
+
+// UpdateData refreshes the model with the profiles of the config file and
+// the name of the open one, which gets selected.
+func (m ProfileModel) UpdateData(profiles []config.Profile, active string) ProfileModel {
+	m.profiles = profiles
+	m.active = active
+	m.cursor = 0
+	for i, profile := range profiles {
+		if profile.Name == active {
+			m.cursor = i
+		}
+	}
+	return m
+}
+
+// View renders the ProfileModel.
+func (m ProfileModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderText.Render("Profiles"))
+	b.WriteString("\n\n")
+
+	for i, profile := range m.profiles {
+		marker := "  "
+		if profile.Name == m.active {
+			marker = "✓ "
+		}
+		dataFile := profile.DataFilename
+		if profile.Storage == config.StorageSQLite {
+			dataFile = profile.DatabaseFilename
+		}
+		line := fmt.Sprintf("%s%-16s  %-4s  %s", marker, truncate(profile.Name, 16), profile.Currency, dataFile)
+
+		if i == m.cursor {
+			b.WriteString(FocusedListItem.Render(line))
+		} else {
+			b.WriteString(NormalListItem.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(m.profiles) == 1 {
+		b.WriteString("\n")
+		b.WriteString(MutedText.Render("Add profiles to the config file to keep several budgets."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(MutedText.Render("(j/k: Nav, Enter: Switch, Esc/q: Back)"))
+
+	return AppStyle.Render(b.String())
+}
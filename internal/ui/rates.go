@@ -0,0 +1,279 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/spf13/viper"
+)
+
+const (
+	rateFocusCurrency = iota
+	rateFocusRate
+)
+
+// RatesModel lists and edits the exchange rates of a month.
+type RatesModel struct {
+	WindowSize
+	MonthYear
+
+	cursor   int
+	monthKey string
+	rates    []domain.ExchangeRate
+
+	isEditing     bool
+	isNew         bool
+	focusIndex    int
+	currencyInput textinput.Model
+	rateInput     textinput.Model
+}
+
+// NewRatesModel creates a new RatesModel instance.
+func NewRatesModel(rates []domain.ExchangeRate, monthYear MonthYear) RatesModel {
+	ci := textinput.New()
+	ci.Placeholder = "e.g., USD"
+	ci.CharLimit = 10
+	ci.Width = 20
+
+	ri := textinput.New()
+	ri.Placeholder = "1.00"
+	ri.CharLimit = 20
+	ri.Width = 20
+
+	return RatesModel{
+		rates:         rates,
+		monthKey:      GetMonthKey(monthYear.CurrentMonth, monthYear.CurrentYear),
+		MonthYear:     monthYear,
+		currencyInput: ci,
+		rateInput:     ri,
+	}
+}
+
+// Init initializes the RatesModel.
+func (m RatesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the RatesModel state.
+func (m RatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.isEditing {
+			return m.handleEditor(msg)
+		}
+
+		switch msg.String() {
+
+		case "q", "esc":
+			return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+		case "j", "down":
+			if len(m.rates) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.rates)
+			}
+
+		case "k", "up":
+			if len(m.rates) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.rates)) % len(m.rates)
+			}
+
+		case "a", "n":
+			return m.openEditor(nil)
+
+		case "e", "enter":
+			if m.cursor >= 0 && m.cursor < len(m.rates) {
+				rate := m.rates[m.cursor]
+				return m.openEditor(&rate)
+			}
+
+		case "d":
+			if m.cursor >= 0 && m.cursor < len(m.rates) {
+				rate := m.rates[m.cursor]
+				return m, func() tea.Msg {
+					return DeleteRateMsg{
+						MonthKey: m.monthKey,
+						Rate:     rate,
+					}
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// openEditor opens the editor for rate, or for a new rate when rate is nil.
+func (m RatesModel) openEditor(rate *domain.ExchangeRate) (tea.Model, tea.Cmd) {
+	m.isEditing = true
+	m.isNew = rate == nil
+	m.currencyInput.SetValue("")
+	m.rateInput.SetValue("")
+
+	if rate != nil {
+		m.currencyInput.SetValue(rate.Currency)
+		m.rateInput.SetValue(rate.Rate.String())
+		m.focusIndex = rateFocusRate
+		m.currencyInput.Blur()
+		m.rateInput.Focus()
+	} else {
+		m.focusIndex = rateFocusCurrency
+		m.currencyInput.Focus()
+		m.rateInput.Blur()
+	}
+	return m, textinput.Blink
+}
+
+// handleEditor processes keys while a rate is being added or edited.
+func (m RatesModel) handleEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+
+	case "esc":
+		m.isEditing = false
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		// A new rate needs a currency, an existing one only its value.
+		if !m.isNew {
+			return m, nil
+		}
+		if m.focusIndex == rateFocusCurrency {
+			m.focusIndex = rateFocusRate
+			m.currencyInput.Blur()
+			m.rateInput.Focus()
+		} else {
+			m.focusIndex = rateFocusCurrency
+			m.rateInput.Blur()
+			m.currencyInput.Focus()
+		}
+		return m, textinput.Blink
+
+	case "enter":
+		currency := domain.NormalizeCurrency(m.currencyInput.Value())
+		if currency == "" {
+			return m, func() tea.Msg {
+				return ViewErrorMsg{
+					Text:  "Please provide a currency",
+					Model: m,
+				}
+			}
+		}
+		if currency == domain.NormalizeCurrency(viper.GetString(config.CurrencyField)) {
+			return m, func() tea.Msg {
+				return ViewErrorMsg{
+					Text:  "The default currency always has a rate of 1",
+					Model: m,
+				}
+			}
+		}
+
+		rate, err := ValidAmount(m.rateInput.Value())
+		if err != nil || !rate.IsPositive() {
+			return m, func() tea.Msg {
+				return ViewErrorMsg{
+					Text:  "Please provide a valid exchange rate",
+					Model: m,
+				}
+			}
+		}
+
+		m.isEditing = false
+		exchangeRate := domain.ExchangeRate{Currency: currency, Rate: rate}
+		return m, func() tea.Msg {
+			return SaveRateMsg{
+				MonthKey: m.monthKey,
+				Rate:     exchangeRate,
+			}
+		}
+	}
+
+	switch m.focusIndex {
+	case rateFocusCurrency:
+		m.currencyInput, cmd = m.currencyInput.Update(msg)
+	case rateFocusRate:
+		m.rateInput, cmd = m.rateInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// View renders the RatesModel.
+func (m RatesModel) View() string {
+	base := viper.GetString(config.CurrencyField)
+
+	var b strings.Builder
+	b.WriteString(HeaderText.Render(fmt.Sprintf("Exchange Rates - %s %d", m.CurrentMonth.String(), m.CurrentYear)))
+	b.WriteString("\n\n")
+	b.WriteString(MutedText.Render(fmt.Sprintf("Value of one unit of each currency in %s. Totals are shown in %s.", base, config.DisplayCurrency())))
+	b.WriteString("\n\n")
+
+	if len(m.rates) == 0 {
+		b.WriteString(MutedText.Render("No exchange rates for this month."))
+	} else {
+		for i, rate := range m.rates {
+			lineStyle := NormalListItem
+			prefix := "  "
+			if i == m.cursor && !m.isEditing {
+				lineStyle = FocusedListItem
+				prefix = "> "
+			}
+			line := fmt.Sprintf("%s1 %s = %s %s", prefix, rate.Currency, rate.Rate.String(), base)
+			b.WriteString(lineStyle.Render(line))
+			if i < len(m.rates)-1 {
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString("\n\n")
+
+	if m.isEditing {
+		title := "Edit Rate"
+		if m.isNew {
+			title = "New Rate"
+		}
+		b.WriteString(EmphasisStyle.Render(title))
+		b.WriteString("\nCurrency:\n")
+		b.WriteString(m.currencyInput.View())
+		fmt.Fprintf(&b, "\nRate in %s:\n", base)
+		b.WriteString(m.rateInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(MutedText.Render("(Tab to navigate, Enter to save, Esc to cancel)"))
+	} else {
+		b.WriteString(MutedText.Render("(j/k: Nav, a/n: Add, e/Enter: Edit, d: Delete, Esc/q: Back)"))
+	}
+
+	return AppStyle.Render(b.String())
+}
+
+// IsEditing returns true while an exchange rate is typed in.
+func (m RatesModel) IsEditing() bool {
+	return m.isEditing
+}
+
+// SetMonthYear updates the current month/year of the rates.
+func (m RatesModel) SetMonthYear(month time.Month, year int) RatesModel {
+	m.CurrentMonth = month
+	m.CurrentYear = year
+	m.monthKey = GetMonthKey(month, year)
+	m.cursor = 0
+	return m
+}
+
+// UpdateData refreshes the model with new rates.
+func (m RatesModel) UpdateData(rates []domain.ExchangeRate) RatesModel {
+	m.rates = rates
+	if m.cursor >= len(m.rates) {
+		m.cursor = max(len(m.rates)-1, 0)
+	}
+	return m
+}
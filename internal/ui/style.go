@@ -63,6 +63,9 @@ var (
 	BoldText = lipgloss.NewStyle().
 			Bold(true)
 
+	ErrorText = lipgloss.NewStyle().
+			Foreground(ColorWarning)
+
 	// Status styles
 	StatusPaid = lipgloss.NewStyle().
 			Foreground(ColorStatusPaid).
@@ -0,0 +1,420 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultTrendMonths is the number of months shown when the trends view opens.
+	DefaultTrendMonths = 6
+	// MinTrendMonths and MaxTrendMonths bound the number of months shown.
+	MinTrendMonths = 3
+	MaxTrendMonths = 24
+	// TrendHistoryMonths is how many months before the shown ones are needed
+	// to compare each month with the same month of the previous year.
+	TrendHistoryMonths = 12
+)
+
+// sparkTicks are the blocks of a sparkline, from the lowest to the highest value.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// trendSeries is a value per month of a total, a group or a category.
+type trendSeries struct {
+	label  string
+	indent bool
+	values []decimal.Decimal
+}
+
+// TrendsModel charts income, spending and balance over the last months.
+type TrendsModel struct {
+	WindowSize
+	MonthYear
+
+	months         []domain.MonthData // Oldest first, including the year before the shown months
+	groups         []domain.CategoryGroup
+	span           int
+	cursor         int
+	showCategories bool
+	yearOverYear   bool
+}
+
+// NewTrendsModel creates a new TrendsModel ending at monthYear.
+func NewTrendsModel(monthYear MonthYear) TrendsModel {
+	return TrendsModel{
+		MonthYear: monthYear,
+		span:      DefaultTrendMonths,
+	}
+}
+
+// Init initializes the TrendsModel.
+func (m TrendsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the TrendsModel state.
+func (m TrendsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		series := m.series()
+
+		switch msg.String() {
+
+		case "q", "esc":
+			return m, func() tea.Msg { return MonthlyViewMsg{} }
+
+		case "j", "down":
+			if len(series) > 0 {
+				m.cursor = (m.cursor + 1) % len(series)
+			}
+
+		case "k", "up":
+			if len(series) > 0 {
+				m.cursor = (m.cursor - 1 + len(series)) % len(series)
+			}
+
+		case "c":
+			m.showCategories = !m.showCategories
+			m.cursor = 0
+
+		case "y":
+			m.yearOverYear = !m.yearOverYear
+
+		case "+", "=":
+			if m.span < MaxTrendMonths {
+				months := m.span + 1
+				return m, func() tea.Msg { return TrendsViewMsg{Months: months} }
+			}
+
+		case "-":
+			if m.span > MinTrendMonths {
+				months := m.span - 1
+				return m, func() tea.Msg { return TrendsViewMsg{Months: months} }
+			}
+		}
+	}
+	return m, nil
+}
+
+// Span returns the number of months shown.
+func (m TrendsModel) Span() int {
+	return m.span
+}
+
+// SetMonthYear updates the last month shown.
+func (m TrendsModel) SetMonthYear(month time.Month, year int) TrendsModel {
+	m.CurrentMonth = month
+	m.CurrentYear = year
+	return m
+}
+
+// UpdateData refreshes the model with the data of the months to chart,
+// oldest first, of which the last span months are shown.
+func (m TrendsModel) UpdateData(groups []domain.CategoryGroup, months []domain.MonthData, span int) TrendsModel {
+	m.groups = groups
+	m.months = months
+	m.span = min(max(span, MinTrendMonths), MaxTrendMonths)
+	if series := m.series(); m.cursor >= len(series) {
+		m.cursor = max(len(series)-1, 0)
+	}
+	return m
+}
+
+// series computes the charted series in the display currency: the totals,
+// then every group followed, when enabled, by its categories. Amounts
+// without an exchange rate are left out.
+func (m TrendsModel) series() []trendSeries {
+	base := viper.GetString(config.CurrencyField)
+	display := config.DisplayCurrency()
+	count := len(m.months)
+
+	income := trendSeries{label: "Income", values: make([]decimal.Decimal, count)}
+	spent := trendSeries{label: "Spent", values: make([]decimal.Decimal, count)}
+	balance := trendSeries{label: "Balance", values: make([]decimal.Decimal, count)}
+
+	known := make(map[string]bool, len(m.groups))
+	for _, group := range m.groups {
+		known[group.GroupID] = true
+	}
+
+	groupValues := make(map[string][]decimal.Decimal)
+	categoryValues := make(map[string]map[string][]decimal.Decimal) // group ID -> category name -> values
+	categoryNames := make(map[string][]string)
+
+	for i, month := range m.months {
+		converter := domain.NewConverter(base, month.Rates)
+
+		for _, record := range month.Incomes {
+			if amount, ok := converter.Convert(record.Amount, record.Currency, display); ok {
+				income.values[i] = income.values[i].Add(amount)
+			}
+		}
+
+		for _, category := range month.Categories {
+			var total decimal.Decimal
+			for _, expense := range category.Expense {
+				if amount, ok := converter.Convert(expense.Amount, expense.Currency, display); ok {
+					total = total.Add(amount)
+				}
+			}
+			spent.values[i] = spent.values[i].Add(total)
+
+			// Categories whose group no longer exists are charted as ungrouped
+			groupID := category.GroupID
+			if !known[groupID] {
+				groupID = ""
+			}
+			if _, ok := groupValues[groupID]; !ok {
+				groupValues[groupID] = make([]decimal.Decimal, count)
+				categoryValues[groupID] = make(map[string][]decimal.Decimal)
+			}
+			groupValues[groupID][i] = groupValues[groupID][i].Add(total)
+
+			// Categories are matched across months by name, IDs may differ
+			name := strings.TrimSpace(category.CategoryName)
+			key := strings.ToLower(name)
+			values, ok := categoryValues[groupID][key]
+			if !ok {
+				values = make([]decimal.Decimal, count)
+				categoryValues[groupID][key] = values
+				categoryNames[groupID] = append(categoryNames[groupID], name)
+			}
+			values[i] = values[i].Add(total)
+		}
+
+		balance.values[i] = income.values[i].Sub(spent.values[i])
+	}
+
+	series := []trendSeries{income, spent, balance}
+
+	groups := slices.Clone(m.groups)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Order < groups[j].Order
+	})
+	groupIDs := make([]string, 0, len(groups)+1)
+	groupNames := map[string]string{"": "Ungrouped"}
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.GroupID)
+		groupNames[group.GroupID] = group.GroupName
+	}
+	groupIDs = append(groupIDs, "")
+
+	for _, groupID := range groupIDs {
+		values, ok := groupValues[groupID]
+		if !ok {
+			continue
+		}
+		series = append(series, trendSeries{label: groupNames[groupID], values: values})
+		if !m.showCategories {
+			continue
+		}
+		for _, name := range categoryNames[groupID] {
+			series = append(series, trendSeries{
+				label:  name,
+				indent: true,
+				values: categoryValues[groupID][strings.ToLower(name)],
+			})
+		}
+	}
+	return series
+}
+
+// shown returns the index of the first shown month in m.months.
+func (m TrendsModel) shown() int {
+	return max(len(m.months)-m.span, 0)
+}
+
+// View renders the TrendsModel.
+func (m TrendsModel) View() string {
+	display := config.DisplayCurrency()
+	start := m.shown()
+	series := m.series()
+
+	var b strings.Builder
+	title := fmt.Sprintf("Trends - last %d months to %s %d", m.span, m.CurrentMonth.String(), m.CurrentYear)
+	b.WriteString(HeaderText.Render(title))
+	b.WriteString("\n\n")
+
+	if len(m.months) == 0 {
+		b.WriteString(MutedText.Render("No data to chart."))
+		b.WriteString("\n\n")
+		b.WriteString(MutedText.Render("(Esc/q: Back)"))
+		return AppStyle.Render(b.String())
+	}
+
+	labelWidth := 10
+	for _, s := range series {
+		labelWidth = max(labelWidth, len([]rune(s.label))+2)
+	}
+	labelWidth = min(labelWidth, 24)
+
+	b.WriteString(MutedText.Render(fmt.Sprintf("%-*s  %-*s  %12s  %12s", labelWidth, "", m.span, "", "Last", "Average")))
+	b.WriteString("\n")
+
+	// Keep the selected series visible when the list is taller than the window
+	rows := len(series)
+	if m.Height > 0 {
+		rows = max(m.Height-m.span-14, 3)
+	}
+	first := 0
+	if m.cursor >= rows {
+		first = m.cursor - rows + 1
+	}
+	last := min(first+rows, len(series))
+
+	for i := first; i < last; i++ {
+		s := series[i]
+		values := s.values[start:]
+		label := s.label
+		if s.indent {
+			label = "  " + label
+		}
+		label = truncate(label, labelWidth)
+
+		line := fmt.Sprintf("%-*s  %s  %12s  %12s",
+			labelWidth, label, sparkline(values), values[len(values)-1].StringFixed(2), average(values).StringFixed(2))
+
+		switch {
+		case i == m.cursor:
+			b.WriteString(FocusedListItem.Render(line))
+		case i < 3:
+			b.WriteString(BoldText.Render(line))
+		default:
+			b.WriteString(NormalListItem.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(series) > 0 {
+		selected := series[m.cursor]
+		b.WriteString("\n")
+		b.WriteString(EmphasisStyle.Render(fmt.Sprintf("%s (%s)", selected.label, display)))
+		b.WriteString("\n")
+		b.WriteString(m.barChart(selected))
+	}
+
+	b.WriteString("\n")
+	categoriesHint := "c: Show categories"
+	if m.showCategories {
+		categoriesHint = "c: Hide categories"
+	}
+	b.WriteString(MutedText.Render(fmt.Sprintf("(j/k: Nav, %s, y: Year over year, +/-: Months, Esc/q: Back)", categoriesHint)))
+
+	return AppStyle.Render(b.String())
+}
+
+// barChart renders a horizontal bar per shown month of s, with the change
+// from the same month of the previous year when year over year is enabled.
+func (m TrendsModel) barChart(s trendSeries) string {
+	start := m.shown()
+	values := s.values[start:]
+
+	barWidth := 40
+	if m.Width > 0 {
+		barWidth = min(max(m.Width-AppStyle.GetHorizontalFrameSize()-50, 10), 60)
+	}
+
+	var highest decimal.Decimal
+	for _, value := range values {
+		highest = decimal.Max(highest, value.Abs())
+	}
+
+	var b strings.Builder
+	for i, value := range values {
+		month := m.months[start+i]
+		length := 0
+		if highest.IsPositive() {
+			length = int(value.Abs().Div(highest).Mul(decimal.NewFromInt(int64(barWidth))).Round(0).IntPart())
+		}
+		bar := strings.Repeat("█", length)
+		if value.IsNegative() {
+			bar = StatusNotPaid.Render(bar)
+		} else {
+			bar = AccentText.Render(bar)
+		}
+
+		fmt.Fprintf(&b, "%s  %s%s %12s",
+			month.Month.Time().Format("Jan 2006"), bar, strings.Repeat(" ", barWidth-length), value.StringFixed(2))
+
+		if m.yearOverYear {
+			b.WriteString("  ")
+			previous := start + i - TrendHistoryMonths
+			if previous < 0 {
+				b.WriteString(MutedText.Render("no data a year earlier"))
+			} else {
+				b.WriteString(yearOverYearChange(s.values[previous], value))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// yearOverYearChange renders the change from previous to current.
+func yearOverYearChange(previous, current decimal.Decimal) string {
+	diff := current.Sub(previous)
+	text := fmt.Sprintf("%+.2f", diff.InexactFloat64())
+	if !previous.IsZero() {
+		percent := diff.Div(previous.Abs()).Mul(decimal.NewFromInt(100))
+		text += fmt.Sprintf(" (%+.1f%%)", percent.InexactFloat64())
+	}
+	return MutedText.Render(text + " vs last year")
+}
+
+// sparkline renders values as a line of blocks scaled between their lowest
+// and highest value.
+func sparkline(values []decimal.Decimal) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, value := range values[1:] {
+		lowest = decimal.Min(lowest, value)
+		highest = decimal.Max(highest, value)
+	}
+	spread := highest.Sub(lowest)
+	top := decimal.NewFromInt(int64(len(sparkTicks) - 1))
+
+	var b strings.Builder
+	for _, value := range values {
+		tick := 0
+		if spread.IsPositive() {
+			tick = int(value.Sub(lowest).Div(spread).Mul(top).Round(0).IntPart())
+		}
+		b.WriteRune(sparkTicks[tick])
+	}
+	return b.String()
+}
+
+// average returns the mean of values.
+func average(values []decimal.Decimal) decimal.Decimal {
+	if len(values) == 0 {
+		return decimal.Zero
+	}
+	return decimal.Sum(decimal.Zero, values...).Div(decimal.NewFromInt(int64(len(values))))
+}
+
+// truncate shortens text to width runes, marking the cut with an ellipsis.
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
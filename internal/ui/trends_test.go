@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+func TestSparkline(t *testing.T) {
+	values := func(amounts ...int64) []decimal.Decimal {
+		result := make([]decimal.Decimal, len(amounts))
+		for i, amount := range amounts {
+			result[i] = decimal.NewFromInt(amount)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		values []decimal.Decimal
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"zeros", values(0, 0, 0), "▁▁▁"},
+		{"rising", values(0, 350, 700), "▁▅█"},
+		{"flat", values(70, 70), "▁▁"},
+		{"varying", values(900, 1000, 950), "▁█▅"},
+		{"negative", values(-100, 0, 100), "▁▅█"},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("%s: sparkline() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrendsModel_Series(t *testing.T) {
+	viper.Set(config.CurrencyField, "USD")
+	t.Cleanup(viper.Reset)
+
+	expense := func(amount int64, currency string) map[string]domain.ExpenseRecord {
+		return map[string]domain.ExpenseRecord{"c": {Amount: decimal.NewFromInt(amount), Currency: currency}}
+	}
+	groups := []domain.CategoryGroup{
+		{GroupID: "g1", GroupName: "Housing", Order: 2},
+		{GroupID: "g2", GroupName: "Living", Order: 1},
+	}
+	months := []domain.MonthData{
+		{
+			Month: domain.NewMonth(2024, time.May),
+			MonthlyRecord: domain.MonthlyRecord{
+				Incomes: []domain.IncomeRecord{{Amount: decimal.NewFromInt(3000)}},
+				Categories: []domain.Category{
+					{CatID: "c1", GroupID: "g1", CategoryName: "Rent", Expense: expense(1000, "")},
+					{CatID: "c2", GroupID: "g2", CategoryName: "Food", Expense: expense(100, "EUR")},
+				},
+			},
+		},
+		{
+			Month: domain.NewMonth(2024, time.June),
+			MonthlyRecord: domain.MonthlyRecord{
+				Incomes: []domain.IncomeRecord{{Amount: decimal.NewFromInt(3000)}},
+				Rates:   []domain.ExchangeRate{{Currency: "EUR", Rate: decimal.NewFromInt(2)}},
+				Categories: []domain.Category{
+					{CatID: "c3", GroupID: "g1", CategoryName: "rent", Expense: expense(1100, "")},
+					{CatID: "c4", GroupID: "g2", CategoryName: "Food", Expense: expense(100, "EUR")},
+					{CatID: "c5", GroupID: "gone", CategoryName: "Gifts", Expense: expense(50, "")},
+				},
+			},
+		},
+	}
+
+	m := NewTrendsModel(MonthYear{CurrentMonth: time.June, CurrentYear: 2024}).UpdateData(groups, months, MinTrendMonths)
+	m.showCategories = true
+	series := m.series()
+
+	var labels []string
+	for _, s := range series {
+		labels = append(labels, strings.TrimSpace(s.label))
+	}
+	want := []string{"Income", "Spent", "Balance", "Living", "Food", "Housing", "Rent", "Ungrouped", "Gifts"}
+	if strings.Join(labels, ",") != strings.Join(want, ",") {
+		t.Fatalf("series labels = %v; want %v", labels, want)
+	}
+
+	// EUR has no rate in May and is left out of its totals
+	if got := series[1].values[0].String(); got != "1000" {
+		t.Errorf("May spent = %s; want 1000", got)
+	}
+	if got := series[1].values[1].String(); got != "1350" {
+		t.Errorf("June spent = %s; want 1350", got)
+	}
+	if got := series[2].values[1].String(); got != "1650" {
+		t.Errorf("June balance = %s; want 1650", got)
+	}
+	if got := series[6].values[1].String(); got != "1100" {
+		t.Errorf("June rent = %s; want 1100", got)
+	}
+}
@@ -13,6 +13,7 @@ type AppData struct {
 	Categories     []domain.Category
 	CategoryGroups []domain.CategoryGroup
 	Incomes        []domain.IncomeRecord
+	Rates          []domain.ExchangeRate
 }
 
 // MonthYear represents the current month and year.
@@ -35,6 +36,11 @@ type AppViews struct {
 	IncomeModel        IncomeModel
 	IncomeFormModel    IncomeFormModel
 	ExpenseModel       ExpenseModel
+	RatesModel         RatesModel
+	TrendsModel        TrendsModel
+	HistoryModel       HistoryModel
+	ProfileModel       ProfileModel
+	ImportModel        ImportModel
 }
 
 // ViewErrorMsg represents an error message and the associated model to handle the error state.
@@ -126,6 +132,56 @@ type DeleteIncomeMsg struct {
 	Income   domain.IncomeRecord
 }
 
+// RatesViewMsg is a message used to signal a view transition to the exchange rates view.
+type RatesViewMsg struct{}
+
+// SaveRateMsg represents a message used to add or replace an exchange rate for a specified month.
+type SaveRateMsg struct {
+	MonthKey string
+	Rate     domain.ExchangeRate
+}
+
+// DeleteRateMsg represents a message for deleting the exchange rate of a currency for a specific month.
+type DeleteRateMsg struct {
+	MonthKey string
+	Rate     domain.ExchangeRate
+}
+
+// TrendsViewMsg represents a message to show the trends of the last Months months.
+type TrendsViewMsg struct {
+	Months int
+}
+
+// HistoryViewMsg represents a message to show the changes recorded for a
+// month and a category. Empty values select every month or category.
+type HistoryViewMsg struct {
+	MonthKey string
+	Category string
+}
+
+// SwitchProfileMsg represents a message to open the data of another profile.
+type SwitchProfileMsg struct {
+	Name string
+}
+
+// LoadStatementMsg represents a message to read the bank statement at Path
+// and review its transactions.
+type LoadStatementMsg struct {
+	Path string
+}
+
+// ApplyImportMsg represents a message to import the accepted transactions of
+// a bank statement into the categories of a month.
+type ApplyImportMsg struct {
+	MonthKey string
+	Items    []ImportItem
+}
+
+// ExportMonthMsg represents a message to export the report of a specific month.
+type ExportMonthMsg struct {
+	MonthKey string
+}
+
 // PopulateCategoriesMsg represents a message containing keys for the current and previous month's categories.
 type PopulateCategoriesMsg struct {
 	CurrentMonthKey  string
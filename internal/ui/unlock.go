@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// unlockResultMsg reports the outcome of an unlock attempt.
+type unlockResultMsg struct {
+	err error
+}
+
+// UnlockModel asks for the passphrase of an encrypted data file until it
+// is unlocked or the user gives up.
+type UnlockModel struct {
+	WindowSize
+	filePath string
+	unlock   func(passphrase string) error
+
+	passphraseInput textinput.Model
+	unlocking       bool
+	unlocked        bool
+	errorMsg        string
+}
+
+// NewUnlockModel creates an UnlockModel for the data file at filePath.
+// unlock is called with every passphrase entered and returns an error when
+// the file cannot be opened with it.
+func NewUnlockModel(filePath string, unlock func(passphrase string) error) UnlockModel {
+	input := textinput.New()
+	input.Placeholder = "Passphrase"
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '•'
+	input.Focus()
+	input.Width = 40
+
+	return UnlockModel{
+		filePath:        filePath,
+		unlock:          unlock,
+		passphraseInput: input,
+		WindowSize: WindowSize{
+			Width:  50,
+			Height: 10,
+		},
+	}
+}
+
+// Unlocked reports whether the data file was unlocked.
+func (m UnlockModel) Unlocked() bool {
+	return m.unlocked
+}
+
+// Init initializes the UnlockModel.
+func (m UnlockModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the UnlockModel state.
+func (m UnlockModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case unlockResultMsg:
+		m.unlocking = false
+		if msg.err != nil {
+			m.errorMsg = msg.err.Error()
+			m.passphraseInput.SetValue("")
+			return m, nil
+		}
+		m.unlocked = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		if m.unlocking {
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "enter":
+			passphrase := m.passphraseInput.Value()
+			if passphrase == "" {
+				m.errorMsg = "Passphrase is required"
+				return m, nil
+			}
+			m.unlocking = true
+			m.errorMsg = ""
+			unlock := m.unlock
+			return m, func() tea.Msg {
+				return unlockResultMsg{err: unlock(passphrase)}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.passphraseInput, cmd = m.passphraseInput.Update(msg)
+	return m, cmd
+}
+
+// View renders the UnlockModel.
+func (m UnlockModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderText.Render("Unlock Data File"))
+	b.WriteString("\n\n")
+	b.WriteString(MutedText.Render(m.filePath))
+	b.WriteString("\n\n")
+
+	b.WriteString("Passphrase:\n")
+	b.WriteString(m.passphraseInput.View())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.unlocking:
+		b.WriteString(InfoStyle.Render("Unlocking..."))
+		b.WriteString("\n\n")
+	case m.errorMsg != "":
+		b.WriteString(ErrorStyle.Render(m.errorMsg))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(MutedText.Render("(Enter to unlock, Esc to quit)"))
+
+	popupContent := AppStyle.Width(m.Width).Align(lipgloss.Center).Render(b.String())
+	return FocusedBorder.Render(popupContent)
+}
@@ -1,10 +1,12 @@
 package ui
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/madalinpopa/gocost/internal/config"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/spf13/viper"
 )
 
 // GetPreviousMonth returns the year and month for the month before the given month and year.
@@ -21,12 +23,39 @@ func GetNextMonth(year int, month time.Month) (int, time.Month) {
 	return nextMonthTime.Year(), nextMonthTime.Month()
 }
 
-// GetMonthKey returns a string key in the format "Month-Year" for the given month and year.
+// GetMonthKey returns the key of the given month and year, in the "YYYY-MM" format.
 func GetMonthKey(month time.Month, year int) string {
-	return fmt.Sprintf("%s-%d", month.String(), year)
+	return domain.NewMonth(year, month).String()
+}
+
+// ParseMonthKey parses a key produced by GetMonthKey back into its month and year.
+func ParseMonthKey(monthKey string) (time.Month, int, error) {
+	month, err := domain.ParseMonth(monthKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	return month.Month, month.Year, nil
 }
 
 // GenerateID generates a unique UUID string.
 func GenerateID() string {
 	return uuid.NewString()
 }
+
+// recordCurrency returns the currency of a record, which is the default currency when unset.
+func recordCurrency(currency string) string {
+	if currency == "" {
+		return viper.GetString(config.CurrencyField)
+	}
+	return currency
+}
+
+// formCurrency normalizes a currency entered in a form. The default currency
+// is stored as empty so that records follow it.
+func formCurrency(value string) string {
+	currency := domain.NormalizeCurrency(value)
+	if currency == domain.NormalizeCurrency(viper.GetString(config.CurrencyField)) {
+		return ""
+	}
+	return currency
+}
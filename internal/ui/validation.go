@@ -2,27 +2,43 @@ package ui
 
 import (
 	"errors"
-	"strconv"
 	"strings"
+
+	"github.com/shopspring/decimal"
 )
 
-// ValidAmount validates and converts a string to a float64 amount, ensuring it's not zero.
-func ValidAmount(v string) (float64, error) {
+// fieldErrorView renders the error of field found in errs on the line below
+// its input, or nothing when the field is valid.
+func fieldErrorView(errs map[string]string, field string) string {
+	return errorView(errs[field])
+}
+
+// errorView renders message on the line below an input, or nothing when
+// message is empty.
+func errorView(message string) string {
+	if message == "" {
+		return ""
+	}
+	return "\n" + ErrorText.Render(message)
+}
+
+// ValidAmount validates and converts a string to an exact decimal amount, ensuring it's not zero.
+func ValidAmount(v string) (decimal.Decimal, error) {
 
 	if v == "" {
-		return 0, errors.New("amount cannot be empty")
+		return decimal.Zero, errors.New("amount cannot be empty")
 	}
 
 	amountStr := strings.TrimSpace(v)
 
-	floatValue, err := strconv.ParseFloat(amountStr, 64)
+	value, err := decimal.NewFromString(amountStr)
 	if err != nil {
-		return 0, err
+		return decimal.Zero, err
 	}
 
-	if floatValue == 0 {
-		return 0.0, errors.New("amount cannot be zero")
+	if value.IsZero() {
+		return decimal.Zero, errors.New("amount cannot be zero")
 	}
 
-	return floatValue, nil
+	return value, nil
 }
@@ -1,27 +1,35 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/madalinpopa/gocost/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestValidAmount(t *testing.T) {
 	tests := []struct {
 		name      string
 		input     string
-		want      float64
+		want      string
 		expectErr bool
 	}{
-		{name: "valid positive float", input: "123.45", want: 123.45, expectErr: false},
-		{name: "valid negative float", input: "-789.01", want: -789.01, expectErr: false},
-		{name: "valid positive integer as float", input: "100", want: 100.0, expectErr: false},
-		{name: "zero value", input: "0", want: 0.0, expectErr: true},
-		{name: "empty input", input: "", want: 0.0, expectErr: true},
-		{name: "non-numeric input", input: "abc", want: 0.0, expectErr: true},
-		{name: "input with spaces", input: " 42.0 ", want: 42.0, expectErr: false},
-		{name: "input with special characters", input: "$123", want: 0.0, expectErr: true},
-		{name: "large valid float", input: "123456789.123456", want: 123456789.123456, expectErr: false},
-		{name: "valid scientific notation", input: "1e6", want: 1000000.0, expectErr: false},
-		{name: "invalid scientific notation", input: "5e", want: 0.0, expectErr: true},
+		{name: "valid positive float", input: "123.45", want: "123.45", expectErr: false},
+		{name: "valid negative float", input: "-789.01", want: "-789.01", expectErr: false},
+		{name: "valid positive integer as float", input: "100", want: "100.0", expectErr: false},
+		{name: "zero value", input: "0", want: "0.0", expectErr: true},
+		{name: "empty input", input: "", want: "0.0", expectErr: true},
+		{name: "non-numeric input", input: "abc", want: "0.0", expectErr: true},
+		{name: "input with spaces", input: " 42.0 ", want: "42.0", expectErr: false},
+		{name: "input with special characters", input: "$123", want: "0.0", expectErr: true},
+		{name: "large valid float", input: "123456789.123456", want: "123456789.123456", expectErr: false},
+		{name: "valid scientific notation", input: "1e6", want: "1000000.0", expectErr: false},
+		{name: "exact decimal", input: "0.1", want: "0.1", expectErr: false},
+		{name: "invalid scientific notation", input: "5e", want: "0.0", expectErr: true},
 	}
 
 	for _, tt := range tests {
@@ -30,9 +38,37 @@ func TestValidAmount(t *testing.T) {
 			if (err != nil) != tt.expectErr {
 				t.Fatalf("ValidAmount(%q) error = %v, expectErr %v", tt.input, err, tt.expectErr)
 			}
-			if !tt.expectErr && got != tt.want {
+			if !tt.expectErr && !got.Equal(decimal.RequireFromString(tt.want)) {
 				t.Errorf("ValidAmount(%q) = %v, want %v", tt.input, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestFieldErrorView(t *testing.T) {
+	errs := map[string]string{domain.FieldAmount: "income amount must be greater than zero"}
+
+	form := NewIncomeFormModel(time.March, 2025, nil).SetFieldErrors(errs)
+	view := form.View()
+	assert.Contains(t, view, "income amount must be greater than zero")
+	assert.Less(t, strings.Index(view, "Amount:"), strings.Index(view, "income amount must be greater than zero"))
+	assert.Less(t, strings.Index(view, "income amount must be greater than zero"), strings.Index(view, "Currency:"))
+
+	assert.Empty(t, fieldErrorView(errs, domain.FieldDescription))
+}
+
+func TestCategoryGroupModel_ShowNameError(t *testing.T) {
+	groups := []domain.CategoryGroup{{GroupID: "g1", GroupName: "Housing", Order: 1}}
+	model := NewCategoryGroupModel(groups, 80, 24, MonthYear{CurrentMonth: time.March, CurrentYear: 2025})
+
+	model, _ = model.ShowNameError(domain.CategoryGroup{GroupID: "g2", GroupName: "housing"}, "a group named 'Housing' already exists")
+	assert.True(t, model.IsEditing())
+	assert.Equal(t, "housing", model.editInput.Value())
+	assert.Equal(t, -1, model.editingIndex)
+	assert.Contains(t, model.headerView(), "a group named 'Housing' already exists")
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(CategoryGroupModel)
+	assert.False(t, model.IsEditing())
+	assert.NotContains(t, model.headerView(), "already exists")
+}